@@ -9,17 +9,21 @@
 //	go run ./cmd/plan-preview --real       # render as a real failover (not dry run)
 //	go run ./cmd/plan-preview --rollback   # include example rollback configuration
 //	go run ./cmd/plan-preview --jito       # simulate jito-solana where gossip and local RPC versions differ
+//	go run ./cmd/plan-preview --schedule   # include an example epoch boundary schedule
 package main
 
 import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/sol-strategies/solana-validator-failover/internal/failover"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 )
 
 func main() {
@@ -28,13 +32,16 @@ func main() {
 	real := flag.Bool("real", false, "render as a real failover (not a dry run)")
 	withRollback := flag.Bool("rollback", false, "include example rollback configuration")
 	jito := flag.Bool("jito", false, "simulate jito-solana where gossip and local RPC versions differ")
+	withSchedule := flag.Bool("schedule", false, "include an example epoch boundary schedule")
 	flag.Parse()
 
 	activeClientVersion := "2.1.14"
 	activeClientVersionRPC := ""
 	passiveClientVersion := "2.1.14"
 	passiveClientVersionRPC := ""
+	client := "agave"
 	if *jito {
+		client = "jito"
 		activeClientVersion = "4.32768.2"
 		activeClientVersionRPC = "4.0.0-beta.2"
 		passiveClientVersion = "4.32768.2"
@@ -44,11 +51,13 @@ func main() {
 	activeNode := failover.NodeInfo{
 		Hostname:           "sol-validator-1",
 		PublicIP:           "203.0.113.10",
+		Client:             client,
 		ClientVersion:      activeClientVersion,
 		ClientVersionRPC:   activeClientVersionRPC,
 		SetIdentityCommand: "agave-validator --ledger /mnt/ledger set-identity /home/solana/passive-1-identity.json",
 		TowerFile:          "/mnt/accounts/tower/tower-1_9-456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM.bin",
 		TowerFileSizeBytes: 121856,
+		SlotDuration:       solana.SlotDuration{Duration: 452 * time.Millisecond, Source: solana.SlotDurationSourceMeasured, Samples: 30},
 		Identities: &identities.Identities{
 			Active:  &identities.Identity{KeyFile: "/home/solana/active-identity.json", PubKeyStr: "456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM"},
 			Passive: &identities.Identity{KeyFile: "/home/solana/passive-1-identity.json", PubKeyStr: "PassV1Kq8YxZd3NvQ7eLmT4bF9wR2cUjHnXsAoPiGkEy"},
@@ -58,10 +67,17 @@ func main() {
 	passiveNode := failover.NodeInfo{
 		Hostname:           "sol-validator-2",
 		PublicIP:           "203.0.113.20",
+		Client:             client,
 		ClientVersion:      passiveClientVersion,
 		ClientVersionRPC:   passiveClientVersionRPC,
 		SetIdentityCommand: "agave-validator --ledger /mnt/ledger set-identity /home/solana/active-identity.json --require-tower",
 		TowerFile:          "/mnt/accounts/tower/tower-1_9-456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM.bin",
+		VoteAccount: solana.VoteAccount{
+			VotePubkey:      "VoteQn3bJd7kXyTz2RfLmP8cW5sHgE4nUaVoBi6tKpMx",
+			NodePubkey:      "456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM",
+			AuthorizedVoter: "456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM",
+		},
+		AuthorizedVoterSource: "active identity",
 		Identities: &identities.Identities{
 			Active:  &identities.Identity{KeyFile: "/home/solana/active-identity.json", PubKeyStr: "456bAij7ryiCALQcYx4n47uUdop5d18camjTcedppAbM"},
 			Passive: &identities.Identity{KeyFile: "/home/solana/passive-2-identity.json", PubKeyStr: "PassV2Lp9ZyXe4OwR8fMuS5cG1vT3dVkInBqHjNrEaFb"},
@@ -95,7 +111,7 @@ func main() {
 		exampleRollback = hooks.RollbackConfig{
 			Enabled: true,
 			ToActive: hooks.RollbackDirectionConfig{
-				ResolvedCmd: "agave-validator --ledger /mnt/ledger set-identity /home/solana/active-identity.json --require-tower",
+				ResolvedCmd: utils.Command{Argv: []string{"agave-validator", "--ledger", "/mnt/ledger", "set-identity", "/home/solana/active-identity.json", "--require-tower"}},
 				Hooks: hooks.RollbackHooksConfig{
 					Post: hooks.Hooks{
 						{Name: "notify-rollback", Command: "curl", Args: []string{"-X", "POST", "https://hooks.slack.com/rollback"}},
@@ -103,14 +119,22 @@ func main() {
 				},
 			},
 			ToPassive: hooks.RollbackDirectionConfig{
-				ResolvedCmd: "agave-validator --ledger /mnt/ledger set-identity /home/solana/passive-2-identity.json",
+				ResolvedCmd: utils.Command{Argv: []string{"agave-validator", "--ledger", "/mnt/ledger", "set-identity", "/home/solana/passive-2-identity.json"}},
 			},
+			VerifyHandover: hooks.VerifyHandoverConfig{Enabled: true, WithinSlots: 64, Timeout: "2m"},
 		}
 	}
 
+	var exampleSchedule failover.Schedule
+	if *withSchedule {
+		exampleSchedule = failover.Schedule{AtEpochBoundary: true, AtSlot: 345_600_000, Epoch: 800}
+	}
+
 	data := failover.PlanData{
 		IsDryRun:        !*real,
 		SkipTowerSync:   *skipTower,
+		Schedule:        exampleSchedule,
+		SlotDuration:    activeNode.SlotDuration,
 		ActiveNodeInfo:  activeNode,
 		PassiveNodeInfo: passiveNode,
 		AppVersion:      "dev",
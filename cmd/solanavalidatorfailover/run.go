@@ -1,8 +1,11 @@
 package solanavalidatorfailover
 
 import (
+	"time"
+
 	"github.com/charmbracelet/log"
 	"github.com/sol-strategies/solana-validator-failover/internal/config"
+	"github.com/sol-strategies/solana-validator-failover/internal/failover"
 	"github.com/sol-strategies/solana-validator-failover/internal/validator"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +19,12 @@ var (
 	autoConfirm           bool
 	rollbackEnabled       bool
 	toPeer                string
+	pull                  bool
+	atSlot                uint64
+	atTime                string
+	atEpochBoundary       bool
+	waitForWindow         time.Duration
+	allowDelinquent       bool
 	runCmd                = &cobra.Command{
 		Use:          "run",
 		Short:        "run a failover - automatically detects what to do based on the node's role (active or passive)",
@@ -26,6 +35,11 @@ var (
 				log.Fatal("failed to load config", "err", err)
 			}
 
+			schedule, err := failover.NewSchedule(atSlot, atTime, atEpochBoundary)
+			if err != nil {
+				log.Fatal("invalid failover schedule", "err", err)
+			}
+
 			v, err := validator.NewFromConfig(&cfg.Validator)
 			if err != nil {
 				log.Fatal("failed to create validator", "err", err)
@@ -39,6 +53,10 @@ var (
 				AutoConfirm:           autoConfirm,
 				RollbackEnabled:       rollbackEnabled,
 				ToPeer:                toPeer,
+				Pull:                  pull,
+				Schedule:              schedule,
+				WaitForWindow:         waitForWindow,   // ignored when run on passive node
+				AllowDelinquent:       allowDelinquent, // ignored when run on active node
 			})
 			if err != nil {
 				log.Fatal("failed to failover", "err", err)
@@ -54,6 +72,13 @@ func init() {
 	runCmd.Flags().BoolVar(&skipTowerSync, "skip-tower-sync", false, "skip syncing the tower file from active to passive node (passive node must not have a tower file)")
 	runCmd.Flags().BoolVarP(&autoConfirm, "yes", "y", false, "automatically answer yes to all prompts")
 	runCmd.Flags().BoolVarP(&rollbackEnabled, "rollback-enabled", "r", false, "force-enable rollback regardless of the rollback.enabled config value")
-	runCmd.Flags().StringVar(&toPeer, "to-peer", "", "when run on an active node, auto-select a peer by name or IP address (skips interactive prompt)")
+	runCmd.Flags().StringVar(&toPeer, "to-peer", "", "auto-select a peer by name or IP address (skips interactive prompt) - on a passive node only used with --pull")
+	runCmd.Flags().BoolVar(&pull, "pull", false, "passive node initiates the failover: the active node listens and the passive node connects to it (set on both nodes)")
+	runCmd.Flags().Uint64Var(&atSlot, "at-slot", 0, "schedule the failover to execute at this slot (set on either or both nodes)")
+	runCmd.Flags().StringVar(&atTime, "at-time", "", "schedule the failover to execute at this RFC3339 time e.g. 2025-06-01T14:00:00Z (set on either or both nodes)")
+	runCmd.Flags().BoolVar(&atEpochBoundary, "at-epoch-boundary", false, "schedule the failover to execute at the first slot of the next epoch (set on either or both nodes)")
+	runCmd.Flags().DurationVar(&waitForWindow, "wait-for-window", 0, "when run on an active node, wait for the next leader-free window with at least this much time left in it e.g. 10m before switching - see the windows command")
+	runCmd.Flags().BoolVar(&allowDelinquent, "allow-delinquent", false, "when run on a passive node, fail over even if the active identity's vote account is delinquent - ignored when run on an active node")
+	runCmd.MarkFlagsMutuallyExclusive("at-slot", "at-time", "at-epoch-boundary", "wait-for-window")
 	rootCmd.AddCommand(runCmd)
 }
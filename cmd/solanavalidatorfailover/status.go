@@ -0,0 +1,121 @@
+package solanavalidatorfailover
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/sol-strategies/solana-validator-failover/internal/config"
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+	"github.com/sol-strategies/solana-validator-failover/internal/style"
+	"github.com/sol-strategies/solana-validator-failover/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "show this node's role, health, next leader slot, vote account and slot time estimate",
+	SilenceUsage: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.NewFromFile(configPath)
+		if err != nil {
+			log.Fatal("failed to load config", "err", err)
+		}
+
+		v, err := validator.NewFromConfig(&cfg.Validator)
+		if err != nil {
+			log.Fatal("failed to create validator", "err", err)
+		}
+
+		status, err := v.Status()
+		if err != nil {
+			log.Fatal("failed to get status", "err", err)
+		}
+
+		role := status.Role
+		switch role {
+		case constants.NodeRoleActive:
+			role = style.RenderActiveString(role, true)
+		case constants.NodeRolePassive:
+			role = style.RenderPassiveString(role, true)
+		default:
+			role = style.RenderWarningString(role)
+		}
+
+		version := status.ClientVersion
+		if status.ClientVersionRPC != "" && status.ClientVersionRPC != status.ClientVersion {
+			version = fmt.Sprintf("%s (%s)", status.ClientVersion, status.ClientVersionRPC)
+		}
+
+		nextLeaderSlot := "not on leader schedule"
+		switch {
+		case status.LeaderScheduleError != nil:
+			nextLeaderSlot = style.RenderErrorStringf("unknown: %v", status.LeaderScheduleError)
+		case status.IsOnLeaderSchedule && status.TimeToNextLeaderSlot < status.MinimumTimeToLeaderSlot:
+			nextLeaderSlot = style.RenderWarningString(fmt.Sprintf("in %s (< min_time_to_leader_slot %s)",
+				status.TimeToNextLeaderSlot.Round(time.Second), status.MinimumTimeToLeaderSlot))
+		case status.IsOnLeaderSchedule:
+			nextLeaderSlot = fmt.Sprintf("in %s", status.TimeToNextLeaderSlot.Round(time.Second))
+		}
+
+		voteAccount := "unknown"
+		authorizedVoter := "unknown"
+		if status.VoteAccount != nil {
+			voteAccount = status.VoteAccount.VotePubkey
+			if status.VoteAccount.Delinquent {
+				voteAccount += " " + style.RenderWarningString("(delinquent)")
+			}
+			authorizedVoter = fmt.Sprintf("%s (%s)", status.VoteAccount.AuthorizedVoter, status.AuthorizedVoterSource)
+		}
+		if status.VoteAccountError != nil {
+			authorizedVoter = style.RenderErrorStringf("%v", status.VoteAccountError)
+			if status.VoteAccount == nil {
+				voteAccount = authorizedVoter
+				authorizedVoter = "unknown"
+			}
+		}
+
+		peerNames := make([]string, 0, len(status.Peers))
+		for name := range status.Peers {
+			peerNames = append(peerNames, name)
+		}
+		sort.Strings(peerNames)
+		peers := ""
+		for i, name := range peerNames {
+			if i > 0 {
+				peers += "\n"
+			}
+			peers += fmt.Sprintf("%s (%s)", name, status.Peers[name].Address)
+		}
+
+		rows := [][]string{
+			{"Name", status.Hostname},
+			{"Public IP", status.PublicIP},
+			{"Role", role},
+			{"Gossip identity", status.GossipPubkey},
+			{"Active identity", status.ActivePubkey},
+			{"Passive identity", status.PassivePubkey},
+			{"Version", version},
+			{"Health", status.Health},
+			{"Slot", fmt.Sprintf("%d (epoch %d)", status.CurrentSlot, status.Epoch)},
+			{"Next leader slot", nextLeaderSlot},
+			{"Vote account", voteAccount},
+			{"Authorized voter", authorizedVoter},
+			{"Slot time", status.SlotDuration.String()},
+			{"Peers", peers},
+		}
+
+		fmt.Println(style.RenderTable(nil, rows, func(row, col int) lipgloss.Style {
+			if col == 0 {
+				return style.TableCellStyle.Align(lipgloss.Left).Foreground(style.ColorPurple)
+			}
+			return style.TableCellStyle.Align(lipgloss.Left)
+		}))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,83 @@
+package solanavalidatorfailover
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/sol-strategies/solana-validator-failover/internal/config"
+	"github.com/sol-strategies/solana-validator-failover/internal/style"
+	"github.com/sol-strategies/solana-validator-failover/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	windowsLimit       int
+	windowsMinDuration time.Duration
+	windowsCmd         = &cobra.Command{
+		Use:          "windows",
+		Short:        "list the largest leader-free windows for the active identity over the current and next epoch",
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.NewFromFile(configPath)
+			if err != nil {
+				log.Fatal("failed to load config", "err", err)
+			}
+
+			v, err := validator.NewFromConfig(&cfg.Validator)
+			if err != nil {
+				log.Fatal("failed to create validator", "err", err)
+			}
+
+			windows, err := v.LeaderFreeWindows()
+			if err != nil {
+				log.Fatal("failed to get leader-free windows", "err", err)
+			}
+
+			log.Info("leader-free windows",
+				"pubkey", windows.Pubkey,
+				"current_slot", windows.CurrentSlot,
+				"scanned_to_slot", windows.ScanEndSlot,
+				"slot_time", windows.SlotDuration,
+			)
+
+			if !windows.IsOnLeaderSchedule {
+				log.Info("active identity is not on the leader schedule - any time is leader-free")
+				return
+			}
+
+			rows := [][]string{}
+			for _, w := range windows.Largest(windowsLimit) {
+				if w.Duration() < windowsMinDuration {
+					continue
+				}
+				rows = append(rows, []string{
+					strconv.FormatUint(w.StartSlot, 10),
+					strconv.FormatUint(w.EndSlot, 10),
+					strconv.FormatUint(w.Slots(), 10),
+					w.StartTime.Local().Format(time.DateTime),
+					w.EndTime.Local().Format(time.DateTime),
+					w.Duration().Round(time.Second).String(),
+				})
+			}
+
+			if len(rows) == 0 {
+				log.Warnf("no leader-free windows of at least %s found", windowsMinDuration)
+				return
+			}
+
+			fmt.Println(style.RenderTable(
+				[]string{"Start slot", "End slot", "Slots", "Starts (est.)", "Ends (est.)", "Duration (est.)"},
+				rows,
+				nil,
+			))
+		},
+	}
+)
+
+func init() {
+	windowsCmd.Flags().IntVarP(&windowsLimit, "limit", "n", 10, "number of windows to list, largest first")
+	windowsCmd.Flags().DurationVar(&windowsMinDuration, "min-duration", 0, "only list windows at least this long e.g. 10m")
+	rootCmd.AddCommand(windowsCmd)
+}
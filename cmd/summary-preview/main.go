@@ -17,6 +17,7 @@ import (
 
 	"github.com/sol-strategies/solana-validator-failover/internal/failover"
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 )
 
@@ -24,6 +25,7 @@ func main() {
 	isDryRun := flag.Bool("dry-run", false, "render as a dry run")
 	skipTower := flag.Bool("skip-tower", false, "skip tower file sync step")
 	withCredits := flag.Bool("credits", false, "include vote credit rank data")
+	withOutputs := flag.Bool("outputs", false, "include hook outputs")
 	flag.Parse()
 
 	// Mirror the mock nodes from plan-preview so the two tools stay consistent.
@@ -50,6 +52,11 @@ func main() {
 		},
 	}
 
+	if *withOutputs {
+		origActiveNode.HookOutputs = map[string]map[string]string{"open-ticket": {"ticket": "OPS-1234"}}
+		origPassiveNode.HookOutputs = map[string]map[string]string{"drain-lb": {"backend": "pool-2", "drained": "3"}}
+	}
+
 	data := failover.SummaryData{
 		IsDryRun:      *isDryRun,
 		SkipTowerSync: *skipTower,
@@ -64,8 +71,20 @@ func main() {
 		TotalDuration:                  445 * time.Millisecond,
 
 		FailoverStartSlot: 300_000_042,
-		FailoverEndSlot:   300_000_044,
-		SlotsDuration:     2,
+		FailoverStartSlotDetection: solana.SlotTransition{
+			Slot:              300_000_042,
+			Source:            solana.SlotSourceSlotsUpdates,
+			DetectionLag:      3 * time.Millisecond,
+			DetectionLagKnown: true,
+		},
+		FailoverEndSlot: 300_000_044,
+		SlotsDuration:   2,
+	}
+
+	// dry runs don't change identity so there is nothing to verify
+	if !*isDryRun {
+		data.OrigActiveIdentityConfirmedDuration = 260 * time.Millisecond
+		data.OrigPassiveIdentityConfirmedDuration = 190 * time.Millisecond
 	}
 
 	if *withCredits {
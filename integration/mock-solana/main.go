@@ -128,6 +128,11 @@ func (s *MockSolanaServer) handleRPC(w http.ResponseWriter, r *http.Request) {
 		// Return an empty schedule — the validator is not a leader this epoch,
 		// so the failover tool proceeds without waiting for a leader slot gap.
 		result = map[string]any{}
+	case "getRecentPerformanceSamples":
+		// 400ms slots, matching the mock's slot counter
+		result = []map[string]any{
+			{"slot": s.slotCounter.Load(), "numSlots": 150, "numTransactions": 0, "samplePeriodSecs": 60},
+		}
 	case "getVoteAccounts":
 		result = s.getVoteAccounts()
 	default:
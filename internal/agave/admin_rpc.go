@@ -0,0 +1,152 @@
+package agave
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// AdminRPCSocketName is the name of the admin rpc unix socket agave creates in its ledger dir
+	AdminRPCSocketName = "admin.rpc"
+	// defaultAdminRPCTimeout bounds a single admin rpc call when ctx has no earlier deadline
+	defaultAdminRPCTimeout = 10 * time.Second
+)
+
+// ErrAdminRPCUnavailable is returned (wrapped) when the admin rpc socket cannot be connected to,
+// as opposed to the validator rejecting a request
+var ErrAdminRPCUnavailable = errors.New("admin rpc unavailable")
+
+// AdminRPCClient talks JSON-RPC to an agave validator over its admin.rpc unix socket - the same
+// socket the agave-validator CLI uses - without starting a process
+type AdminRPCClient struct {
+	socketPath string
+	timeout    time.Duration
+	nextID     atomic.Uint64
+}
+
+// ContactInfo is the subset of the admin rpc contactInfo result used here
+type ContactInfo struct {
+	ID           string `json:"id"` // the validator's current identity pubkey
+	Gossip       string `json:"gossip"`
+	ShredVersion uint16 `json:"shred_version"`
+}
+
+// NewAdminRPCClient returns a client for the admin rpc socket in the given ledger dir
+func NewAdminRPCClient(ledgerDir string) *AdminRPCClient {
+	return &AdminRPCClient{
+		socketPath: filepath.Join(ledgerDir, AdminRPCSocketName),
+		timeout:    defaultAdminRPCTimeout,
+	}
+}
+
+// SocketPath returns the path of the admin rpc socket
+func (c *AdminRPCClient) SocketPath() string {
+	return c.socketPath
+}
+
+// SetIdentity switches the validator to the identity in the given keypair file, which the
+// validator process reads. With requireTower the validator refuses to switch without a tower
+// file for the new identity.
+func (c *AdminRPCClient) SetIdentity(ctx context.Context, keypairFile string, requireTower bool) error {
+	return c.call(ctx, "setIdentity", []any{keypairFile, requireTower}, nil)
+}
+
+// SetIdentityFromBytes switches the validator to the given 64-byte keypair, so the validator
+// never reads the keypair from disk
+func (c *AdminRPCClient) SetIdentityFromBytes(ctx context.Context, keypair []byte, requireTower bool) error {
+	// agave expects a JSON array of numbers - encoding/json would base64 a []byte
+	ints := make([]int, len(keypair))
+	for i, b := range keypair {
+		ints[i] = int(b)
+	}
+	return c.call(ctx, "setIdentityFromBytes", []any{ints, requireTower}, nil)
+}
+
+// ContactInfo returns the validator's contact info, including its current identity
+func (c *AdminRPCClient) ContactInfo(ctx context.Context) (*ContactInfo, error) {
+	var info ContactInfo
+	if err := c.call(ctx, "contactInfo", []any{}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// rpcRequest is a JSON-RPC 2.0 request
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// RPCError is an error returned by the validator for an admin rpc request
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements error
+func (e *RPCError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("%s (code %d): %s", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// call sends a single request on a fresh connection and decodes its result into result (if
+// non-nil). Each call dials anew, as the agave CLI does, so a restarted validator is picked up.
+func (c *AdminRPCClient) call(ctx context.Context, method string, params []any, result any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", c.socketPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAdminRPCUnavailable, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set admin rpc deadline: %w", err)
+		}
+	}
+
+	request := rpcRequest{JSONRPC: "2.0", ID: c.nextID.Add(1), Method: method, Params: params}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return fmt.Errorf("failed to send admin rpc %s request: %w", method, err)
+	}
+
+	var response rpcResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return fmt.Errorf("failed to read admin rpc %s response: %w", method, err)
+	}
+	if response.ID != request.ID {
+		return fmt.Errorf("admin rpc %s response id %d does not match request id %d", method, response.ID, request.ID)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("admin rpc %s failed: %w", method, response.Error)
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to decode admin rpc %s result: %w", method, err)
+		}
+	}
+	return nil
+}
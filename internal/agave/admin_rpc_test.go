@@ -0,0 +1,136 @@
+package agave
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adminRPCStandIn serves JSON-RPC on an admin.rpc unix socket in a temp ledger dir, answering each
+// request with handle and recording the requests it received
+type adminRPCStandIn struct {
+	ledgerDir string
+	requests  chan rpcRequest
+}
+
+func newAdminRPCStandIn(t *testing.T, handle func(req rpcRequest) (result any, rpcErr *RPCError)) *adminRPCStandIn {
+	t.Helper()
+	// unix socket paths are limited to ~100 bytes, so avoid t.TempDir's long paths
+	ledgerDir, err := os.MkdirTemp("", "ledger")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(ledgerDir) })
+
+	listener, err := net.Listen("unix", filepath.Join(ledgerDir, AdminRPCSocketName))
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	s := &adminRPCStandIn{ledgerDir: ledgerDir, requests: make(chan rpcRequest, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var req rpcRequest
+				if err := json.NewDecoder(conn).Decode(&req); err != nil {
+					return
+				}
+				s.requests <- req
+				result, rpcErr := handle(req)
+				response := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+				if rpcErr != nil {
+					response["error"] = rpcErr
+				} else {
+					response["result"] = result
+				}
+				_ = json.NewEncoder(conn).Encode(response)
+			}()
+		}
+	}()
+	return s
+}
+
+func TestAdminRPCClient_SetIdentity(t *testing.T) {
+	standIn := newAdminRPCStandIn(t, func(req rpcRequest) (any, *RPCError) { return nil, nil })
+	client := NewAdminRPCClient(standIn.ledgerDir)
+
+	require.NoError(t, client.SetIdentity(context.Background(), "/keys/active.json", true))
+
+	req := <-standIn.requests
+	assert.Equal(t, "2.0", req.JSONRPC)
+	assert.Equal(t, "setIdentity", req.Method)
+	assert.Equal(t, []any{"/keys/active.json", true}, req.Params)
+}
+
+func TestAdminRPCClient_SetIdentityFromBytes(t *testing.T) {
+	standIn := newAdminRPCStandIn(t, func(req rpcRequest) (any, *RPCError) { return nil, nil })
+	client := NewAdminRPCClient(standIn.ledgerDir)
+
+	require.NoError(t, client.SetIdentityFromBytes(context.Background(), []byte{1, 2, 255}, false))
+
+	req := <-standIn.requests
+	assert.Equal(t, "setIdentityFromBytes", req.Method)
+	// keypair bytes are sent as a JSON array of numbers, not base64
+	assert.Equal(t, []any{[]any{float64(1), float64(2), float64(255)}, false}, req.Params)
+}
+
+func TestAdminRPCClient_ContactInfo(t *testing.T) {
+	standIn := newAdminRPCStandIn(t, func(req rpcRequest) (any, *RPCError) {
+		return map[string]any{"id": "Ident1111111111111111111111111111111111111", "gossip": "10.0.0.1:8001", "shred_version": 50093}, nil
+	})
+	client := NewAdminRPCClient(standIn.ledgerDir)
+
+	info, err := client.ContactInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Ident1111111111111111111111111111111111111", info.ID)
+	assert.Equal(t, "10.0.0.1:8001", info.Gossip)
+	assert.Equal(t, uint16(50093), info.ShredVersion)
+	assert.Equal(t, "contactInfo", (<-standIn.requests).Method)
+}
+
+func TestAdminRPCClient_RPCError(t *testing.T) {
+	standIn := newAdminRPCStandIn(t, func(req rpcRequest) (any, *RPCError) {
+		return nil, &RPCError{Code: -32000, Message: "Unable to set identity", Data: json.RawMessage(`"tower not found"`)}
+	})
+	client := NewAdminRPCClient(standIn.ledgerDir)
+
+	err := client.SetIdentity(context.Background(), "/keys/active.json", true)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrAdminRPCUnavailable), "a rejected request is not an unavailable socket")
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, -32000, rpcErr.Code)
+	assert.Contains(t, err.Error(), "tower not found")
+}
+
+func TestAdminRPCClient_Unavailable(t *testing.T) {
+	client := NewAdminRPCClient(t.TempDir())
+
+	err := client.SetIdentity(context.Background(), "/keys/active.json", true)
+	assert.True(t, errors.Is(err, ErrAdminRPCUnavailable), err)
+}
+
+func TestAdminRPCClient_Timeout(t *testing.T) {
+	standIn := newAdminRPCStandIn(t, func(req rpcRequest) (any, *RPCError) {
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+	client := NewAdminRPCClient(standIn.ledgerDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.SetIdentity(ctx, "/keys/active.json", true)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
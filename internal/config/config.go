@@ -33,20 +33,32 @@ const (
 	// DefaultFailoverMinimumTimeToLeaderSlot is the default minimum time to leader slot for the failover server
 	DefaultFailoverMinimumTimeToLeaderSlot = "5m"
 
+	// DefaultFailoverSetIdentityTimeout is the default timeout for set identity and rollback commands
+	DefaultFailoverSetIdentityTimeout = "30s"
+
+	// DefaultFailoverCatchUpMaxSlotLag is the default number of slots a passive node may be behind and still become active
+	DefaultFailoverCatchUpMaxSlotLag = 16
+
+	// DefaultFailoverCatchUpCommitment is the default commitment slots are compared at for the catch-up check
+	DefaultFailoverCatchUpCommitment = "confirmed"
+
 	// DefaultFailoverMonitorCreditSamplesCount is the default credit samples count for the failover server
 	DefaultFailoverMonitorCreditSamplesCount = 5
 
 	// DefaultFailoverMonitorCreditSamplesInterval is the default credit samples interval for the failover server
 	DefaultFailoverMonitorCreditSamplesInterval = "5s"
 
-	// DefaultTowerFileNameTemplate is the default tower file name template for the validator
-	DefaultTowerFileNameTemplate = "tower-1_9-{{ .Identities.Active.PubKey }}.bin"
+	// DefaultFailoverRollbackVerifyHandoverWithinSlots is the default number of slots the new active node has to land a vote in
+	DefaultFailoverRollbackVerifyHandoverWithinSlots = 64
+
+	// DefaultFailoverRollbackVerifyHandoverTimeout is the default time limit for verifying the handover
+	DefaultFailoverRollbackVerifyHandoverTimeout = "2m"
 
-	// DefaultSetIdentityPassiveCmdTemplate is the default set identity passive command template for the validator
-	DefaultSetIdentityPassiveCmdTemplate = "{{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Passive.KeyFile }}"
+	// DefaultFailoverMonitorVotesWithinSlots is the default number of slots votes must land within after a failover
+	DefaultFailoverMonitorVotesWithinSlots = 64
 
-	// DefaultSetIdentityActiveCmdTemplate is the default set identity active command template for the validator
-	DefaultSetIdentityActiveCmdTemplate = "{{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Active.KeyFile }} --require-tower"
+	// DefaultFailoverMonitorVotesDuration is the default time votes are monitored for after a failover
+	DefaultFailoverMonitorVotesDuration = "1m"
 )
 
 var (
@@ -99,15 +111,21 @@ func (s *SolanaValidatorFailover) LoadFromConfigFile(configPath string) (err err
 	v.SetDefault("validator.bin", DefaultBin)
 	v.SetDefault("validator.average_slot_duration", DefaultAverageSlotDuration)
 	v.SetDefault("validator.cluster", DefaultCluster)
+	v.SetDefault("validator.failover.catch_up.commitment", DefaultFailoverCatchUpCommitment)
+	v.SetDefault("validator.failover.catch_up.max_slot_lag", DefaultFailoverCatchUpMaxSlotLag)
 	v.SetDefault("validator.failover.min_time_to_leader_slot", DefaultFailoverMinimumTimeToLeaderSlot)
 	v.SetDefault("validator.failover.monitor.credit_samples.count", DefaultFailoverMonitorCreditSamplesCount)
 	v.SetDefault("validator.failover.monitor.credit_samples.interval", DefaultFailoverMonitorCreditSamplesInterval)
+	v.SetDefault("validator.failover.monitor.votes.duration", DefaultFailoverMonitorVotesDuration)
+	v.SetDefault("validator.failover.monitor.votes.enabled", true)
+	v.SetDefault("validator.failover.monitor.votes.within_slots", DefaultFailoverMonitorVotesWithinSlots)
+	v.SetDefault("validator.failover.rollback.verify_handover.timeout", DefaultFailoverRollbackVerifyHandoverTimeout)
+	v.SetDefault("validator.failover.rollback.verify_handover.within_slots", DefaultFailoverRollbackVerifyHandoverWithinSlots)
 	v.SetDefault("validator.failover.server.heartbeat_interval", DefaultFailoverServerHeartbeatInterval)
 	v.SetDefault("validator.failover.server.port", DefaultFailoverServerPort)
+	v.SetDefault("validator.failover.set_identity_timeout", DefaultFailoverSetIdentityTimeout)
 	v.SetDefault("validator.failover.server.stream_timeout", DefaultFailoverServerStreamTimeout)
-	v.SetDefault("validator.failover.set_identity_active_cmd_template", DefaultSetIdentityActiveCmdTemplate)
-	v.SetDefault("validator.failover.set_identity_passive_cmd_template", DefaultSetIdentityPassiveCmdTemplate)
-	v.SetDefault("validator.tower.file_name_template", DefaultTowerFileNameTemplate)
+	// set identity command and tower file name templates default per validator client profile
 	v.SetDefault("update.check_on_startup", true)
 
 	// Read config file
@@ -140,7 +140,18 @@ validator:
 	assert.Equal(t, DefaultFailoverMinimumTimeToLeaderSlot, cfg.Validator.Failover.MinimumTimeToLeaderSlot)             // default
 	assert.Equal(t, DefaultFailoverMonitorCreditSamplesCount, cfg.Validator.Failover.Monitor.CreditSamples.Count)       // default
 	assert.Equal(t, DefaultFailoverMonitorCreditSamplesInterval, cfg.Validator.Failover.Monitor.CreditSamples.Interval) // default
-	assert.Equal(t, DefaultTowerFileNameTemplate, cfg.Validator.Tower.FileNameTemplate)                                 // default
+	assert.Equal(t, DefaultFailoverSetIdentityTimeout, cfg.Validator.Failover.SetIdentityTimeout)                       // default
+	assert.Equal(t, DefaultFailoverCatchUpMaxSlotLag, cfg.Validator.Failover.CatchUp.MaxSlotLag)                        // default
+	assert.Equal(t, DefaultFailoverCatchUpCommitment, cfg.Validator.Failover.CatchUp.Commitment)                        // default
+	assert.True(t, cfg.Validator.Failover.Monitor.Votes.Enabled)                                                        // default
+	assert.Equal(t, DefaultFailoverMonitorVotesWithinSlots, cfg.Validator.Failover.Monitor.Votes.WithinSlots)           // default
+	assert.Equal(t, DefaultFailoverMonitorVotesDuration, cfg.Validator.Failover.Monitor.Votes.Duration)                 // default
+	assert.Empty(t, cfg.Validator.Tower.FileNameTemplate)                                                               // defaulted by client profile
+
+	// verified handover is opt-in
+	assert.False(t, cfg.Validator.Failover.Rollback.VerifyHandover.Enabled)
+	assert.Equal(t, DefaultFailoverRollbackVerifyHandoverWithinSlots, cfg.Validator.Failover.Rollback.VerifyHandover.WithinSlots)
+	assert.Equal(t, DefaultFailoverRollbackVerifyHandoverTimeout, cfg.Validator.Failover.Rollback.VerifyHandover.Timeout)
 }
 
 func TestLoadFromConfigFile_WithInvalidYAML(t *testing.T) {
@@ -186,6 +197,10 @@ validator:
       credit_samples:
         count: 15
         interval: 30s
+      votes:
+        enabled: false
+        within_slots: 100
+        duration: 2m
     peers:
       peer1:
         address: peer1.private.net:9898
@@ -228,6 +243,9 @@ validator:
 	assert.Equal(t, "20s", cfg.Validator.Failover.MinimumTimeToLeaderSlot)
 	assert.Equal(t, 15, cfg.Validator.Failover.Monitor.CreditSamples.Count)
 	assert.Equal(t, "30s", cfg.Validator.Failover.Monitor.CreditSamples.Interval)
+	assert.False(t, cfg.Validator.Failover.Monitor.Votes.Enabled)
+	assert.Equal(t, 100, cfg.Validator.Failover.Monitor.Votes.WithinSlots)
+	assert.Equal(t, "2m", cfg.Validator.Failover.Monitor.Votes.Duration)
 
 	// Verify tower configuration
 	assert.Equal(t,
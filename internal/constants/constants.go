@@ -25,8 +25,17 @@ var (
 	// ClientTypeAgave is the type of agave-validator client
 	ClientTypeAgave = "agave"
 
+	// ClientTypeJito is the type of jito-solana client
+	ClientTypeJito = "jito"
+
 	// ClientTypeFiredancer is the type of firedancer client
 	ClientTypeFiredancer = "firedancer"
+
+	// SetIdentityModeCommand sets identity by running the set-identity command templates
+	SetIdentityModeCommand = "command"
+
+	// SetIdentityModeAdminRPC sets identity over the validator's admin rpc socket
+	SetIdentityModeAdminRPC = "admin_rpc"
 )
 
 func init() {
@@ -3,9 +3,10 @@ package failover
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
-	"strings"
+	"os"
 	"time"
 
 	"github.com/charmbracelet/huh/spinner"
@@ -15,6 +16,7 @@ import (
 	"github.com/quic-go/quic-go"
 	"github.com/sol-strategies/solana-validator-failover/internal/constants"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+	"github.com/sol-strategies/solana-validator-failover/internal/notifications"
 	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 	"github.com/sol-strategies/solana-validator-failover/internal/utils"
@@ -34,10 +36,33 @@ type ClientConfig struct {
 	RPCURL                         string
 	SkipTowerSync                  bool
 	Rollback                       hooks.RollbackConfig
+	Notifier                       *notifications.Dispatcher
+	Schedule                       Schedule
+	// SlotCommitment is the commitment ActiveNodeInfo.Slot is read at when the failover is
+	// requested, for the passive node's catch-up check
+	SlotCommitment rpc.CommitmentType
+	// WaitForWindow, when non-zero, holds the failover until the next leader-free window
+	// with at least this much time left in it
+	WaitForWindow time.Duration
+	// AdminRPC, when set, switches this node's identity over the validator's admin rpc socket,
+	// with ActiveNodeInfo.SetIdentityCommand as the fallback
+	AdminRPC AdminRPC
 	// TLSConfig is an optional mTLS config. When non-nil, the client presents its
 	// certificate to the server and verifies the server's certificate against the CA.
 	// When nil, server certificate verification is skipped (InsecureSkipVerify).
 	TLSConfig *tls.Config
+
+	// Pull is set when the passive node initiates the failover. Instead of dialing
+	// ServerAddress, the client listens on ListenPort and waits for one of PullPeers
+	// to connect, then proceeds exactly as it would have after dialing.
+	Pull              bool
+	PullPeers         map[string]string // peer name -> host:port, used to identify the connecting passive node
+	ListenPort        int
+	HeartbeatInterval string
+	StreamTimeout     string
+	// ListenTLSConfig is the optional mTLS config used when listening in pull mode.
+	// When nil, an ephemeral self-signed certificate is used (no client auth).
+	ListenTLSConfig *tls.Config
 }
 
 // Client is the failover client - an active node connects to a passive node server to handover as active
@@ -49,6 +74,7 @@ type Client struct {
 	activeNodeInfo                 *NodeInfo
 	failoverStream                 *Stream
 	hooks                          hooks.FailoverHooks
+	phaseHooks                     hooks.PhaseQueue // background phase hooks, joined before Start returns
 	minTimeToLeaderSlot            time.Duration
 	waitMinTimeToLeaderSlotEnabled bool
 	localRPCClient                 *rpc.Client
@@ -58,20 +84,20 @@ type Client struct {
 	serverAddress                  string
 	skipTowerSync                  bool
 	rollback                       hooks.RollbackConfig
+	adminRPC                       AdminRPC
+	notifier                       *notifications.Dispatcher
+	schedule                       Schedule
+	slotCommitment                 rpc.CommitmentType
+	slotSource                     solana.SlotSource
+	waitForWindow                  time.Duration
 	tlsConfig                      *tls.Config // non-nil when mTLS is enabled
+	transport                      *quic.Transport
 }
 
 // NewClientFromConfig creates a new QUIC client from a configuration
 func NewClientFromConfig(config ClientConfig) (client *Client, err error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	var clientTLSConfig *tls.Config
-	if config.TLSConfig != nil {
-		cloned := config.TLSConfig.Clone()
-		cloned.NextProtos = []string{ProtocolName}
-		clientTLSConfig = cloned
-	}
-
 	client = &Client{
 		logger:                         log.Default(),
 		ctx:                            ctx,
@@ -87,7 +113,22 @@ func NewClientFromConfig(config ClientConfig) (client *Client, err error) {
 		serverAddress:                  config.ServerAddress,
 		skipTowerSync:                  config.SkipTowerSync,
 		rollback:                       config.Rollback,
-		tlsConfig:                      clientTLSConfig,
+		adminRPC:                       config.AdminRPC,
+		slotCommitment:                 config.SlotCommitment,
+		notifier:                       config.Notifier,
+		schedule:                       config.Schedule,
+		waitForWindow:                  config.WaitForWindow,
+		tlsConfig:                      config.TLSConfig,
+	}
+
+	if config.Pull {
+		err = client.waitForPassivePeer(config)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to accept passive peer connection: %w", err)
+		}
+		client.logger.Debugf("%s connected", style.RenderPassiveString(client.serverName, false))
+		return client, nil
 	}
 
 	err = client.connectToServer()
@@ -106,6 +147,11 @@ func (c *Client) Start() {
 	c.logger.Debug("starting QUIC client")
 	var wentPassive bool
 
+	// give any notifications sent during the failover a chance to go out before returning
+	defer c.notifier.Flush()
+	defer c.closeConnection()
+	defer c.phaseHooks.Wait()
+
 	// open a bidirectional stream to the server
 	stream, err := c.Conn.OpenStreamSync(c.ctx)
 	if err != nil {
@@ -131,9 +177,12 @@ func (c *Client) Start() {
 		return
 	}
 
-	// send message with your own info
+	// send message with your own info - including this node's slot now, so the passive node can
+	// check it isn't behind
+	c.refreshActiveSlot()
 	c.failoverStream.SetActiveNodeInfo(c.activeNodeInfo)
 	c.failoverStream.SetActiveRollbackEnabled(c.rollback.Enabled)
+	c.failoverStream.SetSchedule(c.schedule)
 	err = c.failoverStream.Encode()
 	if err != nil {
 		return
@@ -165,8 +214,11 @@ func (c *Client) Start() {
 		return
 	}
 
+	c.runPhaseHooks(hooks.PhaseOnConnect, false, nil)
+
 	// see if the server says can proceed, else show error message and exit
 	if !c.failoverStream.GetCanProceed() {
+		c.runPhaseHooks(hooks.PhaseOnAbort, false, errors.New(c.failoverStream.GetErrorMessage()))
 		c.logger.Fatal(c.failoverStream.GetErrorMessage())
 		return
 	}
@@ -174,35 +226,26 @@ func (c *Client) Start() {
 	// Get skipTowerSync from the server's message (server is the authority on this)
 	skipTowerSync := c.failoverStream.GetSkipTowerSync()
 
-	// wait until the next leader slot is at least the minimum time to leader slot
-	err = c.waitMinTimeToLeaderSlot()
-	if err != nil {
-		c.logger.Fatal("failed to wait for next leader slot", "err", err)
-		return
-	}
+	// set up slot change detection now so any websocket subscription is warm by the time we
+	// need to catch the start of a slot
+	c.slotSource = c.solanaRPCClient.NewSlotSource(c.ctx)
+	defer c.slotSource.Close()
+	c.logger.Debug("slot source", "source", c.slotSource.Name())
 
-	// run pre hooks when active
-	err = c.hooks.RunPreWhenActive(c.getHookEnvMap(hookEnvMapParams{
-		isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
-		isPreFailover:    true,
-	}))
+	// wait for the schedule, leader checks and pre hooks, then catch the start of the slot to switch in
+	slotTransition, err := c.waitForSwitchPoint(c.failoverStream.GetSchedule())
 	if err != nil {
-		c.logger.Fatal("failed to run pre hooks when active", "err", err)
+		c.abortFailover("failed to reach the switch point", err)
+		c.logger.Fatal("failed to reach the switch point", "err", err)
 		return
 	}
 
 	c.logger.Info("failover started")
-
-	// wait until the next slot starts so we switch right at the beginning of the next slot
-	// this ensures we're early in the slot when we start the switch
-	slot, err := c.waitUntilStartOfNextSlot()
-	if err != nil {
-		c.logger.Fatal("failed to wait for next slot to start", "err", err)
-		return
-	}
+	c.notifier.Notify(c.failoverStream.NotificationEvent(notifications.EventStarted, ""))
 
 	// set the failover start slot to the current slot (we're now early in this slot)
-	c.failoverStream.SetFailoverStartSlot(slot)
+	c.failoverStream.SetFailoverStartSlot(slotTransition.Slot)
+	c.failoverStream.SetFailoverStartSlotDetection(slotTransition)
 
 	// set identity to passive
 	dryRunPrefix := ""
@@ -214,54 +257,99 @@ func (c *Client) Start() {
 		style.RenderPassiveString(constants.NodeRolePassive, false) +
 		style.RenderPinkString(" identity"))
 
+	c.runPhaseHooks(hooks.PhaseBeforeSetIdentity, false, nil)
+
 	c.failoverStream.SetActiveNodeSetIdentityStartTime()
 
-	err = utils.RunCommand(utils.RunCommandParams{
-		CommandSlice: strings.Split(c.failoverStream.GetActiveNodeInfo().SetIdentityCommand, " "),
-		DryRun:       c.failoverStream.GetIsDryRunFailover(),
-		LogDebug:     c.logger.GetLevel() <= log.DebugLevel,
+	err = setIdentity(c.ctx, setIdentityParams{
+		adminRPC: c.adminRPC,
+		identity: c.failoverStream.GetActiveNodeInfo().Identities.Passive,
+		command:  c.failoverStream.GetActiveNodeInfo().SetIdentityCmd,
+		dryRun:   c.failoverStream.GetIsDryRunFailover(),
+		logger:   c.logger,
 	})
+	if errors.Is(err, utils.ErrCommandTimedOut) {
+		// killed mid-way, so the validator may or may not have switched - handle it like an
+		// unconfirmed switch
+		c.failFailover("set identity to passive timed out", err)
+		c.logger.Error("set identity to passive timed out", "err", err)
+		c.logger.Error("CRITICAL: this node may or may not be passive - the passive node was told not to take over")
+		c.rollbackUnconfirmedPassiveIdentity(err)
+		return
+	}
 	if err != nil {
+		c.abortFailover("failed to set identity to passive", err)
 		c.logger.Error("failed to set identity to passive", "err", err)
 		return
 	}
 	c.failoverStream.SetActiveNodeSetIdentityEndTime()
 	wentPassive = true // this node is now passive; used below for rollback/warning decisions
 
+	// the command exiting zero isn't proof - the validator must report the passive identity before
+	// the passive node is allowed to take over
+	if !c.failoverStream.GetIsDryRunFailover() {
+		confirmedAt, err := waitForLocalIdentity(c.ctx, c.solanaRPCClient, c.failoverStream.GetActiveNodeInfo().Identities.Passive.PubKey(), identityConfirmTimeout, identityConfirmInterval)
+		if err != nil {
+			c.failFailover("failed to confirm passive identity", err)
+			c.logger.Error("failed to confirm passive identity", "err", err)
+			c.logger.Error("CRITICAL: this node may still be active - the passive node was told not to take over")
+			c.rollbackUnconfirmedPassiveIdentity(err)
+			return
+		}
+		c.failoverStream.SetActiveNodeIdentityConfirmedTime(confirmedAt)
+		c.logger.Debug("local validator reports passive identity")
+	}
+
+	c.runPhaseHooks(hooks.PhaseAfterSetIdentity, wentPassive, nil)
+
 	if skipTowerSync {
 		c.logger.Info("skipping tower file sync")
-		// Don't send anything - server won't wait for tower file when skipTowerSync is true
 	} else {
+		c.runPhaseHooks(hooks.PhaseBeforeTowerSync, wentPassive, nil)
 		c.logger.Infof("sending tower file to %s", style.RenderPassiveString(c.failoverStream.GetPassiveNodeInfo().Hostname, false))
 
 		// Read the tower file into TowerFileBytes
 		c.failoverStream.SetActiveNodeSyncTowerFileStartTime()
 		err = c.failoverStream.GetActiveNodeInfo().SetTowerFileBytes()
 		if err != nil {
+			c.failFailover("failed to read tower file", err)
 			c.logger.Error(fmt.Sprintf("failed to set tower file bytes for %s", c.failoverStream.GetActiveNodeInfo().TowerFile), "err", err)
+			c.logger.Error("CRITICAL: this node is now passive and the passive node was told not to take over — intervene manually")
+			if !c.rollback.ToActive.ResolvedCmd.IsZero() {
+				c.logger.Errorf("if this node needs to revert to active: %s", c.rollback.ToActive.ResolvedCmd)
+			}
 			return
 		}
 		c.failoverStream.SetActiveNodeSyncTowerFileEndTime()
+	}
 
-		// Send the updated node info with tower file bytes
-		if err := c.failoverStream.Encode(); err != nil {
-			c.logger.Error(fmt.Sprintf("failed to send tower file bytes for %s", c.failoverStream.GetActiveNodeInfo().TowerFile), "err", err)
-			if wentPassive {
-				c.logger.Error(
-					"CRITICAL: tower sync failed after this node switched to passive — " +
-						"the passive node has not changed identity; check gossip and intervene manually if needed",
-				)
-				if c.rollback.ToActive.ResolvedCmd != "" {
-					c.logger.Errorf("if this node needs to revert to active: %s", c.rollback.ToActive.ResolvedCmd)
-				}
-			}
-			return
+	// Tell the passive node this node is now passive - with the tower file bytes unless skipping
+	// tower sync. The passive node waits for this before taking the active identity. Outputs of
+	// background phase hooks still running are missing from the summary.
+	c.failoverStream.SetActiveNodeHookOutputs(c.hooks.Outputs.Snapshot())
+	if err := c.failoverStream.Encode(); err != nil {
+		c.runPhaseHooks(hooks.PhaseOnFailure, wentPassive, fmt.Errorf("failed to send tower file: %w", err))
+		c.logger.Error(fmt.Sprintf("failed to send tower file bytes for %s", c.failoverStream.GetActiveNodeInfo().TowerFile), "err", err)
+		c.logger.Error(
+			"CRITICAL: tower sync failed after this node switched to passive — " +
+				"the passive node has not changed identity; check gossip and intervene manually if needed",
+		)
+		if !c.rollback.ToActive.ResolvedCmd.IsZero() {
+			c.logger.Errorf("if this node needs to revert to active: %s", c.rollback.ToActive.ResolvedCmd)
 		}
+		return
+	}
+	if !skipTowerSync {
+		c.runPhaseHooks(hooks.PhaseAfterTowerSync, wentPassive, nil)
 	}
 
 	// wait for confirmation from server that failover is complete
+	if c.failoverStream.GetVerifyHandover() {
+		c.logger.Infof("verified handover: waiting for %s's votes to land - this node reverts to active if they don't", style.RenderActiveString(c.failoverStream.GetPassiveNodeInfo().Hostname, false))
+	}
 	err = c.failoverStream.Decode()
 	if err != nil {
+		c.runPhaseHooks(hooks.PhaseOnFailure, wentPassive, fmt.Errorf("failed to decode failover stream: %w", err))
 		c.logger.Error("failed to decode failover stream", "err", err)
 		if wentPassive {
 			// The connection dropped after this node switched to passive.
@@ -271,7 +359,7 @@ func (c *Client) Start() {
 				"CRITICAL: connection lost after this node switched to passive — " +
 					"check gossip to determine cluster state and intervene manually if needed",
 			)
-			if c.rollback.ToActive.ResolvedCmd != "" {
+			if !c.rollback.ToActive.ResolvedCmd.IsZero() {
 				c.logger.Errorf("if this node needs to revert to active: %s", c.rollback.ToActive.ResolvedCmd)
 			}
 		}
@@ -281,20 +369,44 @@ func (c *Client) Start() {
 	// Check for explicit rollback signal from server
 	if c.failoverStream.GetRollbackRequired() {
 		c.logger.Error("server signalled rollback required — failover failed on the passive node")
+		c.runPhaseHooks(hooks.PhaseOnFailure, wentPassive, errors.New(c.failoverStream.GetErrorMessage()))
+		if c.failoverStream.GetHandoverUnverified() && !skipTowerSync {
+			// the passive node went active and may have voted - revert with its tower, not ours
+			if err := c.restoreTowerFile(); err != nil {
+				c.logger.Error("failed to restore the tower file sent back by the passive node — not reverting to active; manual intervention required", "err", err)
+				if !c.rollback.ToActive.ResolvedCmd.IsZero() {
+					c.logger.Errorf("once %s is restored from %s:%s, revert this node to active with: %s",
+						c.failoverStream.GetActiveNodeInfo().TowerFile,
+						c.failoverStream.GetPassiveNodeInfo().Hostname,
+						c.failoverStream.GetPassiveNodeInfo().TowerFile,
+						c.rollback.ToActive.ResolvedCmd,
+					)
+				}
+				return
+			}
+			c.logger.Infof("restored tower file from %s", style.RenderPassiveString(c.failoverStream.GetPassiveNodeInfo().Hostname, false))
+		}
 		if c.rollback.Enabled && wentPassive {
 			c.logger.Warn("rollback enabled: reverting this node to active")
-			if rbErr := RunRollbackToActive(c.rollback, c.getHookEnvMap(hookEnvMapParams{
+			reason := "failed to take over"
+			if c.failoverStream.GetHandoverUnverified() {
+				reason = "may have gone active and rolled back to passive"
+			}
+			c.notifier.Notify(c.failoverStream.NotificationEvent(notifications.EventRollback,
+				fmt.Sprintf("%s %s - reverting %s to active", c.failoverStream.GetPassiveNodeInfo().Hostname, reason, c.failoverStream.GetActiveNodeInfo().Hostname)))
+			if rbErr := RunRollbackToActive(c.failoverStream.HookContext(c.ctx), c.rollback, c.getHookEnvMap(hookEnvMapParams{
 				isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
 				isPostFailover:   true,
+				err:              errors.New(c.failoverStream.GetErrorMessage()),
 			}), c.failoverStream.GetIsDryRunFailover(), c.logger); rbErr != nil {
 				c.logger.Error("rollback to active failed — manual intervention required", "err", rbErr)
-				if c.rollback.ToActive.ResolvedCmd != "" {
+				if !c.rollback.ToActive.ResolvedCmd.IsZero() {
 					c.logger.Errorf("to recover this node: %s", c.rollback.ToActive.ResolvedCmd)
 				}
 			}
 		} else {
 			c.logger.Error("rollback disabled — this node is currently passive; manual intervention required")
-			if c.rollback.ToActive.ResolvedCmd != "" {
+			if !c.rollback.ToActive.ResolvedCmd.IsZero() {
 				c.logger.Errorf("to revert this node to active: %s", c.rollback.ToActive.ResolvedCmd)
 			}
 		}
@@ -303,57 +415,364 @@ func (c *Client) Start() {
 
 	if !c.failoverStream.GetIsSuccessfullyCompleted() {
 		c.logger.Errorf("server failed to complete failover: %s", c.failoverStream.GetErrorMessage())
+		c.runPhaseHooks(hooks.PhaseOnFailure, wentPassive, errors.New(c.failoverStream.GetErrorMessage()))
 		return
 	}
 
 	c.logger.Info("failover complete")
 
-	// run post hooks now this is passive and active node says all is peachy
-	c.hooks.RunPostWhenPassive(c.getHookEnvMap(hookEnvMapParams{
+	// run post hooks now this is passive and active node says all is peachy - once the phase hooks
+	// still running in the background are done
+	c.phaseHooks.Wait()
+	c.hooks.RunPostWhenPassive(c.failoverStream.HookContext(c.ctx), c.getHookEnvMap(hookEnvMapParams{
 		isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
 		isPostFailover:   true,
+		phase:            hooks.PhasePost,
 	}))
 }
 
-// waitUntilStartOfNextSlot waits until the start of the next slot
-// this is important to try to start a failover early in the slot to avoid missing it
-// It polls getSlot() to detect when the slot changes and returns the new slot number,
-// which naturally gets us well within the first few milliseconds of the new slot
-// should get us in within the first 10ms of the next slot on average
-func (c *Client) waitUntilStartOfNextSlot() (newSlot uint64, err error) {
-	c.logger.Debug("waiting until start of next slot")
+// closeConnection closes the connection to the passive node and the transport it runs over,
+// releasing the UDP socket
+func (c *Client) closeConnection() {
+	if c.Conn != nil {
+		if err := c.Conn.CloseWithError(quic.ApplicationErrorCode(0), "client done"); err != nil {
+			c.logger.Debugf("closing connection: %v", err)
+		}
+	}
+	if c.transport != nil {
+		if err := c.transport.Close(); err != nil {
+			c.logger.Error("failed to close transport", "err", err)
+		}
+	}
+}
+
+// abortFailover tells the passive node not to take the active identity and runs on_abort hooks.
+// It is best-effort: if the stream is already broken the passive node will see the connection
+// drop instead.
+func (c *Client) abortFailover(reason string, err error) {
+	c.notifyPassiveOfAbort(reason, err)
+	c.runPhaseHooks(hooks.PhaseOnAbort, false, fmt.Errorf("%s: %w", reason, err))
+}
+
+// failFailover is abortFailover for when this node has already gone passive, so the failover
+// has failed rather than been aborted and on_failure hooks run instead
+func (c *Client) failFailover(reason string, err error) {
+	c.notifyPassiveOfAbort(reason, err)
+	c.runPhaseHooks(hooks.PhaseOnFailure, true, fmt.Errorf("%s: %w", reason, err))
+}
+
+// rollbackUnconfirmedPassiveIdentity reverts this node to active when its validator never reported
+// the passive identity. The passive node has been told not to take over, so re-asserting the active
+// identity is safe whether or not the set-identity command actually took effect.
+func (c *Client) rollbackUnconfirmedPassiveIdentity(err error) {
+	if !c.rollback.Enabled {
+		c.logger.Error("rollback disabled — check this node's identity and intervene manually")
+		if !c.rollback.ToActive.ResolvedCmd.IsZero() {
+			c.logger.Errorf("if this node needs to revert to active: %s", c.rollback.ToActive.ResolvedCmd)
+		}
+		return
+	}
+
+	c.logger.Warn("rollback enabled: reverting this node to active")
+	c.notifier.Notify(c.failoverStream.NotificationEvent(notifications.EventRollback,
+		fmt.Sprintf("%s did not confirm its passive identity (%v) - reverting it to active", c.failoverStream.GetActiveNodeInfo().Hostname, err)))
+	if rbErr := RunRollbackToActive(c.failoverStream.HookContext(c.ctx), c.rollback, c.getHookEnvMap(hookEnvMapParams{
+		isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
+		isPostFailover:   true,
+		err:              err,
+	}), c.failoverStream.GetIsDryRunFailover(), c.logger); rbErr != nil {
+		c.logger.Error("rollback to active failed — manual intervention required", "err", rbErr)
+		if !c.rollback.ToActive.ResolvedCmd.IsZero() {
+			c.logger.Errorf("to recover this node: %s", c.rollback.ToActive.ResolvedCmd)
+		}
+	}
+}
+
+// restoreTowerFile writes the tower file the passive node sent back after an unverified handover,
+// so this node reverts to active with any votes cast while the passive node was active
+func (c *Client) restoreTowerFile() error {
+	passiveNodeInfo := c.failoverStream.GetPassiveNodeInfo()
+	computedTowerFileHash := passiveNodeInfo.ComputeTowerFileHashFromBytes(passiveNodeInfo.TowerFileBytes)
+	if computedTowerFileHash != passiveNodeInfo.TowerFileHash {
+		return fmt.Errorf("tower file hash mismatch: (got: %s) != (expected: %s)", computedTowerFileHash, passiveNodeInfo.TowerFileHash)
+	}
+	if err := os.WriteFile(c.failoverStream.GetActiveNodeInfo().TowerFile, passiveNodeInfo.TowerFileBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write tower file %s: %w", c.failoverStream.GetActiveNodeInfo().TowerFile, err)
+	}
+	return nil
+}
+
+// notifyPassiveOfAbort tells the passive node not to take the active identity
+func (c *Client) notifyPassiveOfAbort(reason string, err error) {
+	c.failoverStream.SetErrorMessagef("active node aborted failover: %s: %v", reason, err)
+	if encodeErr := c.failoverStream.Encode(); encodeErr != nil {
+		c.logger.Debug("failed to notify passive node of abort", "err", encodeErr)
+	}
+}
+
+// runPhaseHooks runs the hooks for a failover phase on this node, which is active until it has
+// switched to its passive identity
+func (c *Client) runPhaseHooks(phase string, wentPassive bool, err error) {
+	role := constants.NodeRoleActive
+	if wentPassive {
+		role = constants.NodeRolePassive
+	}
+	c.hooks.RunPhase(c.failoverStream.HookContext(c.ctx), phase, role, c.getHookEnvMap(hookEnvMapParams{
+		isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
+		isPreFailover:    !wentPassive,
+		isPostFailover:   wentPassive,
+		phase:            phase,
+		err:              err,
+	}), &c.phaseHooks)
+}
+
+// waitForSwitchPoint runs everything that must happen before the switch - the schedule, the
+// leader-free window and next leader slot checks, the pre hooks and the passive node re-checking it
+// is ready - and returns once the slot to switch in has started. With a slot schedule all of that
+// runs scheduleLeadSlots ahead of it, so only the final approach lies between them and the switch,
+// which lands in the scheduled slot. The failover is refused if they run past it.
+func (c *Client) waitForSwitchPoint(schedule Schedule) (transition solana.SlotTransition, err error) {
+	if schedule.IsSet() {
+		if err := c.waitForSchedule(schedule); err != nil {
+			return transition, fmt.Errorf("failed to wait for scheduled failover: %w", err)
+		}
+	}
+
+	// wait for a long enough leader-free window if asked to
+	if c.waitForWindow > 0 {
+		if err := c.waitForLeaderFreeWindow(); err != nil {
+			return transition, fmt.Errorf("failed to wait for leader-free window: %w", err)
+		}
+	}
+
+	// wait until the next leader slot is at least the minimum time to leader slot
+	if err := c.waitMinTimeToLeaderSlot(); err != nil {
+		return transition, err
+	}
+
+	// run pre hooks when active
+	err = c.hooks.RunPreWhenActive(c.failoverStream.HookContext(c.ctx), c.getHookEnvMap(hookEnvMapParams{
+		isDryRunFailover: c.failoverStream.GetIsDryRunFailover(),
+		isPreFailover:    true,
+		phase:            hooks.PhasePre,
+	}))
+	if err != nil {
+		return transition, fmt.Errorf("failed to run pre hooks when active: %w", err)
+	}
+
+	// the passive node's checks ran before all the waiting - have it check again now
+	if err := c.confirmPassiveReady(); err != nil {
+		return transition, err
+	}
+
+	if schedule.AtSlot > 0 {
+		if err := c.waitForScheduledSlot(schedule); err != nil {
+			return transition, err
+		}
+	}
+
+	// wait until the next slot starts so we switch right at the beginning of the next slot
+	// this ensures we're early in the slot when we start the switch
+	transition, err = c.waitUntilStartOfNextSlot()
+	if err != nil {
+		return transition, fmt.Errorf("failed to wait for next slot to start: %w", err)
+	}
+
+	if schedule.AtSlot > 0 && transition.Slot != schedule.AtSlot {
+		c.logger.Warn("switching in a later slot than scheduled", "scheduled_slot", schedule.AtSlot, "slot", transition.Slot)
+	}
+	return transition, nil
+}
+
+// refreshActiveSlot records this node's current slot in its node info for the passive node to check
+// it isn't behind. It is left at 0 - unknown - when slot checks are off or the slot can't be read.
+func (c *Client) refreshActiveSlot() {
+	if c.slotCommitment == "" {
+		return
+	}
+	slot, err := c.solanaRPCClient.GetLocalNodeSlot(c.slotCommitment)
+	if err != nil {
+		c.logger.Warn("failed to get local slot - the passive node will only check its slot against the cluster", "err", err)
+	}
+	c.activeNodeInfo.Slot = slot
+}
+
+// confirmPassiveReady tells the passive node this node has finished waiting and is about to switch,
+// with its current slot, and waits for the passive node to re-run its preflight - the checks it ran
+// before the waits may be minutes or hours stale. An error means the passive node refused.
+func (c *Client) confirmPassiveReady() error {
+	c.refreshActiveSlot()
+	c.failoverStream.GetActiveNodeInfo().Slot = c.activeNodeInfo.Slot
+	c.failoverStream.SetReadyToSwitch(true)
+	if err := c.failoverStream.Encode(); err != nil {
+		return fmt.Errorf("failed to tell passive node this node is ready to switch: %w", err)
+	}
+
+	c.logger.Debug("waiting for passive node to confirm it is still ready")
+	if err := c.failoverStream.Decode(); err != nil {
+		return fmt.Errorf("failed to get passive node's confirmation it is still ready: %w", err)
+	}
+	if msg := c.failoverStream.GetErrorMessage(); msg != "" {
+		return fmt.Errorf("passive node is no longer ready: %s", msg)
+	}
+	return nil
+}
+
+// waitForSchedule blocks until the scheduled failover point is reached. Slot schedules return
+// scheduleLeadSlots ahead of the target, leaving waitForScheduledSlot to make the final approach.
+func (c *Client) waitForSchedule(schedule Schedule) error {
+	c.logger.Infof("failover scheduled %s", schedule)
+
+	sp := spinner.New().TitleStyle(style.SpinnerTitleStyle).Title(style.RenderPinkString(fmt.Sprintf("failover scheduled %s - waiting...", schedule)))
+	sp.ActionWithErr(func(ctx context.Context) error {
+		if !schedule.AtTime.IsZero() {
+			for {
+				remaining := time.Until(schedule.AtTime)
+				if remaining <= 0 {
+					return nil
+				}
+				sp.Title(style.RenderPinkString(fmt.Sprintf("failover scheduled %s - starting in %s...", schedule, remaining.Round(time.Second))))
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(min(remaining, time.Second)):
+				}
+			}
+		}
+
+		if schedule.AtSlot <= scheduleLeadSlots {
+			return nil
+		}
+		return c.waitUntilSlot(ctx, schedule.AtSlot-scheduleLeadSlots, func(slot uint64) {
+			sp.Title(style.RenderPinkString(fmt.Sprintf("failover scheduled %s - %d slots to go...", schedule, schedule.AtSlot-slot)))
+		})
+	})
+
+	if err := sp.Run(); err != nil {
+		return err
+	}
 
-	// Get the current slot number
-	currentSlot, err := c.solanaRPCClient.GetCurrentSlot()
+	c.logger.Info("scheduled failover point reached")
+	return nil
+}
+
+// waitForScheduledSlot makes the final approach to a slot schedule, returning once the cluster is
+// in the slot before the target so that waitUntilStartOfNextSlot then switches right as the target
+// slot starts. It fails if the target slot has already started.
+func (c *Client) waitForScheduledSlot(schedule Schedule) error {
+	slot, err := c.solanaRPCClient.GetCurrentSlot()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get current slot: %w", err)
+		return fmt.Errorf("failed to get current slot: %w", err)
+	}
+	if slot >= schedule.AtSlot {
+		return fmt.Errorf("missed scheduled slot %d - the leader checks and pre hooks finished in slot %d", schedule.AtSlot, slot)
+	}
+
+	c.logger.Debug("approaching scheduled slot", "slot", slot, "scheduled_slot", schedule.AtSlot)
+	return c.waitUntilSlot(c.ctx, schedule.AtSlot-1, nil)
+}
+
+// waitForLeaderFreeWindow blocks until the next leader-free window with at least
+// c.waitForWindow of time left in it has started
+func (c *Client) waitForLeaderFreeWindow() error {
+	pubkey, err := solanago.PublicKeyFromBase58(c.activeNodeInfo.Identities.Active.PubKey())
+	if err != nil {
+		return fmt.Errorf("failed to parse active identity pubkey: %w", err)
+	}
+
+	windows, err := c.solanaRPCClient.GetLeaderFreeWindows(pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to get leader-free windows: %w", err)
+	}
+
+	if !windows.IsOnLeaderSchedule {
+		c.logger.Info("not on leader schedule, skipping wait for leader-free window")
+		return nil
+	}
+
+	window, found := windows.NextAtLeast(c.waitForWindow, time.Now())
+	if !found {
+		return fmt.Errorf("no leader-free window of at least %s found before slot %d", c.waitForWindow, windows.ScanEndSlot)
 	}
 
-	// Poll getSlot() to detect when the slot changes.
-	// getSlot() is a lightweight local RPC call so 10ms polling is cheap and gives
-	// ~5ms average detection lag vs ~25ms at the previous 50ms interval.
-	// On RPC error use a longer back-off to avoid hammering a struggling local node.
+	c.logger.Info("waiting for leader-free window",
+		"start_slot", window.StartSlot,
+		"end_slot", window.EndSlot,
+		"starts_in", time.Until(window.StartTime).Round(time.Second),
+		"duration", window.Duration().Round(time.Second),
+	)
+
+	title := fmt.Sprintf("waiting for leader-free window at slot %d", window.StartSlot)
+	sp := spinner.New().TitleStyle(style.SpinnerTitleStyle).Title(style.RenderPinkString(title + "..."))
+	sp.ActionWithErr(func(ctx context.Context) error {
+		return c.waitUntilSlot(ctx, window.StartSlot, func(slot uint64) {
+			sp.Title(style.RenderPinkString(fmt.Sprintf("%s - %d slots to go...", title, window.StartSlot-slot)))
+		})
+	})
+
+	if err := sp.Run(); err != nil {
+		return err
+	}
+
+	c.logger.Info("leader-free window reached", "slot", window.StartSlot)
+	return nil
+}
+
+// waitUntilSlot polls the current slot until it reaches at least target. Polling slows down
+// while the target is far away and speeds up as it gets close.
+func (c *Client) waitUntilSlot(ctx context.Context, target uint64, onProgress func(slot uint64)) error {
 	const (
-		pollInterval      = 10 * time.Millisecond
-		errorRetryInterval = 50 * time.Millisecond
+		farPollInterval    = time.Second
+		nearPollInterval   = 50 * time.Millisecond
+		nearSlots          = 10
+		errorRetryInterval = 500 * time.Millisecond
 	)
+
 	for {
 		slot, err := c.solanaRPCClient.GetCurrentSlot()
-		if err != nil {
+		interval := farPollInterval
+		switch {
+		case err != nil:
 			c.logger.Debug("failed to get slot, retrying", "err", err)
-			time.Sleep(errorRetryInterval)
-			continue
+			interval = errorRetryInterval
+		case slot >= target:
+			return nil
+		default:
+			if onProgress != nil {
+				onProgress(slot)
+			}
+			if target-slot <= nearSlots {
+				interval = nearPollInterval
+			}
 		}
 
-		// Slot has changed, we're now in the next slot
-		if slot > currentSlot {
-			c.logger.Debug("slot transition detected, proceeding", "old_slot", currentSlot, "new_slot", slot)
-			return slot, nil
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
 		}
+	}
+}
+
+// waitUntilStartOfNextSlot waits until the start of the next slot
+// this is important to try to start a failover early in the slot to avoid missing it
+// It uses the client's slot source - websocket notifications from the local validator when
+// available, otherwise polling getSlot - and returns the new slot along with how it was detected
+func (c *Client) waitUntilStartOfNextSlot() (transition solana.SlotTransition, err error) {
+	c.logger.Debug("waiting until start of next slot")
 
-		// Still in the same slot, continue polling
-		time.Sleep(pollInterval)
+	source := c.slotSource
+	if source == nil {
+		source = solana.NewPollingSlotSource(c.solanaRPCClient.GetCurrentSlot, c.logger)
 	}
+
+	transition, err = source.WaitForNextSlot(c.ctx)
+	if err != nil {
+		return solana.SlotTransition{}, err
+	}
+
+	c.logger.Debug("slot transition detected, proceeding", "slot", transition.Slot, "detected", transition)
+	return transition, nil
 }
 
 // waitMinTimeToLeaderSlot waits until the next leader slot is at least the minimum time to leader slot
@@ -443,6 +862,11 @@ func (c *Client) getHookEnvMap(params hookEnvMapParams) (envMap map[string]strin
 	envMap = map[string]string{}
 
 	envMap["IS_DRY_RUN_FAILOVER"] = fmt.Sprintf("%t", params.isDryRunFailover)
+	envMap["PHASE"] = params.phase
+	if params.err != nil {
+		envMap["ERROR"] = params.err.Error()
+	}
+	envMap["CLUSTER"] = c.activeNodeInfo.Cluster
 
 	// this node is active
 	if params.isPreFailover {
@@ -520,58 +944,130 @@ func (c *Client) connectToServer() error {
 }
 
 // tryQUICConnection attempts the actual QUIC connection that will be used.
-// It uses a basicPacketConn wrapper to avoid quic-go's OOB (recvmsg/sendmsg)
-// optimizations that fail on virtual network interfaces like Tailscale/WireGuard.
 func (c *Client) tryQUICConnection() error {
-	udpAddr, err := net.ResolveUDPAddr("udp4", c.serverAddress)
+	transport, conn, err := dialQUIC(c.ctx, c.serverAddress, newDialerTLSConfig(c.tlsConfig), nil)
 	if err != nil {
-		c.logger.Debug("failed to resolve server address", "err", err, "address", c.serverAddress)
+		if isALPNMismatch(err) {
+			// Fatal logs and calls os.Exit(1) — the spinner will not retry.
+			c.logger.Fatal(
+				"passive node rejected connection: incompatible wire protocol version — " +
+					"ensure both nodes run the same version of solana-validator-failover",
+			)
+		}
+		c.logger.Debug("QUIC server not ready, retrying...", "err", err, "address", c.serverAddress)
 		return err
 	}
 
-	wrapped, err := newBasicPacketConn(":0")
+	if c.tlsConfig != nil {
+		logPeerCertificate(c.logger, conn, "server")
+	}
+
+	c.transport = transport
+	c.Conn = conn
+	return nil
+}
+
+// waitForPassivePeer listens on the failover server port and blocks until a configured
+// passive peer connects (pull mode). Connections from addresses that don't resolve to a
+// configured peer are rejected so an arbitrary host can't talk this node into going passive.
+func (c *Client) waitForPassivePeer(config ClientConfig) error {
+	tlsConfig, err := newListenerTLSConfig(config.ListenTLSConfig)
 	if err != nil {
-		c.logger.Debug("failed to create UDP socket", "err", err)
 		return err
 	}
 
-	tr := &quic.Transport{Conn: wrapped}
+	port := config.ListenPort
+	if port == 0 {
+		port = DefaultPort
+	}
 
-	quicTLSConfig := c.tlsConfig
-	if quicTLSConfig == nil {
-		quicTLSConfig = &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec // intentional fallback when mTLS is not configured
-			NextProtos:         []string{ProtocolName},
-		}
+	if config.HeartbeatInterval == "" {
+		config.HeartbeatInterval = DefaultHeartbeatIntervalDurationStr
+	}
+	heartbeatInterval, err := time.ParseDuration(config.HeartbeatInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse heartbeat interval: %v", err)
 	}
 
-	conn, err := tr.Dial(c.ctx, udpAddr, quicTLSConfig, nil)
+	if config.StreamTimeout == "" {
+		config.StreamTimeout = DefaultStreamTimeoutDurationStr
+	}
+	streamTimeout, err := time.ParseDuration(config.StreamTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse stream timeout: %v", err)
+	}
+
+	transport, listener, err := listenQUIC(port, tlsConfig, &quic.Config{
+		KeepAlivePeriod: heartbeatInterval,
+		MaxIdleTimeout:  streamTimeout,
+	})
 	if err != nil {
-		tr.Close()
-		if isALPNMismatch(err) {
-			// Fatal logs and calls os.Exit(1) — the spinner will not retry.
-			c.logger.Fatal(
-				"passive node rejected connection: incompatible wire protocol version — " +
-					"ensure both nodes run the same version of solana-validator-failover",
-			)
-		}
-		c.logger.Debug("QUIC server not ready, retrying...", "err", err, "address", c.serverAddress)
 		return err
 	}
+	// only one passive peer may drive this failover - stop accepting once it has connected
+	defer listener.Close()
 
-	if c.tlsConfig != nil {
-		tlsState := conn.ConnectionState().TLS
-		if len(tlsState.PeerCertificates) > 0 {
-			peer := tlsState.PeerCertificates[0]
-			c.logger.Info("mTLS: server certificate verified",
-				"remote_addr", conn.RemoteAddr().String(),
-				"subject", peer.Subject.String(),
-				"issuer", peer.Issuer.String(),
-				"expires", peer.NotAfter,
-			)
+	c.logger.Info(style.RenderPinkString(fmt.Sprintf("pull mode: listening on port %d - run this program with --pull on the ", port)) +
+		style.RenderPassiveString("passive", false) +
+		style.RenderPinkString(" validator to continue"))
+
+	sp := spinner.New().Context(c.ctx).Title(style.RenderPinkString("waiting for a passive peer to connect..."))
+	sp.ActionWithErr(func(spinnerCtx context.Context) error {
+		for {
+			conn, err := listener.Accept(spinnerCtx)
+			if err != nil {
+				return err
+			}
+
+			peerName, ok := matchPeerByRemoteAddr(config.PullPeers, conn.RemoteAddr())
+			if !ok {
+				c.logger.Warn("rejecting connection from unknown peer", "remote_addr", conn.RemoteAddr().String())
+				_ = conn.CloseWithError(quic.ApplicationErrorCode(1), "unknown peer")
+				continue
+			}
+
+			if config.ListenTLSConfig != nil {
+				logPeerCertificate(c.logger, conn, "client")
+			}
+
+			c.serverName = peerName
+			c.serverAddress = conn.RemoteAddr().String()
+			c.Conn = conn
+			return nil
 		}
+	})
+
+	if err := sp.Run(); err != nil {
+		transport.Close()
+		return err
 	}
 
-	c.Conn = conn
+	c.transport = transport
 	return nil
 }
+
+// matchPeerByRemoteAddr returns the name of the peer whose configured host resolves to the IP of addr
+func matchPeerByRemoteAddr(peers map[string]string, addr net.Addr) (name string, ok bool) {
+	udpAddr, isUDP := addr.(*net.UDPAddr)
+	if !isUDP {
+		return "", false
+	}
+
+	for peerName, peerAddress := range peers {
+		host, _, err := net.SplitHostPort(peerAddress)
+		if err != nil {
+			continue
+		}
+		ips, err := net.LookupIP(host)
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			if ip.Equal(udpAddr.IP) {
+				return peerName, true
+			}
+		}
+	}
+
+	return "", false
+}
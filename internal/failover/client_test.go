@@ -3,9 +3,17 @@ package failover
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/log"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
 	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 )
 
@@ -22,6 +30,15 @@ func newTestClient(mock solana.ClientInterface) *Client {
 	return c
 }
 
+// testActiveNodeInfo returns active node info with a throwaway active identity
+func testActiveNodeInfo(t *testing.T) *NodeInfo {
+	active, err := identities.NewIdentityFromPubkey(solanago.NewWallet().PublicKey().String())
+	if err != nil {
+		t.Fatalf("failed to create identity: %v", err)
+	}
+	return &NodeInfo{Identities: &identities.Identities{Active: active}}
+}
+
 // slotSequenceMock builds a MockClient whose GetCurrentSlot returns successive
 // values from the provided slice. Once the slice is exhausted it repeats the
 // last value, so the slot appears stable until the test is done.
@@ -49,8 +66,8 @@ func TestWaitUntilStartOfNextSlot_SlotChangesImmediately(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got != 11 {
-		t.Errorf("expected slot 11, got %d", got)
+	if got.Slot != 11 {
+		t.Errorf("expected slot 11, got %d", got.Slot)
 	}
 }
 
@@ -67,8 +84,8 @@ func TestWaitUntilStartOfNextSlot_SlotChangesAfterSeveralPolls(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got != 51 {
-		t.Errorf("expected slot 51, got %d", got)
+	if got.Slot != 51 {
+		t.Errorf("expected slot 51, got %d", got.Slot)
 	}
 }
 
@@ -84,8 +101,8 @@ func TestWaitUntilStartOfNextSlot_SkippedSlot(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got != 102 {
-		t.Errorf("expected slot 102, got %d", got)
+	if got.Slot != 102 {
+		t.Errorf("expected slot 102, got %d", got.Slot)
 	}
 }
 
@@ -135,7 +152,203 @@ func TestWaitUntilStartOfNextSlot_RPCErrorsDuringPollingAreRetried(t *testing.T)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got != 201 {
-		t.Errorf("expected slot 201, got %d", got)
+	if got.Slot != 201 {
+		t.Errorf("expected slot 201, got %d", got.Slot)
+	}
+}
+
+// TestWaitUntilSlot_ReturnsOnceTargetReached checks that waitUntilSlot reports progress
+// while below the target and returns as soon as the target slot is seen.
+func TestWaitUntilSlot_ReturnsOnceTargetReached(t *testing.T) {
+	mock := slotSequenceMock([]uint64{95, 98, 100, 101})
+	c := newTestClient(mock)
+
+	var progress []uint64
+	err := c.waitUntilSlot(c.ctx, 100, func(slot uint64) {
+		progress = append(progress, slot)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(progress) != 2 || progress[0] != 95 || progress[1] != 98 {
+		t.Errorf("expected progress [95 98], got %v", progress)
+	}
+}
+
+// TestWaitUntilSlot_Cancelled checks that waitUntilSlot stops when its context is cancelled
+func TestWaitUntilSlot_Cancelled(t *testing.T) {
+	c := newTestClient(slotSequenceMock([]uint64{1}))
+	c.cancel()
+
+	if err := c.waitUntilSlot(c.ctx, 100, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestWaitForLeaderFreeWindow_NoneLongEnough checks that the failover is refused when no
+// leader-free window in the scanned schedule is long enough
+func TestWaitForLeaderFreeWindow_NoneLongEnough(t *testing.T) {
+	now := time.Now()
+	mock := solana.NewMockClient().WithGetLeaderFreeWindows(func(pubkey solanago.PublicKey) (*solana.LeaderFreeWindows, error) {
+		return &solana.LeaderFreeWindows{
+			IsOnLeaderSchedule: true,
+			ScanEndSlot:        2000,
+			Windows: []solana.LeaderFreeWindow{
+				{StartSlot: 1000, EndSlot: 1100, StartTime: now, EndTime: now.Add(40 * time.Second)},
+			},
+		}, nil
+	})
+	c := newTestClient(mock)
+	c.activeNodeInfo = testActiveNodeInfo(t)
+	c.waitForWindow = 10 * time.Minute
+
+	err := c.waitForLeaderFreeWindow()
+	if err == nil {
+		t.Fatal("expected error when no window is long enough")
+	}
+	if !strings.Contains(err.Error(), "no leader-free window of at least 10m0s") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForLeaderFreeWindow_NotOnSchedule checks that there is nothing to wait for when the
+// active identity has no leader slots
+func TestWaitForLeaderFreeWindow_NotOnSchedule(t *testing.T) {
+	c := newTestClient(solana.NewMockClient())
+	c.activeNodeInfo = testActiveNodeInfo(t)
+	c.waitForWindow = 10 * time.Minute
+
+	if err := c.waitForLeaderFreeWindow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestRestoreTowerFile checks the tower file sent back after an unverified handover is written
+// over this node's tower file, and refused when its hash doesn't match
+func TestRestoreTowerFile(t *testing.T) {
+	towerFile := filepath.Join(t.TempDir(), "tower-1_9-active.bin")
+	if err := os.WriteFile(towerFile, []byte("old tower"), 0644); err != nil {
+		t.Fatalf("failed to write tower file: %v", err)
+	}
+
+	c := newTestClient(solana.NewMockClient())
+	c.failoverStream = &Stream{}
+	c.failoverStream.SetActiveNodeInfo(&NodeInfo{TowerFile: towerFile})
+	passiveNodeInfo := c.failoverStream.GetPassiveNodeInfo()
+	passiveNodeInfo.TowerFileBytes = []byte("new tower")
+	passiveNodeInfo.TowerFileHash = "xxh3:0"
+
+	if err := c.restoreTowerFile(); err == nil || !strings.Contains(err.Error(), "tower file hash mismatch") {
+		t.Fatalf("expected hash mismatch error, got: %v", err)
+	}
+
+	passiveNodeInfo.setTowerFileHash()
+	if err := c.restoreTowerFile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := os.ReadFile(towerFile)
+	if err != nil {
+		t.Fatalf("failed to read tower file: %v", err)
+	}
+	if string(got) != "new tower" {
+		t.Errorf("expected restored tower file, got %q", got)
+	}
+}
+
+// fakePassiveReady answers the client's ready to switch message on server with errorMessage, or
+// confirms it when empty, and returns the active slot the client sent
+func fakePassiveReady(t *testing.T, server *Stream, errorMessage string) <-chan uint64 {
+	activeSlot := make(chan uint64, 1)
+	go func() {
+		if err := server.Decode(); err != nil {
+			return
+		}
+		if !server.GetReadyToSwitch() {
+			t.Errorf("expected ready to switch message")
+		}
+		activeSlot <- server.GetActiveNodeInfo().Slot
+		server.SetErrorMessage(errorMessage)
+		_ = server.Encode()
+	}()
+	return activeSlot
+}
+
+// switchPointTestClient builds a client whose cluster advances a slot every slotDuration from
+// startSlot, with a pre hook when active that sleeps for hookSleep and a passive node that confirms
+// it is ready to switch
+func switchPointTestClient(t *testing.T, startSlot uint64, slotDuration time.Duration, hookSleep string) *Client {
+	start := time.Now()
+	mock := solana.NewMockClient().WithGetCurrentSlot(func() (uint64, error) {
+		return startSlot + uint64(time.Since(start)/slotDuration), nil
+	})
+
+	c := newTestClient(mock)
+	c.activeNodeInfo = testActiveNodeInfo(t)
+	c.activeNodeInfo.Identities.Passive = c.activeNodeInfo.Identities.Active
+	client, server := newTestStreamPair(t)
+	fakePassiveReady(t, server, "")
+	c.failoverStream = client
+	c.failoverStream.GetPassiveNodeInfo().Identities = c.activeNodeInfo.Identities
+	c.hooks = hooks.FailoverHooks{Pre: hooks.PreHooks{WhenActive: hooks.Hooks{
+		{Name: "slow", Command: "sleep", Args: []string{hookSleep}, MustSucceed: true},
+	}}}
+	if err := c.hooks.Configure(); err != nil {
+		t.Fatalf("failed to configure hooks: %v", err)
+	}
+	return c
+}
+
+// TestWaitForSwitchPoint_SwitchesInScheduledSlot checks that pre hooks run before the final
+// approach to a slot schedule, so the switch still lands in the scheduled slot
+func TestWaitForSwitchPoint_SwitchesInScheduledSlot(t *testing.T) {
+	c := switchPointTestClient(t, 1000, 100*time.Millisecond, "0.3")
+
+	transition, err := c.waitForSwitchPoint(Schedule{AtSlot: 1010})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transition.Slot != 1010 {
+		t.Errorf("expected switch in scheduled slot 1010, got %d", transition.Slot)
+	}
+}
+
+// TestWaitForSwitchPoint_MissedScheduledSlot checks that the failover is refused when the pre
+// hooks run past the scheduled slot
+func TestWaitForSwitchPoint_MissedScheduledSlot(t *testing.T) {
+	c := switchPointTestClient(t, 1000, 100*time.Millisecond, "0.5")
+
+	_, err := c.waitForSwitchPoint(Schedule{AtSlot: 1003})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "missed scheduled slot 1003") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestConfirmPassiveReady checks the active node sends its current slot when it is ready to switch,
+// and that the passive node refusing stops the failover
+func TestConfirmPassiveReady(t *testing.T) {
+	mock := solana.NewMockClient().WithGetLocalNodeSlot(func(commitment rpc.CommitmentType) (uint64, error) {
+		return 5000, nil
+	})
+	c := newTestClient(mock)
+	c.activeNodeInfo = testActiveNodeInfo(t)
+	c.slotCommitment = rpc.CommitmentProcessed
+
+	client, server := newTestStreamPair(t)
+	c.failoverStream = client
+	activeSlot := fakePassiveReady(t, server, "")
+	if err := c.confirmPassiveReady(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-activeSlot; got != 5000 {
+		t.Errorf("expected current active slot 5000 sent, got %d", got)
+	}
+
+	fakePassiveReady(t, server, "server preflight failed: this node is 200 slots behind")
+	err := c.confirmPassiveReady()
+	if err == nil || !strings.Contains(err.Error(), "passive node is no longer ready: server preflight failed") {
+		t.Fatalf("expected passive node refusal, got: %v", err)
 	}
 }
@@ -1,8 +1,15 @@
 package failover
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
 	"net"
 	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/quic-go/quic-go"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 )
 
 // basicPacketConn wraps a net.PacketConn and hides *net.UDPConn from quic-go.
@@ -35,3 +42,92 @@ func newBasicPacketConn(addr string) (*basicPacketConn, error) {
 	}
 	return &basicPacketConn{conn: udpConn}, nil
 }
+
+// newListenerTLSConfig returns the TLS config for the listening side of a failover connection.
+// When mtlsConfig is nil an ephemeral self-signed certificate is generated (no client auth).
+func newListenerTLSConfig(mtlsConfig *tls.Config) (*tls.Config, error) {
+	if mtlsConfig != nil {
+		cloned := mtlsConfig.Clone()
+		cloned.NextProtos = []string{ProtocolName}
+		return cloned, nil
+	}
+
+	tlsCert, err := utils.GenerateTLSCertificate()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+		NextProtos:   []string{ProtocolName},
+	}, nil
+}
+
+// newDialerTLSConfig returns the TLS config for the dialing side of a failover connection.
+// When mtlsConfig is nil, server verification is skipped.
+func newDialerTLSConfig(mtlsConfig *tls.Config) *tls.Config {
+	if mtlsConfig != nil {
+		cloned := mtlsConfig.Clone()
+		cloned.NextProtos = []string{ProtocolName}
+		return cloned
+	}
+	return &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // intentional fallback when mTLS is not configured
+		NextProtos:         []string{ProtocolName},
+	}
+}
+
+// listenQUIC creates a QUIC listener on the given port using a basicPacketConn
+func listenQUIC(port int, tlsConfig *tls.Config, quicConfig *quic.Config) (*quic.Transport, *quic.Listener, error) {
+	wrapped, err := newBasicPacketConn(fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create UDP socket: %v", err)
+	}
+	transport := &quic.Transport{Conn: wrapped}
+
+	listener, err := transport.Listen(tlsConfig, quicConfig)
+	if err != nil {
+		wrapped.Close()
+		return nil, nil, fmt.Errorf("failed to create listener: %v", err)
+	}
+
+	return transport, listener, nil
+}
+
+// dialQUIC dials a QUIC listener at address. It uses a basicPacketConn wrapper to avoid quic-go's
+// OOB (recvmsg/sendmsg) optimizations that fail on virtual network interfaces like Tailscale/WireGuard.
+// The returned transport must be closed by the caller once the connection is no longer needed.
+func dialQUIC(ctx context.Context, address string, tlsConfig *tls.Config, quicConfig *quic.Config) (*quic.Transport, *quic.Conn, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve address %s: %w", address, err)
+	}
+
+	wrapped, err := newBasicPacketConn(":0")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create UDP socket: %w", err)
+	}
+
+	tr := &quic.Transport{Conn: wrapped}
+	conn, err := tr.Dial(ctx, udpAddr, tlsConfig, quicConfig)
+	if err != nil {
+		tr.Close()
+		return nil, nil, err
+	}
+
+	return tr, conn, nil
+}
+
+// logPeerCertificate logs the verified mTLS certificate presented by the peer of conn
+func logPeerCertificate(logger *log.Logger, conn *quic.Conn, peerRole string) {
+	tlsState := conn.ConnectionState().TLS
+	if len(tlsState.PeerCertificates) == 0 {
+		return
+	}
+	peer := tlsState.PeerCertificates[0]
+	logger.Info(fmt.Sprintf("mTLS: %s certificate verified", peerRole),
+		"remote_addr", conn.RemoteAddr().String(),
+		"subject", peer.Subject.String(),
+		"issuer", peer.Issuer.String(),
+		"expires", peer.NotAfter,
+	)
+}
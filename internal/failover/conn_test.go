@@ -0,0 +1,250 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+// TestListenAndDialQUIC verifies the shared listen/dial helpers used by both push and
+// pull mode can establish a connection and exchange data over a stream.
+func TestListenAndDialQUIC(t *testing.T) {
+	listenerTLS, err := newListenerTLSConfig(nil)
+	if err != nil {
+		t.Fatalf("failed to build listener TLS config: %v", err)
+	}
+
+	transport, listener, err := listenQUIC(0, listenerTLS, &quic.Config{MaxIdleTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer transport.Close()
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	port := listener.Addr().(*net.UDPAddr).Port
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept(ctx)
+		if err != nil {
+			return
+		}
+		stream, err := conn.AcceptStream(ctx)
+		if err != nil {
+			return
+		}
+		buf := make([]byte, 1)
+		if _, err := io.ReadFull(stream, buf); err == nil {
+			received <- buf
+		}
+	}()
+
+	dialTransport, conn, err := dialQUIC(ctx, fmt.Sprintf("127.0.0.1:%d", port), newDialerTLSConfig(nil), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer dialTransport.Close()
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	if _, err := stream.Write([]byte{MessageTypeFailoverInitiateRequest}); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got[0] != MessageTypeFailoverInitiateRequest {
+			t.Errorf("expected message type %d, got %d", MessageTypeFailoverInitiateRequest, got[0])
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for data on the listening side")
+	}
+}
+
+// newTestStreamPair connects a client and server failover Stream over a loopback QUIC connection
+func newTestStreamPair(t *testing.T) (client *Stream, server *Stream) {
+	t.Helper()
+	listenerTLS, err := newListenerTLSConfig(nil)
+	if err != nil {
+		t.Fatalf("failed to build listener TLS config: %v", err)
+	}
+	transport, listener, err := listenQUIC(0, listenerTLS, nil)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { transport.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accepted := make(chan *quic.Stream, 1)
+	go func() {
+		conn, err := listener.Accept(ctx)
+		if err != nil {
+			return
+		}
+		stream, err := conn.AcceptStream(ctx)
+		if err != nil {
+			return
+		}
+		accepted <- stream
+	}()
+
+	port := listener.Addr().(*net.UDPAddr).Port
+	dialTransport, conn, err := dialQUIC(ctx, fmt.Sprintf("127.0.0.1:%d", port), newDialerTLSConfig(nil), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { dialTransport.Close() })
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	client = NewFailoverStream(stream)
+
+	// the listening side only sees the stream once data arrives on it
+	if err := client.Encode(); err != nil {
+		t.Fatalf("failed to send first message: %v", err)
+	}
+	select {
+	case s := <-accepted:
+		server = NewFailoverStream(s)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the stream to be accepted")
+	}
+	if err := server.Decode(); err != nil {
+		t.Fatalf("failed to receive first message: %v", err)
+	}
+	return client, server
+}
+
+// TestMatchPeerByRemoteAddr checks that pull mode only accepts connections from configured peers
+func TestMatchPeerByRemoteAddr(t *testing.T) {
+	peers := map[string]string{
+		"backup":  "127.0.0.1:9898",
+		"invalid": "not-a-host-port",
+	}
+
+	name, ok := matchPeerByRemoteAddr(peers, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 51234})
+	if !ok || name != "backup" {
+		t.Errorf("expected to match peer backup, got %q (ok=%t)", name, ok)
+	}
+
+	if name, ok := matchPeerByRemoteAddr(peers, &net.UDPAddr{IP: net.ParseIP("10.1.2.3"), Port: 51234}); ok {
+		t.Errorf("expected no match for unknown address, got %q", name)
+	}
+}
+
+// freeUDPPort returns a loopback UDP port that was free at the time of the call
+func freeUDPPort(t *testing.T) int {
+	t.Helper()
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer pc.Close()
+	return pc.LocalAddr().(*net.UDPAddr).Port
+}
+
+// TestWaitForPassivePeer_AcceptsConfiguredPeer checks that pull mode takes a connection from a
+// configured peer and records it as the server to talk to
+func TestWaitForPassivePeer_AcceptsConfiguredPeer(t *testing.T) {
+	port := freeUDPPort(t)
+	c := newTestClient(nil)
+	defer c.closeConnection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dialed := make(chan *quic.Transport, 1)
+	go func() {
+		// retry until the listener is up
+		for ctx.Err() == nil {
+			tr, _, err := dialQUIC(ctx, fmt.Sprintf("127.0.0.1:%d", port), newDialerTLSConfig(nil), nil)
+			if err == nil {
+				dialed <- tr
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	err := c.waitForPassivePeer(ClientConfig{
+		ListenPort: port,
+		PullPeers:  map[string]string{"backup": fmt.Sprintf("127.0.0.1:%d", port)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case tr := <-dialed:
+		defer tr.Close()
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the peer to dial")
+	}
+
+	if c.serverName != "backup" {
+		t.Errorf("expected server name backup, got %q", c.serverName)
+	}
+	if c.Conn == nil || c.transport == nil {
+		t.Fatal("expected connection and transport to be set")
+	}
+}
+
+// TestWaitForPassivePeer_RejectsUnknownPeer checks that pull mode closes connections from
+// addresses that aren't a configured peer and keeps waiting
+func TestWaitForPassivePeer_RejectsUnknownPeer(t *testing.T) {
+	port := freeUDPPort(t)
+	c := newTestClient(nil)
+	defer c.closeConnection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rejected := make(chan error, 1)
+	go func() {
+		defer c.cancel()
+		for ctx.Err() == nil {
+			tr, conn, err := dialQUIC(ctx, fmt.Sprintf("127.0.0.1:%d", port), newDialerTLSConfig(nil), nil)
+			if err != nil {
+				time.Sleep(20 * time.Millisecond)
+				continue
+			}
+			defer tr.Close()
+			_, err = conn.AcceptStream(ctx)
+			rejected <- err
+			return
+		}
+	}()
+
+	err := c.waitForPassivePeer(ClientConfig{
+		ListenPort: port,
+		PullPeers:  map[string]string{"backup": "10.1.2.3:9898"},
+	})
+	if err == nil {
+		t.Fatal("expected waiting to end with an error once cancelled, got nil")
+	}
+	if c.Conn != nil {
+		t.Error("expected no connection to be accepted")
+	}
+
+	select {
+	case err := <-rejected:
+		var appErr *quic.ApplicationError
+		if !errors.As(err, &appErr) || appErr.ErrorMessage != "unknown peer" {
+			t.Errorf("expected connection closed as unknown peer, got: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the connection to be rejected")
+	}
+}
@@ -32,7 +32,9 @@ const (
 	// History:
 	//   1 = original (pre-v0.1.18) — no version byte, implicit
 	//   2 = version byte added after msg_type / before first gob frame (v0.1.18+)
-	WireProtocolVersion byte = 2
+	//   3 = active node always reports going passive (or aborting), also with --skip-tower-sync
+	//   4 = ready-to-switch round trip after the active node's waits, before it goes passive
+	WireProtocolVersion byte = 4
 )
 
 // hookEnvMapParams is the parameters for the hook environment map
@@ -40,4 +42,6 @@ type hookEnvMapParams struct {
 	isDryRunFailover bool
 	isPreFailover    bool
 	isPostFailover   bool
+	phase            string
+	err              error // the error behind on_failure/on_abort/rollback hooks
 }
@@ -0,0 +1,121 @@
+package failover
+
+import (
+	"context"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+)
+
+// hookContextNode describes a node in the failover context hooks receive on stdin. Private keys
+// and tower file bytes are deliberately left out.
+type hookContextNode struct {
+	Hostname                       string   `json:"hostname"`
+	PublicIP                       string   `json:"public_ip"`
+	Cluster                        string   `json:"cluster"`
+	ActiveIdentityPubkey           string   `json:"active_identity_pubkey"`
+	PassiveIdentityPubkey          string   `json:"passive_identity_pubkey"`
+	Client                         string   `json:"client,omitempty"`
+	ClientVersion                  string   `json:"client_version"`
+	ClientVersionLocalRPC          string   `json:"client_version_local_rpc"`
+	SolanaValidatorFailoverVersion string   `json:"solana_validator_failover_version"`
+	RPCAddress                     string   `json:"rpc_address"`
+	TowerFile                      string   `json:"tower_file"`
+	TowerFileSizeBytes             int64    `json:"tower_file_size_bytes"`
+	TowerFileHash                  string   `json:"tower_file_hash,omitempty"`
+	SetIdentityCommand             string   `json:"set_identity_command"`
+	SetIdentityArgv                []string `json:"set_identity_argv,omitempty"`
+	AdminRPCSocket                 string   `json:"admin_rpc_socket,omitempty"`
+	SlotDurationMs                 int64    `json:"slot_duration_ms"`
+}
+
+// hookContextTimings are the failover step timestamps recorded so far - unset steps are null
+type hookContextTimings struct {
+	ActiveSetIdentityStart   *time.Time `json:"active_set_identity_start"`
+	ActiveSetIdentityEnd     *time.Time `json:"active_set_identity_end"`
+	ActiveIdentityConfirmed  *time.Time `json:"active_identity_confirmed"`
+	TowerSyncStart           *time.Time `json:"tower_sync_start"`
+	TowerSyncEnd             *time.Time `json:"tower_sync_end"`
+	PassiveSetIdentityStart  *time.Time `json:"passive_set_identity_start"`
+	PassiveSetIdentityEnd    *time.Time `json:"passive_set_identity_end"`
+	PassiveIdentityConfirmed *time.Time `json:"passive_identity_confirmed"`
+}
+
+// hookContext is the failover context hooks receive on stdin
+type hookContext struct {
+	IsDryRun          bool               `json:"is_dry_run"`
+	SkipTowerSync     bool               `json:"skip_tower_sync"`
+	Schedule          string             `json:"schedule,omitempty"`
+	ActiveNode        hookContextNode    `json:"active_node"`
+	PassiveNode       hookContextNode    `json:"passive_node"`
+	FailoverStartSlot uint64             `json:"failover_start_slot,omitempty"`
+	FailoverEndSlot   uint64             `json:"failover_end_slot,omitempty"`
+	Timings           hookContextTimings `json:"timings"`
+}
+
+// newHookContextNode describes a node for hooks
+func newHookContextNode(n NodeInfo) hookContextNode {
+	node := hookContextNode{
+		Hostname:                       n.Hostname,
+		PublicIP:                       n.PublicIP,
+		Cluster:                        n.Cluster,
+		Client:                         n.Client,
+		ClientVersion:                  n.ClientVersion,
+		ClientVersionLocalRPC:          n.ClientVersionRPC,
+		SolanaValidatorFailoverVersion: n.SolanaValidatorFailoverVersion,
+		RPCAddress:                     n.RPCAddress,
+		TowerFile:                      n.TowerFile,
+		TowerFileSizeBytes:             n.TowerFileSizeBytes,
+		TowerFileHash:                  n.TowerFileHash,
+		SetIdentityCommand:             n.SetIdentityCommand,
+		SetIdentityArgv:                n.SetIdentityCmd.Argv,
+		AdminRPCSocket:                 n.AdminRPCSocket,
+		SlotDurationMs:                 n.SlotDuration.Duration.Milliseconds(),
+	}
+	if n.Identities != nil {
+		node.ActiveIdentityPubkey = n.Identities.Active.PubKey()
+		node.PassiveIdentityPubkey = n.Identities.Passive.PubKey()
+	}
+	return node
+}
+
+// timeOrNil returns nil for the zero time so unset timings marshal as null
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// HookContext returns ctx carrying the current failover state, which command hooks run with it
+// receive as JSON on stdin
+func (s *Stream) HookContext(ctx context.Context) context.Context {
+	return hooks.WithFailoverContext(ctx, s.hookContext())
+}
+
+// hookContext builds the failover context for hooks from the current stream message state
+func (s *Stream) hookContext() hookContext {
+	m := s.message
+	hc := hookContext{
+		IsDryRun:          m.IsDryRunFailover,
+		SkipTowerSync:     m.SkipTowerSync,
+		ActiveNode:        newHookContextNode(m.ActiveNodeInfo),
+		PassiveNode:       newHookContextNode(m.PassiveNodeInfo),
+		FailoverStartSlot: m.FailoverStartSlot,
+		FailoverEndSlot:   m.FailoverEndSlot,
+		Timings: hookContextTimings{
+			ActiveSetIdentityStart:   timeOrNil(m.ActiveNodeSetIdentityStartTime),
+			ActiveSetIdentityEnd:     timeOrNil(m.ActiveNodeSetIdentityEndTime),
+			ActiveIdentityConfirmed:  timeOrNil(m.ActiveNodeIdentityConfirmedTime),
+			TowerSyncStart:           timeOrNil(m.ActiveNodeSyncTowerFileStartTime),
+			TowerSyncEnd:             timeOrNil(m.PassiveNodeSyncTowerFileEndTime),
+			PassiveSetIdentityStart:  timeOrNil(m.PassiveNodeSetIdentityStartTime),
+			PassiveSetIdentityEnd:    timeOrNil(m.PassiveNodeSetIdentityEndTime),
+			PassiveIdentityConfirmed: timeOrNil(m.PassiveNodeIdentityConfirmedTime),
+		},
+	}
+	if m.Schedule.IsSet() {
+		hc.Schedule = m.Schedule.String()
+	}
+	return hc
+}
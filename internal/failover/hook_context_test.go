@@ -0,0 +1,42 @@
+package failover
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamHookContext(t *testing.T) {
+	key := solanago.NewWallet().PrivateKey
+	ids := &identities.Identities{
+		Active:  &identities.Identity{Key: key, PubKeyStr: key.PublicKey().String()},
+		Passive: &identities.Identity{PubKeyStr: "PASSIVE"},
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &Stream{message: Message{
+		ActiveNodeInfo:                 NodeInfo{Hostname: "london", Identities: ids, TowerFileBytes: []byte("tower"), Cluster: "testnet"},
+		PassiveNodeInfo:                NodeInfo{Hostname: "chicago", Identities: ids},
+		FailoverStartSlot:              100,
+		ActiveNodeSetIdentityStartTime: start,
+	}}
+
+	doc, err := json.Marshal(s.hookContext())
+	require.NoError(t, err)
+	assert.NotContains(t, string(doc), key.String())
+	assert.NotContains(t, string(doc), "tower_file_bytes")
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(doc, &got))
+	assert.Equal(t, "london", got["active_node"].(map[string]any)["hostname"])
+	assert.Equal(t, "testnet", got["active_node"].(map[string]any)["cluster"])
+	assert.Equal(t, key.PublicKey().String(), got["active_node"].(map[string]any)["active_identity_pubkey"])
+	assert.Equal(t, float64(100), got["failover_start_slot"])
+	timings := got["timings"].(map[string]any)
+	assert.Equal(t, "2026-01-02T03:04:05Z", timings["active_set_identity_start"])
+	assert.Nil(t, timings["passive_set_identity_end"])
+}
@@ -0,0 +1,45 @@
+package failover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+const (
+	// identityConfirmTimeout is how long a validator has to report its new identity after a
+	// successful set-identity command before the switch is treated as failed
+	identityConfirmTimeout = 10 * time.Second
+	// identityConfirmInterval is how often the local validator's identity is polled
+	identityConfirmInterval = 100 * time.Millisecond
+)
+
+// waitForLocalIdentity polls the local validator's getIdentity RPC until it reports pubkey,
+// returning the time it first did. A set-identity command exiting zero is not proof the validator
+// switched - this is.
+func waitForLocalIdentity(ctx context.Context, client solana.ClientInterface, pubkey string, timeout, interval time.Duration) (confirmedAt time.Time, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var reported string
+	for {
+		reported, err = client.GetLocalNodeIdentity()
+		if err == nil && reported == pubkey {
+			return time.Now(), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return time.Time{}, fmt.Errorf("local validator did not report identity %s within %s: %w", pubkey, timeout, err)
+			}
+			return time.Time{}, fmt.Errorf("local validator still reports identity %s after %s, expected %s", reported, timeout, pubkey)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+// identitySequenceMock builds a MockClient whose GetLocalNodeIdentity returns successive values
+// from the provided slice, repeating the last one once it is exhausted
+func identitySequenceMock(identities []string) *solana.MockClient {
+	i := 0
+	return solana.NewMockClient().WithGetLocalNodeIdentity(func() (string, error) {
+		v := identities[i]
+		if i < len(identities)-1 {
+			i++
+		}
+		return v, nil
+	})
+}
+
+// TestWaitForLocalIdentity_ReportedAfterSeveralPolls checks the normal case where the validator
+// takes a few polls to report its new identity
+func TestWaitForLocalIdentity_ReportedAfterSeveralPolls(t *testing.T) {
+	mock := identitySequenceMock([]string{"old", "old", "new"})
+
+	start := time.Now()
+	confirmedAt, err := waitForLocalIdentity(context.Background(), mock, "new", time.Second, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if confirmedAt.Before(start) {
+		t.Errorf("expected confirmation time after %s, got %s", start, confirmedAt)
+	}
+}
+
+// TestWaitForLocalIdentity_StillOldIdentity checks that a validator that never switches fails
+// once the deadline passes, naming the identity it still reports
+func TestWaitForLocalIdentity_StillOldIdentity(t *testing.T) {
+	mock := identitySequenceMock([]string{"old"})
+
+	_, err := waitForLocalIdentity(context.Background(), mock, "new", 20*time.Millisecond, time.Millisecond)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "still reports identity old") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForLocalIdentity_RPCErrors checks that errors are retried until the deadline and the
+// last one is returned
+func TestWaitForLocalIdentity_RPCErrors(t *testing.T) {
+	boom := errors.New("connection refused")
+	calls := 0
+	mock := solana.NewMockClient().WithGetLocalNodeIdentity(func() (string, error) {
+		calls++
+		return "", boom
+	})
+
+	_, err := waitForLocalIdentity(context.Background(), mock, "new", 20*time.Millisecond, time.Millisecond)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped rpc error, got: %v", err)
+	}
+	if calls < 2 {
+		t.Errorf("expected getIdentity to be retried, got %d calls", calls)
+	}
+}
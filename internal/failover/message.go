@@ -2,6 +2,8 @@ package failover
 
 import (
 	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 )
 
 // Message represents the message data that can be encoded/decoded
@@ -15,15 +17,22 @@ type Message struct {
 	SkipTowerSync                    bool
 	RollbackRequired                 bool
 	ActiveRollbackEnabled            bool
+	VerifyHandover                   bool // the passive node will verify its votes land before completing, rolling both nodes back if not
+	HandoverUnverified               bool // set with RollbackRequired when the passive node may have gone active and has rolled back
+	ReadyToSwitch                    bool // the active node has finished waiting and asks the passive node to re-run its preflight
 	ActiveNodeSetIdentityStartTime   time.Time
 	ActiveNodeSetIdentityEndTime     time.Time
+	ActiveNodeIdentityConfirmedTime  time.Time // when the local validator first reported the passive identity
 	ActiveNodeSyncTowerFileStartTime time.Time
 	ActiveNodeSyncTowerFileEndTime   time.Time
 	PassiveNodeSetIdentityStartTime  time.Time
 	PassiveNodeSetIdentityEndTime    time.Time
+	PassiveNodeIdentityConfirmedTime time.Time // when the local validator first reported the active identity
 	PassiveNodeSyncTowerFileEndTime  time.Time
 	FailoverStartSlot                uint64
+	FailoverStartSlotDetection       solana.SlotTransition // how the active node detected the start of FailoverStartSlot
 	FailoverEndSlot                  uint64
+	Schedule                         Schedule
 	// key is the identity pubkey
 	CreditSamples CreditSamples
 }
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 	"github.com/zeebo/xxh3"
 )
 
@@ -17,11 +19,20 @@ type NodeInfo struct {
 	TowerFileSizeBytes             int64
 	TowerFileBytes                 []byte
 	TowerFileHash                  string
-	SetIdentityCommand             string
+	SetIdentityCommand             string        // SetIdentityCmd shell-quoted, for display
+	SetIdentityCmd                 utils.Command // the command run to set identity
+	AdminRPCSocket                 string        // set when set-identity goes over this admin rpc socket, with SetIdentityCommand as the fallback
+	Client                         string        // client profile in use, e.g. agave or firedancer
 	ClientVersion                  string
 	ClientVersionRPC               string
 	SolanaValidatorFailoverVersion string
 	RPCAddress                     string
+	Cluster                        string                       // solana cluster name from config
+	HookOutputs                    map[string]map[string]string // outputs of hooks run on the node, for the summary
+	SlotDuration                   solana.SlotDuration          // the node's slot time estimate, used for leader-slot timing
+	Slot                           uint64                       // the node's local slot when the failover was requested, 0 when unknown
+	VoteAccount                    solana.VoteAccount           // the active identity's vote account as checked on the passive node
+	AuthorizedVoterSource          string                       // which configured key is the vote account's authorized voter
 }
 
 // SetTowerFileBytes sets the tower file bytes
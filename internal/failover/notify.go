@@ -0,0 +1,46 @@
+package failover
+
+import (
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sol-strategies/solana-validator-failover/internal/notifications"
+)
+
+// notificationNode describes a node for a notification event
+func notificationNode(n NodeInfo) notifications.Node {
+	node := notifications.Node{
+		Name:     n.Hostname,
+		PublicIP: n.PublicIP,
+	}
+	if n.Identities != nil {
+		node.ActivePubkey = n.Identities.Active.PubKey()
+		node.PassivePubkey = n.Identities.Passive.PubKey()
+	}
+	return node
+}
+
+// NotificationEvent builds a notification event from the current stream message state
+func (s *Stream) NotificationEvent(eventType notifications.EventType, detail string) notifications.Event {
+	event := notifications.Event{
+		Type:     eventType,
+		IsDryRun: s.message.IsDryRunFailover,
+		From:     notificationNode(s.message.ActiveNodeInfo),
+		To:       notificationNode(s.message.PassiveNodeInfo),
+		Detail:   detail,
+	}
+	if s.message.Schedule.IsSet() {
+		event.Schedule = s.message.Schedule.String()
+	}
+	return event
+}
+
+// CompletedNotificationEvent builds the completed notification event, including timing and the
+// post-failover summary table with styling stripped
+func (s *Stream) CompletedNotificationEvent(summary SummaryData, renderedSummary string) notifications.Event {
+	event := s.NotificationEvent(notifications.EventCompleted, "")
+	event.TotalDuration = summary.TotalDuration
+	event.StartSlot = summary.FailoverStartSlot
+	event.EndSlot = summary.FailoverEndSlot
+	event.Slots = summary.SlotsDuration
+	event.Summary = ansi.Strip(renderedSummary)
+	return event
+}
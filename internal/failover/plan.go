@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 )
 
@@ -17,6 +18,8 @@ import (
 type PlanData struct {
 	IsDryRun            bool
 	SkipTowerSync       bool
+	Schedule            Schedule
+	SlotDuration        solana.SlotDuration // active node's slot time estimate, which its leader-slot checks use
 	ActiveNodeInfo      NodeInfo
 	PassiveNodeInfo     NodeInfo
 	AppVersion          string
@@ -99,6 +102,18 @@ func RenderFailoverPlan(data PlanData) (string, error) {
 				lines = append(lines, style.RenderMutedString(fmt.Sprintf("%d hooks (%s)", total, strings.Join(parts, ", "))))
 			}
 
+			var phaseParts []string
+			phaseTotal := 0
+			for _, phase := range hooks.Phases {
+				if n := h.Phase(phase).Len(); n > 0 {
+					phaseTotal += n
+					phaseParts = append(phaseParts, fmt.Sprintf("%d %s", n, phase))
+				}
+			}
+			if phaseTotal > 0 {
+				lines = append(lines, style.RenderMutedString(fmt.Sprintf("%d phase hooks (%s)", phaseTotal, strings.Join(phaseParts, ", "))))
+			}
+
 			if rollback.Enabled {
 				lines = append(lines, style.RenderMutedString("1 rollback plan (if failover fails)"))
 			}
@@ -124,7 +139,9 @@ func RenderFailoverPlan(data PlanData) (string, error) {
 			var result strings.Builder
 			for i, hook := range hooksList {
 				hookCmd, err := hooks.RenderHookCommand(hook, hookData)
-				if err != nil {
+				if err != nil && hook.Type == hooks.HookTypeHTTP {
+					hookCmd = hook.HTTP.URL
+				} else if err != nil {
 					hookCmd = hook.Command
 					if len(hook.Args) > 0 {
 						hookCmd += " " + strings.Join(hook.Args, " ")
@@ -134,10 +151,22 @@ func RenderFailoverPlan(data PlanData) (string, error) {
 				if hook.MustSucceed {
 					mustSucceedStr = style.RenderLightWarningString(" (must succeed)")
 				}
-				fmt.Fprintf(&result, "    [%d/%d] %s%s: %s\n",
+				skippedStr := ""
+				if shouldRun, reason, err := hook.ShouldRun(hookData); err != nil {
+					skippedStr = style.RenderLightWarningString(fmt.Sprintf(" (%v)", err))
+				} else if !shouldRun {
+					skippedStr = style.RenderMutedString(" (skipped: " + reason + ")")
+				}
+				groupStr := ""
+				if group := hook.ParallelGroup(); group != "" {
+					groupStr = style.RenderMutedString(" (parallel: " + group + ")")
+				}
+				fmt.Fprintf(&result, "    [%d/%d] %s%s%s%s: %s\n",
 					i+1, len(hooksList),
 					hooks.PrefixStyle.Render(hook.Name),
+					groupStr,
 					mustSucceedStr,
+					skippedStr,
 					hooks.PrefixStyle.Render(hookCmd))
 			}
 			return result.String()
@@ -165,8 +194,11 @@ func RenderFailoverPlan(data PlanData) (string, error) {
       {{ Warning "~" }} {{ Muted "role      =" }} {{ Active (printf "%-15s" "active") false }}  {{ Muted "→" }}  {{ Passive (printf "%-15s" "passive") false }}{{ if .IsDryRun }}  {{ LightGrey "(dry run)" }}{{ end }}
       {{ Warning "~" }} {{ Muted "identity  =" }} {{ Active (printf "%-15s" (truncPubkey .ActiveNodeInfo.Identities.Active.PubKey)) false }}  {{ Muted "→" }}  {{ Passive (printf "%-15s" (truncPubkey .ActiveNodeInfo.Identities.Passive.PubKey)) false }}{{ if .IsDryRun }}  {{ LightGrey "(dry run)" }}{{ end }}
         {{ Muted "ip        =" }} {{ LightGrey .ActiveNodeInfo.PublicIP }}
+{{- if .ActiveNodeInfo.Client }}
+        {{ Muted "client    =" }} {{ LightGrey .ActiveNodeInfo.Client }}{{ end }}
         {{ Muted "version   =" }} {{ LightGrey (FormatVersion .ActiveNodeInfo.ClientVersion .ActiveNodeInfo.ClientVersionRPC) }}
-        {{ Muted "cmd       =" }} {{ LightGrey .ActiveNodeInfo.SetIdentityCommand }}
+        {{ Muted "cmd       =" }} {{ LightGrey .ActiveNodeInfo.SetIdentityCommand }}{{ if .ActiveNodeInfo.SetIdentityCmd.Timeout }} {{ Muted (printf "(timeout %s)" .ActiveNodeInfo.SetIdentityCmd.Timeout) }}{{ end }}{{ if .ActiveNodeInfo.AdminRPCSocket }} {{ Muted "(fallback)" }}
+        {{ Muted "admin_rpc =" }} {{ LightGrey .ActiveNodeInfo.AdminRPCSocket }}{{ end }}
 {{- if not .SkipTowerSync }}
 
   {{ Purple (printf "%d — sync tower file" (Step)) }}
@@ -181,8 +213,14 @@ func RenderFailoverPlan(data PlanData) (string, error) {
       {{ Warning "~" }} {{ Muted "role      =" }} {{ Passive (printf "%-15s" "passive") false }}  {{ Muted "→" }}  {{ Active (printf "%-15s" "active") false }}{{ if .IsDryRun }}  {{ LightGrey "(dry run)" }}{{ end }}
       {{ Warning "~" }} {{ Muted "identity  =" }} {{ Passive (printf "%-15s" (truncPubkey .PassiveNodeInfo.Identities.Passive.PubKey)) false }}  {{ Muted "→" }}  {{ Active (printf "%-15s" (truncPubkey .PassiveNodeInfo.Identities.Active.PubKey)) false }}{{ if .IsDryRun }}  {{ LightGrey "(dry run)" }}{{ end }}
         {{ Muted "ip        =" }} {{ LightGrey .PassiveNodeInfo.PublicIP }}
+{{- if .PassiveNodeInfo.Client }}
+        {{ Muted "client    =" }} {{ LightGrey .PassiveNodeInfo.Client }}{{ end }}
         {{ Muted "version   =" }} {{ LightGrey (FormatVersion .PassiveNodeInfo.ClientVersion .PassiveNodeInfo.ClientVersionRPC) }}
-        {{ Muted "cmd       =" }} {{ LightGrey .PassiveNodeInfo.SetIdentityCommand }}
+{{- if .PassiveNodeInfo.VoteAccount.VotePubkey }}
+        {{ Muted "vote      =" }} {{ LightGrey .PassiveNodeInfo.VoteAccount.VotePubkey }}{{ if .PassiveNodeInfo.VoteAccount.Delinquent }} {{ Warning "(delinquent)" }}{{ end }}
+        {{ Muted "voter     =" }} {{ LightGrey .PassiveNodeInfo.VoteAccount.AuthorizedVoter }} {{ Muted (printf "(%s)" .PassiveNodeInfo.AuthorizedVoterSource) }}{{ end }}
+        {{ Muted "cmd       =" }} {{ LightGrey .PassiveNodeInfo.SetIdentityCommand }}{{ if .PassiveNodeInfo.SetIdentityCmd.Timeout }} {{ Muted (printf "(timeout %s)" .PassiveNodeInfo.SetIdentityCmd.Timeout) }}{{ end }}{{ if .PassiveNodeInfo.AdminRPCSocket }} {{ Muted "(fallback)" }}
+        {{ Muted "admin_rpc =" }} {{ LightGrey .PassiveNodeInfo.AdminRPCSocket }}{{ end }}
 {{- if .Hooks.Post.WhenActive }}
 
   {{ Purple (printf "%d — run hooks %s post-active" (Step) .PassiveNodeInfo.Hostname) }}
@@ -192,11 +230,20 @@ func RenderFailoverPlan(data PlanData) (string, error) {
 {{- if .Rollback.Enabled }}
 
   {{ Warning "Rollback" }} {{ Muted "if failover fails:" }}
-      {{ Warning "!" }} {{ Purple .ActiveNodeInfo.Hostname }} {{ Muted "→" }} {{ Active "active" false }}: {{ LightGrey .Rollback.ToActive.ResolvedCmd }}
-      {{ Warning "!" }} {{ Purple .PassiveNodeInfo.Hostname }} {{ Muted "→" }} {{ Passive "passive" false }}: {{ LightGrey .Rollback.ToPassive.ResolvedCmd }}
+      {{ Warning "!" }} {{ Purple .ActiveNodeInfo.Hostname }} {{ Muted "→" }} {{ Active "active" false }}: {{ LightGrey .Rollback.ToActive.ResolvedCmd.String }}
+      {{ Warning "!" }} {{ Purple .PassiveNodeInfo.Hostname }} {{ Muted "→" }} {{ Passive "passive" false }}: {{ LightGrey .Rollback.ToPassive.ResolvedCmd.String }}
+{{- if and .Rollback.VerifyHandover.Enabled (not .IsDryRun) }}
+      {{ Warning "!" }} {{ Muted (printf "also if %s's votes don't land within %d slots (timeout %s) - tower sent back to %s" .PassiveNodeInfo.Hostname .Rollback.VerifyHandover.WithinSlots .Rollback.VerifyHandover.Timeout .ActiveNodeInfo.Hostname) }}
+{{- end }}
 {{- end }}
   {{ HRule }}
   {{ Purple "   Plan:" }} {{ planSummaryLines .ActiveNodeInfo.Hostname .PassiveNodeInfo.Hostname .SkipTowerSync .Hooks .Rollback }}
+{{- if .Schedule.IsSet }}
+  {{ Purple "   When:" }} {{ Warning .Schedule.String }} {{ Muted "(then leader-slot gap check and pre-hooks)" }}
+{{- end }}
+{{- if .SlotDuration.Duration }}
+  {{ Purple "   Slot:" }} {{ Muted .SlotDuration.String }}
+{{- end }}
   {{ Purple "Version:" }} {{ Muted .AppVersion }}
   {{ if .IsDryRun }}{{ Blue "   Note:" }} {{ Muted "dry run — re-run with" }} {{ LightGrey "--not-a-drill" }} {{ Muted "on the passive node to do for realsies." }}{{ else }}{{ Warning "Warning:" }} {{ Muted "This is a real failover — identities will be changed on both nodes." }}{{ end }}
   {{ HRule }}
@@ -209,6 +256,8 @@ func RenderFailoverPlan(data PlanData) (string, error) {
 	if err := tpl.Execute(&buf, map[string]any{
 		"IsDryRun":        data.IsDryRun,
 		"SkipTowerSync":   data.SkipTowerSync,
+		"Schedule":        data.Schedule,
+		"SlotDuration":    data.SlotDuration,
 		"PassiveNodeInfo": data.PassiveNodeInfo,
 		"ActiveNodeInfo":  data.ActiveNodeInfo,
 		"AppVersion":      data.AppVersion,
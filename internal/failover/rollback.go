@@ -1,44 +1,47 @@
 package failover
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
-	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 )
 
 // RunRollbackToActive is called on the active node (which just switched to passive) to revert to active.
-// It runs the set-identity-to-active command, then post-hooks.
-// Post-hooks always run even if the command failed.
+// It runs pre-hooks, the set-identity-to-active command, then post-hooks.
+// Pre-hooks never block the rollback and post-hooks always run even if the command failed.
 // Returns the set-identity command error (if any); hook errors are logged but not returned.
-func RunRollbackToActive(cfg hooks.RollbackConfig, envMap map[string]string, isDryRun bool, logger *log.Logger) error {
-	return runRollback(cfg.ToActive, envMap, "to-active", isDryRun, logger)
+func RunRollbackToActive(ctx context.Context, cfg hooks.RollbackConfig, envMap map[string]string, isDryRun bool, logger *log.Logger) error {
+	return runRollback(ctx, cfg.ToActive, envMap, cfg.Outputs, "to-active", isDryRun, logger)
 }
 
 // RunRollbackToPassive is called on the passive node (which failed to become active) to re-assert passive.
-// It runs the set-identity-to-passive command, then post-hooks.
-// Post-hooks always run even if the command failed.
+// It runs pre-hooks, the set-identity-to-passive command, then post-hooks.
+// Pre-hooks never block the rollback and post-hooks always run even if the command failed.
 // Returns the set-identity command error (if any); hook errors are logged but not returned.
-func RunRollbackToPassive(cfg hooks.RollbackConfig, envMap map[string]string, isDryRun bool, logger *log.Logger) error {
-	return runRollback(cfg.ToPassive, envMap, "to-passive", isDryRun, logger)
+func RunRollbackToPassive(ctx context.Context, cfg hooks.RollbackConfig, envMap map[string]string, isDryRun bool, logger *log.Logger) error {
+	return runRollback(ctx, cfg.ToPassive, envMap, cfg.Outputs, "to-passive", isDryRun, logger)
 }
 
-func runRollback(dir hooks.RollbackDirectionConfig, envMap map[string]string, dirName string, isDryRun bool, logger *log.Logger) error {
+func runRollback(ctx context.Context, dir hooks.RollbackDirectionConfig, envMap map[string]string, outputs *hooks.Outputs, dirName string, isDryRun bool, logger *log.Logger) error {
 	logger.Warnf("rollback %s: starting", dirName)
 
+	// pre-rollback hooks — errors logged, never block the rollback
+	envMap = withPhase(envMap, hooks.PhaseRollbackPre)
+	for i, hook := range dir.Hooks.Pre {
+		if result, err := hook.Run(ctx, envMap, outputs, hooks.PhaseRollbackPre, i+1, len(dir.Hooks.Pre)); err != nil {
+			logger.Error(fmt.Sprintf("rollback %s: pre-hook %s failed", dirName, hook.Name), "err", err, "attempts", result.String())
+		}
+	}
+
 	// set-identity command
 	var cmdErr error
-	if dir.ResolvedCmd == "" {
+	if dir.ResolvedCmd.IsZero() {
 		logger.Errorf("rollback %s: no command configured — cannot execute rollback set-identity", dirName)
 	} else {
 		logger.Warn(fmt.Sprintf("rollback %s: running set-identity command", dirName), "command", dir.ResolvedCmd)
-		cmdErr = utils.RunCommand(utils.RunCommandParams{
-			CommandSlice: strings.Split(dir.ResolvedCmd, " "),
-			DryRun:       isDryRun,
-			LogDebug:     logger.GetLevel() <= log.DebugLevel,
-		})
+		cmdErr = dir.ResolvedCmd.Run(isDryRun, logger.GetLevel() <= log.DebugLevel)
 		if cmdErr != nil {
 			logger.Error(fmt.Sprintf("rollback %s: set-identity command failed", dirName), "err", cmdErr)
 		} else {
@@ -47,9 +50,10 @@ func runRollback(dir hooks.RollbackDirectionConfig, envMap map[string]string, di
 	}
 
 	// post-rollback hooks — always run, even if cmd failed; errors logged, never fatal
+	envMap = withPhase(envMap, hooks.PhaseRollbackPost)
 	for i, hook := range dir.Hooks.Post {
-		if err := hook.Run(envMap, "rollback-post", i+1, len(dir.Hooks.Post)); err != nil {
-			logger.Error(fmt.Sprintf("rollback %s: post-hook %s failed", dirName, hook.Name), "err", err)
+		if result, err := hook.Run(ctx, envMap, outputs, hooks.PhaseRollbackPost, i+1, len(dir.Hooks.Post)); err != nil {
+			logger.Error(fmt.Sprintf("rollback %s: post-hook %s failed", dirName, hook.Name), "err", err, "attempts", result.String())
 		}
 	}
 
@@ -60,3 +64,13 @@ func runRollback(dir hooks.RollbackDirectionConfig, envMap map[string]string, di
 	logger.Warnf("rollback %s: complete", dirName)
 	return nil
 }
+
+// withPhase returns a copy of envMap with PHASE set
+func withPhase(envMap map[string]string, phase string) map[string]string {
+	m := make(map[string]string, len(envMap)+1)
+	for k, v := range envMap {
+		m[k] = v
+	}
+	m["PHASE"] = phase
+	return m
+}
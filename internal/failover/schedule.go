@@ -0,0 +1,111 @@
+package failover
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// scheduleLeadSlots is how far ahead of a scheduled slot the wait for it stops, so the leader checks
+// and pre hooks run before the final approach - about a minute at 400ms slots
+const scheduleLeadSlots = 150
+
+// Schedule describes when a scheduled failover executes. At most one of AtSlot, AtTime or
+// AtEpochBoundary is set by the operator. An epoch boundary is resolved to AtSlot (the first
+// slot of the next epoch) by the passive node so both nodes agree on the exact slot.
+type Schedule struct {
+	AtSlot          uint64
+	AtTime          time.Time
+	AtEpochBoundary bool
+	Epoch           uint64 // epoch starting at AtSlot, set when AtEpochBoundary is resolved
+}
+
+// NewSchedule builds a Schedule from the run flags, at most one of which may be set.
+// atTime must be an RFC3339 timestamp, e.g. 2025-06-01T14:00:00Z
+func NewSchedule(atSlot uint64, atTime string, atEpochBoundary bool) (schedule Schedule, err error) {
+	set := 0
+	if atSlot > 0 {
+		set++
+		schedule.AtSlot = atSlot
+	}
+	if atTime != "" {
+		set++
+		schedule.AtTime, err = time.Parse(time.RFC3339, atTime)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid --at-time %q - must be RFC3339 e.g. 2025-06-01T14:00:00Z: %w", atTime, err)
+		}
+	}
+	if atEpochBoundary {
+		set++
+		schedule.AtEpochBoundary = true
+	}
+	if set > 1 {
+		return Schedule{}, fmt.Errorf("only one of --at-slot, --at-time or --at-epoch-boundary may be set")
+	}
+	return schedule, nil
+}
+
+// IsSet returns true if a failover time has been scheduled
+func (s Schedule) IsSet() bool {
+	return s.AtSlot > 0 || !s.AtTime.IsZero() || s.AtEpochBoundary
+}
+
+// Matches returns true if both schedules ask for the same point in time
+func (s Schedule) Matches(other Schedule) bool {
+	if s.AtEpochBoundary || other.AtEpochBoundary {
+		return s.AtEpochBoundary == other.AtEpochBoundary
+	}
+	return s.AtSlot == other.AtSlot && s.AtTime.Equal(other.AtTime)
+}
+
+// String returns a human readable description of the schedule
+func (s Schedule) String() string {
+	switch {
+	case s.AtEpochBoundary && s.AtSlot > 0:
+		return fmt.Sprintf("at slot %d (start of epoch %d)", s.AtSlot, s.Epoch)
+	case s.AtEpochBoundary:
+		return "at the next epoch boundary"
+	case s.AtSlot > 0:
+		return fmt.Sprintf("at slot %d", s.AtSlot)
+	case !s.AtTime.IsZero():
+		return fmt.Sprintf("at %s", s.AtTime.Format(time.RFC3339))
+	default:
+		return "immediately"
+	}
+}
+
+// mergeSchedules reconciles the schedules requested on each node. Either node may set the
+// schedule; if both do they must match, otherwise the failover is refused.
+func mergeSchedules(passive, active Schedule) (Schedule, error) {
+	switch {
+	case passive.IsSet() && active.IsSet() && !passive.Matches(active):
+		return Schedule{}, fmt.Errorf(
+			"schedule mismatch: this node wants the failover %s but the active node wants it %s — set the same schedule on both nodes (or only one) and retry",
+			passive, active,
+		)
+	case passive.IsSet():
+		return passive, nil
+	default:
+		return active, nil
+	}
+}
+
+// resolve turns an epoch boundary schedule into the first slot of the next epoch and ensures
+// the scheduled point has not already passed
+func (s Schedule) resolve(epochInfo *rpc.GetEpochInfoResult, now time.Time) (Schedule, error) {
+	if s.AtEpochBoundary && s.AtSlot == 0 {
+		s.AtSlot = epochInfo.AbsoluteSlot - epochInfo.SlotIndex + epochInfo.SlotsInEpoch
+		s.Epoch = epochInfo.Epoch + 1
+	}
+
+	if s.AtSlot > 0 && s.AtSlot <= epochInfo.AbsoluteSlot {
+		return Schedule{}, fmt.Errorf("scheduled slot %d has already passed (current slot %d)", s.AtSlot, epochInfo.AbsoluteSlot)
+	}
+
+	if !s.AtTime.IsZero() && !s.AtTime.After(now) {
+		return Schedule{}, fmt.Errorf("scheduled time %s has already passed", s.AtTime.Format(time.RFC3339))
+	}
+
+	return s, nil
+}
@@ -0,0 +1,85 @@
+package failover
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestNewSchedule(t *testing.T) {
+	schedule, err := NewSchedule(0, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schedule.IsSet() {
+		t.Errorf("expected empty schedule, got %s", schedule)
+	}
+
+	schedule, err = NewSchedule(0, "2025-06-01T14:00:00Z", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !schedule.AtTime.Equal(time.Date(2025, 6, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time: %s", schedule.AtTime)
+	}
+
+	if _, err := NewSchedule(0, "tomorrow", false); err == nil {
+		t.Error("expected error for non-RFC3339 time")
+	}
+
+	if _, err := NewSchedule(100, "", true); err == nil {
+		t.Error("expected error when more than one schedule option is set")
+	}
+}
+
+func TestMergeSchedules(t *testing.T) {
+	none := Schedule{}
+	atSlot := Schedule{AtSlot: 1000}
+
+	merged, err := mergeSchedules(none, atSlot)
+	if err != nil || merged.AtSlot != 1000 {
+		t.Errorf("expected active node schedule to be adopted, got %s (err=%v)", merged, err)
+	}
+
+	merged, err = mergeSchedules(atSlot, none)
+	if err != nil || merged.AtSlot != 1000 {
+		t.Errorf("expected passive node schedule to be kept, got %s (err=%v)", merged, err)
+	}
+
+	if _, err := mergeSchedules(atSlot, atSlot); err != nil {
+		t.Errorf("expected matching schedules to merge, got %v", err)
+	}
+
+	_, err = mergeSchedules(atSlot, Schedule{AtSlot: 2000})
+	if err == nil || !strings.Contains(err.Error(), "schedule mismatch") {
+		t.Errorf("expected schedule mismatch error, got %v", err)
+	}
+}
+
+func TestScheduleResolve(t *testing.T) {
+	epochInfo := &rpc.GetEpochInfoResult{
+		AbsoluteSlot: 1_000_100,
+		SlotIndex:    100,
+		SlotsInEpoch: 432_000,
+		Epoch:        500,
+	}
+	now := time.Now()
+
+	resolved, err := Schedule{AtEpochBoundary: true}.resolve(epochInfo, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.AtSlot != 1_432_000 || resolved.Epoch != 501 {
+		t.Errorf("expected slot 1432000 epoch 501, got slot %d epoch %d", resolved.AtSlot, resolved.Epoch)
+	}
+
+	if _, err := (Schedule{AtSlot: 1_000_000}).resolve(epochInfo, now); err == nil {
+		t.Error("expected error for a slot in the past")
+	}
+
+	if _, err := (Schedule{AtTime: now.Add(-time.Minute)}).resolve(epochInfo, now); err == nil {
+		t.Error("expected error for a time in the past")
+	}
+}
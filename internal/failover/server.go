@@ -3,6 +3,7 @@ package failover
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/quic-go/quic-go"
 	"github.com/sol-strategies/solana-validator-failover/internal/constants"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+	"github.com/sol-strategies/solana-validator-failover/internal/notifications"
 	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 	"github.com/sol-strategies/solana-validator-failover/internal/utils"
@@ -23,6 +25,7 @@ import (
 // MonitorConfig holds the configuration for a failover monitor
 type MonitorConfig struct {
 	CreditSamples CreditSamplesConfig
+	Votes         VoteMonitorConfig
 }
 
 // CreditSamplesConfig holds the configuration for a failover monitor credit samples
@@ -46,10 +49,28 @@ type ServerConfig struct {
 	SkipTowerSync     bool
 	AutoConfirm       bool
 	Rollback          hooks.RollbackConfig
+	Notifier          *notifications.Dispatcher
+	Schedule          Schedule
+	// AdminRPC, when set, switches this node's identity over the validator's admin rpc socket,
+	// with PassiveNodeInfo.SetIdentityCommand as the fallback
+	AdminRPC AdminRPC
+	// Preflight, when set, runs after the pre hooks and before the active node is told to proceed,
+	// and again once the active node has finished waiting and is ready to switch, given the active
+	// node's info - an error aborts the failover while the active node is still active
+	Preflight func(activeNodeInfo *NodeInfo) error
 	// TLSConfig is an optional mTLS config. When non-nil, the server requires
 	// connecting clients to present a certificate signed by the configured CA.
 	// When nil, an ephemeral self-signed certificate is used (no client auth).
 	TLSConfig *tls.Config
+
+	// Pull is set when this passive node initiates the failover. Instead of listening,
+	// the server dials the active peer at PullPeerAddress, which waits in pull mode.
+	Pull            bool
+	PullPeerName    string
+	PullPeerAddress string
+	// PullTLSConfig is the optional mTLS config used to dial the active peer in pull mode.
+	// When nil, the active peer's certificate is not verified.
+	PullTLSConfig *tls.Config
 }
 
 // Server is the failover server - run by the passive node
@@ -71,30 +92,27 @@ type Server struct {
 	isDryRunFailover  bool
 	activeConn        *quic.Conn
 	hooks             hooks.FailoverHooks
+	phaseHooks        hooks.PhaseQueue // background phase hooks, joined before the failover stream is done
 	monitorConfig     MonitorConfig
 	skipTowerSync     bool
 	autoConfirm       bool
 	rollback          hooks.RollbackConfig
+	adminRPC          AdminRPC
+	preflight         func(activeNodeInfo *NodeInfo) error
+	notifier          *notifications.Dispatcher
+	schedule          Schedule
 	mtlsEnabled       bool
+	pull              bool
+	pullPeerName      string
+	pullPeerAddress   string
+	pullTLSConfig     *tls.Config
 }
 
 // NewServerFromConfig creates a new failover server from a configuration
 func NewServerFromConfig(config ServerConfig) (*Server, error) {
-	var serverTLSConfig *tls.Config
-	mtlsEnabled := config.TLSConfig != nil
-	if mtlsEnabled {
-		cloned := config.TLSConfig.Clone()
-		cloned.NextProtos = []string{ProtocolName}
-		serverTLSConfig = cloned
-	} else {
-		tlsCert, err := utils.GenerateTLSCertificate()
-		if err != nil {
-			return nil, err
-		}
-		serverTLSConfig = &tls.Config{
-			Certificates: []tls.Certificate{tlsCert},
-			NextProtos:   []string{ProtocolName},
-		}
+	serverTLSConfig, err := newListenerTLSConfig(config.TLSConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -102,7 +120,7 @@ func NewServerFromConfig(config ServerConfig) (*Server, error) {
 	s := &Server{
 		port:             config.Port,
 		tlsConfig:        serverTLSConfig,
-		mtlsEnabled:      mtlsEnabled,
+		mtlsEnabled:      config.TLSConfig != nil,
 		logger:           log.Default(),
 		ctx:              ctx,
 		cancel:           cancel,
@@ -115,6 +133,14 @@ func NewServerFromConfig(config ServerConfig) (*Server, error) {
 		skipTowerSync:    config.SkipTowerSync,
 		autoConfirm:      config.AutoConfirm,
 		rollback:         config.Rollback,
+		adminRPC:         config.AdminRPC,
+		preflight:        config.Preflight,
+		notifier:         config.Notifier,
+		schedule:         config.Schedule,
+		pull:             config.Pull,
+		pullPeerName:     config.PullPeerName,
+		pullPeerAddress:  config.PullPeerAddress,
+		pullTLSConfig:    config.PullTLSConfig,
 	}
 
 	if s.port == 0 {
@@ -130,7 +156,6 @@ func NewServerFromConfig(config ServerConfig) (*Server, error) {
 		config.StreamTimeout = DefaultStreamTimeoutDurationStr
 	}
 
-	var err error
 	s.heartbeatInterval, err = time.ParseDuration(config.HeartbeatInterval)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse heartbeat interval: %v", err)
@@ -146,23 +171,15 @@ func NewServerFromConfig(config ServerConfig) (*Server, error) {
 
 // Start starts the failover server
 func (s *Server) Start() error {
-	wrapped, err := newBasicPacketConn(fmt.Sprintf(":%d", s.port))
-	if err != nil {
-		return fmt.Errorf("failed to create UDP socket: %v", err)
+	if s.pull {
+		return s.pullFromActivePeer()
 	}
-	s.transport = &quic.Transport{Conn: wrapped}
 
-	listener, err := s.transport.Listen(
-		s.tlsConfig,
-		&quic.Config{
-			KeepAlivePeriod: s.heartbeatInterval,
-			MaxIdleTimeout:  s.streamTimeout,
-		},
-	)
+	transport, listener, err := listenQUIC(s.port, s.tlsConfig, s.quicConfig())
 	if err != nil {
-		wrapped.Close()
-		return fmt.Errorf("failed to create listener: %v", err)
+		return err
 	}
+	s.transport = transport
 	s.listener = listener
 
 	s.logger.Info(style.RenderPinkString(fmt.Sprintf("listening on port %d - run this program on the ", s.port)) +
@@ -188,23 +205,71 @@ func (s *Server) Start() error {
 	}
 }
 
+// quicConfig returns the QUIC config for failover connections made or accepted by this server
+func (s *Server) quicConfig() *quic.Config {
+	return &quic.Config{
+		KeepAlivePeriod: s.heartbeatInterval,
+		MaxIdleTimeout:  s.streamTimeout,
+	}
+}
+
+// pullFromActivePeer dials the active peer, which must be running in pull mode, and serves
+// the failover over that connection. The active node opens the failover stream as usual, so
+// the protocol from here on is identical to the active node dialing this one.
+func (s *Server) pullFromActivePeer() error {
+	var conn *quic.Conn
+
+	sp := spinner.New().Title(style.RenderPinkString("pull mode: waiting for ") +
+		style.RenderActiveString(s.pullPeerName, false) +
+		style.RenderPinkString(" at ") +
+		style.RenderGreyString(s.pullPeerAddress, false) +
+		style.RenderPinkString("..."))
+	sp.ActionWithErr(func(spinnerCtx context.Context) error {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			transport, c, err := dialQUIC(spinnerCtx, s.pullPeerAddress, newDialerTLSConfig(s.pullTLSConfig), s.quicConfig())
+			if err == nil {
+				s.transport = transport
+				conn = c
+				return nil
+			}
+			if isALPNMismatch(err) {
+				return fmt.Errorf("active node rejected connection: incompatible wire protocol version — " +
+					"ensure both nodes run the same version of solana-validator-failover")
+			}
+			s.logger.Debug("active peer not listening yet, retrying...", "err", err, "address", s.pullPeerAddress)
+
+			select {
+			case <-spinnerCtx.Done():
+				return spinnerCtx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+	if err := sp.Run(); err != nil {
+		return fmt.Errorf("failed to connect to active peer %s: %w", s.pullPeerName, err)
+	}
+
+	if s.pullTLSConfig != nil {
+		logPeerCertificate(s.logger, conn, "server")
+	}
+
+	s.logger.Debugf("connected to %s", style.RenderActiveString(s.pullPeerName, false))
+	s.handleConnection(conn)
+
+	return nil
+}
+
 // handleConnection handles a new failover connection
 func (s *Server) handleConnection(conn *quic.Conn) {
 	defer conn.CloseWithError(0, "connection closed")
 
 	s.logger.Debug("accepted new connection", "remote_addr", conn.RemoteAddr().String())
 
-	if s.mtlsEnabled {
-		tlsState := conn.ConnectionState().TLS
-		if len(tlsState.PeerCertificates) > 0 {
-			peer := tlsState.PeerCertificates[0]
-			s.logger.Info("mTLS: client certificate verified",
-				"remote_addr", conn.RemoteAddr().String(),
-				"subject", peer.Subject.String(),
-				"issuer", peer.Issuer.String(),
-				"expires", peer.NotAfter,
-			)
-		}
+	if s.mtlsEnabled && !s.pull {
+		logPeerCertificate(s.logger, conn, "client")
 	}
 
 	s.activeConn = conn
@@ -255,6 +320,10 @@ func (s *Server) handleStream(stream *quic.Stream) {
 }
 
 func (s *Server) handleFailoverStream(stream *quic.Stream) {
+	// give any notifications sent during the failover a chance to go out before returning
+	defer s.notifier.Flush()
+	defer s.phaseHooks.Wait()
+
 	// read the message and parse it into a Stream struct
 	s.failoverStream = NewFailoverStream(stream)
 	if s.failoverStream.Decode() != nil {
@@ -274,6 +343,9 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 	// set the skip tower sync flag
 	s.failoverStream.SetSkipTowerSync(s.skipTowerSync)
 
+	// set the verify handover flag - dry runs don't change identity so there is nothing to verify
+	s.failoverStream.SetVerifyHandover(s.rollback.Enabled && s.rollback.VerifyHandover.Enabled && !s.isDryRunFailover)
+
 	// set this node's info so subsequent responses can be sent to the client with it
 	s.failoverStream.SetPassiveNodeInfo(s.passiveNodeInfo)
 
@@ -295,6 +367,8 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		return
 	}
 
+	s.runPhaseHooks(hooks.PhaseOnConnect, false, nil)
+
 	// query gossip for client by its public IP
 	s.logger.Debugf("querying gossip for active node IP %s", s.failoverStream.GetActiveNodeInfo().PublicIP)
 	gossipActiveNode, err := s.solanaRPCClient.NodeFromIP(s.failoverStream.GetActiveNodeInfo().PublicIP)
@@ -344,10 +418,31 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		return
 	}
 
+	// agree on when the failover executes - either node may schedule it, but not differently
+	schedule, err := mergeSchedules(s.schedule, s.failoverStream.GetSchedule())
+	if err == nil && schedule.IsSet() {
+		epochInfo, epochErr := s.solanaRPCClient.GetEpochInfo()
+		if epochErr != nil {
+			err = fmt.Errorf("failed to resolve failover schedule: %w", epochErr)
+		} else {
+			schedule, err = schedule.resolve(epochInfo, time.Now())
+		}
+	}
+	if err != nil {
+		s.failoverStream.SetErrorMessage(err.Error())
+		if encodeErr := s.failoverStream.Encode(); encodeErr != nil {
+			s.logger.Error("failed to send error message to client", "err", encodeErr)
+		}
+		s.logger.Fatal(err.Error())
+		return
+	}
+	s.failoverStream.SetSchedule(schedule)
+
 	s.logger.Infof("%s connected from %s - failover plan:", s.failoverStream.GetActiveNodeInfo().Hostname, s.activeConn.RemoteAddr())
 
 	if err := s.failoverStream.ConfirmFailover(s.hooks, s.rollback, activeRPCURL, passiveRPCURL, s.autoConfirm); err != nil {
 		s.logger.Error("failover cancelled", "err", err)
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, fmt.Errorf("failover cancelled: %w", err))
 
 		// Send error message to client before exiting
 		s.failoverStream.SetErrorMessagef("server cancelled failover: %v", err)
@@ -370,6 +465,8 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		os.Exit(1)
 	}
 
+	s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventPlanned, ""))
+
 	// take initial sample of vote credits and rank for the active key - use it to compare later
 	s.logger.Debug("pulling pre-failover vote credits sample...")
 	err = s.failoverStream.PullActiveIdentityVoteCreditsSample(s.solanaRPCClient)
@@ -418,11 +515,13 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 	}
 
 	// run pre hooks when passive
-	err = s.hooks.RunPreWhenPassive(s.getHookEnvMap(hookEnvMapParams{
+	err = s.hooks.RunPreWhenPassive(s.failoverStream.HookContext(s.ctx), s.getHookEnvMap(hookEnvMapParams{
 		isDryRunFailover: s.isDryRunFailover,
 		isPreFailover:    true,
+		phase:            hooks.PhasePre,
 	}))
 	if err != nil {
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, fmt.Errorf("failed to run pre hooks when passive: %w", err))
 		s.failoverStream.SetErrorMessagef("server failed to run its pre-failover hooks: %v", err)
 		if encodeErr := s.failoverStream.Encode(); encodeErr != nil {
 			s.logger.Error("failed to send error message to client", "err", encodeErr)
@@ -431,23 +530,61 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		return
 	}
 
+	// last checks before the active node gives up its identity
+	s.runPreflight()
+
 	// set can proceed to true
 	s.failoverStream.SetCanProceed(true)
 	if s.failoverStream.Encode() != nil {
 		return
 	}
 
+	if schedule.IsSet() {
+		s.logger.Infof("failover scheduled %s - waiting for %s", schedule, s.failoverStream.GetActiveNodeInfo().Hostname)
+	}
+
+	// Wait for the active node to finish waiting for the schedule, its leader checks and pre hooks,
+	// which can take hours, then run the preflight again against its current slot before it goes
+	// passive - the checks above may be long stale by now.
+	if err := s.failoverStream.Decode(); err != nil {
+		s.logger.Error("failed to decode ready to switch message", "err", err)
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, fmt.Errorf("failed to decode ready to switch message: %w", err))
+		return
+	}
+	if abortReason := s.failoverStream.GetErrorMessage(); abortReason != "" {
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, errors.New(abortReason))
+		s.logger.Fatal("active node aborted the failover - this node remains passive", "reason", abortReason)
+		return
+	}
+	s.logger.Debug("active node is ready to switch - re-running preflight", "active_slot", s.failoverStream.GetActiveNodeInfo().Slot)
+	s.runPreflight()
+	if s.failoverStream.Encode() != nil {
+		return
+	}
+
 	if s.skipTowerSync {
-		s.logger.Info("failover started - skipping tower file sync")
+		s.logger.Infof("failover started - skipping tower file sync, waiting for %s to go passive", s.failoverStream.GetActiveNodeInfo().Hostname)
 	} else {
+		s.runPhaseHooks(hooks.PhaseBeforeTowerSync, false, nil)
 		s.logger.Infof("failover started - waiting for tower file from %s", s.failoverStream.GetActiveNodeInfo().Hostname)
+	}
 
-		// Wait for the updated node info with tower file bytes
-		if err := s.failoverStream.Decode(); err != nil {
-			s.logger.Error("failed to decode updated node info", "err", err)
-			return
-		}
+	// Wait for the active node to report it has gone passive (with the tower file bytes unless
+	// skipping tower sync). This node must never take the active identity before that.
+	if err := s.failoverStream.Decode(); err != nil {
+		s.logger.Error("failed to decode updated node info", "err", err)
+		s.runPhaseHooks(hooks.PhaseOnFailure, false, fmt.Errorf("failed to decode updated node info: %w", err))
+		return
+	}
 
+	// the active node bailed out before changing its identity - stay passive
+	if abortReason := s.failoverStream.GetErrorMessage(); abortReason != "" {
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, errors.New(abortReason))
+		s.logger.Fatal("active node aborted the failover - this node remains passive", "reason", abortReason)
+		return
+	}
+
+	if !s.skipTowerSync {
 		// check that the TowerFileBytes sent are the same as the hash of the tower file
 		computedTowerFileHash := s.failoverStream.GetActiveNodeInfo().ComputeTowerFileHashFromBytes(s.failoverStream.GetActiveNodeInfo().TowerFileBytes)
 		expectedTowerFileHash := s.failoverStream.GetActiveNodeInfo().TowerFileHash
@@ -456,6 +593,7 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 
 		if computedTowerFileHash != expectedTowerFileHash {
 			s.logger.Errorf("tower file hash mismatch: (got: %s) != (expected: %s)", computedTowerFileHash, expectedTowerFileHash)
+			s.runPhaseHooks(hooks.PhaseOnFailure, false, fmt.Errorf("tower file hash mismatch: (got: %s) != (expected: %s)", computedTowerFileHash, expectedTowerFileHash))
 			s.logger.Error("aborting failover - save it by running:")
 			fmt.Printf(
 				"  rsync -avz --no-perms --no-i-r --no-progress --no-motd --no-times -e ssh -i <YOUR-SSH-KEY> -o PubkeyAcceptedKeyTypes=+ssh-ed25519 -o HostKeyAlgorithms=+ssh-ed25519 -o BatchMode=yes -o StrictHostKeyChecking=no %s@%s:%s %s \n",
@@ -473,17 +611,20 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		// Write bytes and close immediately
 		if _, err := towerFile.Write(s.failoverStream.GetActiveNodeInfo().TowerFileBytes); err != nil {
 			s.logger.Error(fmt.Sprintf("failed to write tower file to %s", s.failoverStream.GetPassiveNodeInfo().TowerFile), "err", err)
+			s.runPhaseHooks(hooks.PhaseOnFailure, false, fmt.Errorf("failed to write tower file: %w", err))
 			return
 		}
 
 		// close the file handle - defer utils.SafeCloseFile() above won't conflict
 		if err := towerFile.Close(); err != nil {
 			s.logger.Error(fmt.Sprintf("failed to close tower file %s", s.failoverStream.GetPassiveNodeInfo().TowerFile), "err", err)
+			s.runPhaseHooks(hooks.PhaseOnFailure, false, fmt.Errorf("failed to close tower file: %w", err))
 			return
 		}
 
 		s.failoverStream.SetPassiveNodeSyncTowerFileEndTime()
 		s.logger.Info("received tower file")
+		s.runPhaseHooks(hooks.PhaseAfterTowerSync, false, nil)
 	}
 
 	// set identity to active
@@ -496,39 +637,56 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		style.RenderActiveString(constants.NodeRoleActive, false) +
 		style.RenderPinkString(" identity"))
 
+	s.runPhaseHooks(hooks.PhaseBeforeSetIdentity, false, nil)
+
 	s.failoverStream.SetPassiveNodeSetIdentityStartTime()
 
-	err = utils.RunCommand(utils.RunCommandParams{
-		CommandSlice: strings.Split(s.failoverStream.GetPassiveNodeInfo().SetIdentityCommand, " "),
-		DryRun:       s.isDryRunFailover,
-		LogDebug:     s.logger.GetLevel() <= log.DebugLevel,
+	err = setIdentity(s.ctx, setIdentityParams{
+		adminRPC:     s.adminRPC,
+		identity:     s.failoverStream.GetPassiveNodeInfo().Identities.Active,
+		requireTower: !s.skipTowerSync,
+		command:      s.failoverStream.GetPassiveNodeInfo().SetIdentityCmd,
+		dryRun:       s.isDryRunFailover,
+		logger:       s.logger,
 	})
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("failed to set identity to active with command: %s", s.failoverStream.GetPassiveNodeInfo().SetIdentityCommand), "err", err)
+		s.runPhaseHooks(hooks.PhaseOnFailure, false, fmt.Errorf("failed to set identity to active: %w", err))
 		if s.rollback.Enabled {
 			// Both sides have rollback enabled (mismatch is caught earlier).
 			s.logger.Warn("rollback enabled: signalling active node to revert, then re-asserting passive identity")
+			s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventRollback,
+				fmt.Sprintf("%s failed to set identity to active (%v) - reverting both nodes", s.failoverStream.GetPassiveNodeInfo().Hostname, err)))
 			s.failoverStream.SetRollbackRequired(true)
 			// best-effort — client may already be gone; ignore encode error
 			_ = s.failoverStream.Encode()
-			if rbErr := RunRollbackToPassive(s.rollback, s.getHookEnvMap(hookEnvMapParams{
+			if rbErr := RunRollbackToPassive(s.failoverStream.HookContext(s.ctx), s.rollback, s.getHookEnvMap(hookEnvMapParams{
 				isDryRunFailover: s.isDryRunFailover,
 				isPostFailover:   true,
+				err:              fmt.Errorf("failed to set identity to active: %w", err),
 			}), s.isDryRunFailover, s.logger); rbErr != nil {
 				s.logger.Error("rollback to passive failed — manual intervention required", "err", rbErr)
 			}
 		} else {
 			s.logger.Error("rollback disabled — this node is still passive; the peer has also switched to passive")
-			if s.rollback.ToPassive.ResolvedCmd != "" {
+			if !s.rollback.ToPassive.ResolvedCmd.IsZero() {
 				s.logger.Errorf("to recover this node: %s", s.rollback.ToPassive.ResolvedCmd)
 			}
 		}
+		s.notifier.Flush()
 		s.logger.Fatal("set identity to active failed — failover aborted", "err", err)
 		return
 	}
-
 	s.failoverStream.SetPassiveNodeSetIdentityEndTime()
 
+	// the command exiting zero isn't proof - and without it this node may or may not be active
+	if err = s.confirmActiveIdentity(); err != nil {
+		s.rollbackUnconfirmedActiveIdentity(err)
+		return
+	}
+
+	s.runPhaseHooks(hooks.PhaseAfterSetIdentity, true, nil)
+
 	// get the current slot and record it - sometimes rpc will be a slot behind, if so, assume same-slot
 	failoverEndSlot, err := s.solanaRPCClient.GetCurrentSlot()
 	if err != nil {
@@ -540,16 +698,27 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		s.failoverStream.SetFailoverEndSlot(failoverEndSlot)
 	}
 
+	// in verified handover mode the active node waits, passive, until this node's votes are seen
+	// landing - if they aren't, both nodes roll back
+	if s.failoverStream.GetVerifyHandover() {
+		if err := s.verifyHandover(); err != nil {
+			s.rollbackUnverifiedHandover(err)
+			return
+		}
+	}
+
 	// set is successfully completed to true
 	s.failoverStream.SetIsSuccessfullyCompleted(true)
 	if s.failoverStream.Encode() != nil {
 		return
 	}
 
-	// run post hooks when active
-	s.hooks.RunPostWhenActive(s.getHookEnvMap(hookEnvMapParams{
+	// run post hooks when active, once the phase hooks still running in the background are done
+	s.phaseHooks.Wait()
+	s.hooks.RunPostWhenActive(s.failoverStream.HookContext(s.ctx), s.getHookEnvMap(hookEnvMapParams{
 		isDryRunFailover: s.isDryRunFailover,
 		isPostFailover:   true,
+		phase:            hooks.PhasePost,
 	}))
 
 	if !s.isDryRunFailover {
@@ -557,6 +726,7 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 	}
 
 	// build and render the post-failover summary immediately (before credit monitoring)
+	s.failoverStream.SetPassiveNodeHookOutputs(s.hooks.Outputs.Snapshot())
 	summaryData := s.failoverStream.BuildSummaryData()
 	rendered, renderErr := RenderFailoverSummary(summaryData)
 	if renderErr != nil {
@@ -565,6 +735,7 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		s.logger.Info("post-failover state:")
 		fmt.Println(style.RenderMessageString(strings.TrimLeft(rendered, "\n")))
 	}
+	s.notifier.Notify(s.failoverStream.CompletedNotificationEvent(summaryData, rendered))
 
 	// monitor the credits by pulling configured samples
 	s.logger.Info("monitoring vote credits post-failover...")
@@ -587,6 +758,19 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 		s.logger.Infof("vote credits: rank %s (%d → %d)", rankMsg, firstRank, lastRank)
 	}
 
+	// watch votes land under the new identity - the failover has completed on both sides so a
+	// failure here can't be undone, only alerted on
+	var votesErr error
+	if s.monitorConfig.Votes.Enabled && !s.isDryRunFailover {
+		votesErr = s.monitorVotesPostFailover()
+		if votesErr != nil {
+			s.logger.Error("votes are not landing after failover - investigate immediately", "err", votesErr)
+			s.runPhaseHooks(hooks.PhaseOnFailure, true, fmt.Errorf("votes are not landing after failover: %w", votesErr))
+			s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventVotesStalled,
+				fmt.Sprintf("%s switched to active but votes are not landing - investigate immediately: %v", s.failoverStream.GetPassiveNodeInfo().Hostname, votesErr)))
+		}
+	}
+
 	// close the stream and connection cleanly
 	if err := stream.Close(); err != nil {
 		s.logger.Error("failed to close stream", "err", err)
@@ -606,7 +790,28 @@ func (s *Server) handleFailoverStream(stream *quic.Stream) {
 			s.logger.Error("failed to close transport", "err", err)
 		}
 	}
+	s.phaseHooks.Wait()
 	s.cancel()
+
+	if votesErr != nil {
+		s.notifier.Flush()
+		s.logger.Fatal("post-failover vote monitoring failed", "err", votesErr)
+	}
+}
+
+// confirmActiveIdentity waits for the local validator to report the active identity and records
+// when it did. Dry runs don't change identity so there is nothing to confirm.
+func (s *Server) confirmActiveIdentity() error {
+	if s.isDryRunFailover {
+		return nil
+	}
+	confirmedAt, err := waitForLocalIdentity(s.ctx, s.solanaRPCClient, s.failoverStream.GetPassiveNodeInfo().Identities.Active.PubKey(), identityConfirmTimeout, identityConfirmInterval)
+	if err != nil {
+		return fmt.Errorf("failed to confirm active identity: %w", err)
+	}
+	s.failoverStream.SetPassiveNodeIdentityConfirmedTime(confirmedAt)
+	s.logger.Debug("local validator reports active identity")
+	return nil
 }
 
 // confirmGossipNodesPostFailover confirms that the gossip nodes have switched roles post-failover
@@ -738,7 +943,210 @@ func (s *Server) confirmGossipNodesPostFailover() {
 		s.logger.Info("gossip confirms nodes switched roles successfully")
 	} else {
 		s.logger.Error("gossip does not confirm role switch")
+		detail := "gossip does not show the nodes switched roles - investigate immediately"
+		if err != nil {
+			detail += ": " + err.Error()
+		}
+		s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventGossipConfirmationFailed, detail))
+	}
+}
+
+// verifyHandover waits for this node's first vote under the active identity to land
+func (s *Server) verifyHandover() (err error) {
+	cfg := s.rollback.VerifyHandover
+	identity := s.failoverStream.GetPassiveNodeInfo().Identities.Active.PubKey()
+	startSlot := max(s.failoverStream.GetFailoverEndSlot(), s.failoverStream.GetFailoverStartSlot())
+	var progress *solana.VoteProgress
+
+	sp := spinner.New().TitleStyle(style.SpinnerTitleStyle).Title(style.RenderPinkString(fmt.Sprintf("verifying handover: waiting for a vote after slot %d...", startSlot)))
+	sp.ActionWithErr(func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cfg.TimeoutDuration)
+		defer cancel()
+		progress, err = waitForVotes(ctx, s.solanaRPCClient, identity, startSlot, uint64(cfg.WithinSlots), voteMonitorInterval, func(p *solana.VoteProgress) {
+			sp.Title(style.RenderPinkString(fmt.Sprintf("verifying handover: waiting for a vote after slot %d (last vote %d, cluster slot %d)...", startSlot, p.LastVote, p.Slot)))
+		})
+		return err
+	})
+	if runErr := sp.Run(); runErr != nil && err == nil {
+		err = runErr
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("handover verified - votes landing", "last_vote", progress.LastVote, "cluster_slot", progress.Slot)
+	return nil
+}
+
+// rollbackUnverifiedHandover returns this node to passive after its votes didn't land, then tells
+// the active node to revert - with this node's tower file unless skipping tower sync. The active
+// node is only told to revert once this node has confirmed its passive identity, so both nodes are
+// never active.
+func (s *Server) rollbackUnverifiedHandover(verifyErr error) {
+	err := fmt.Errorf("handover not verified: %w", verifyErr)
+	s.logger.Error("votes not landing after switching to active - rolling back both nodes", "err", verifyErr)
+	s.runPhaseHooks(hooks.PhaseOnFailure, true, err)
+	s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventRollback,
+		fmt.Sprintf("%s's votes did not land after it went active (%v) - reverting both nodes", s.failoverStream.GetPassiveNodeInfo().Hostname, verifyErr)))
+
+	if rbErr := s.revertBothNodes(err); rbErr != nil {
+		s.notifier.Flush()
+		s.logger.Fatal("handover not verified and rollback failed", "err", err)
+		return
+	}
+	s.notifier.Flush()
+	s.logger.Fatal("handover not verified - this node rolled back to passive", "err", verifyErr)
+}
+
+// rollbackUnconfirmedActiveIdentity handles a set-identity command that succeeded without the local
+// validator reporting the active identity. This node may be active and voting, so it is handled
+// like an unverified handover: this node goes back to passive before the active node may revert.
+func (s *Server) rollbackUnconfirmedActiveIdentity(confirmErr error) {
+	err := fmt.Errorf("failed to set identity to active: %w", confirmErr)
+	s.logger.Error("set-identity command succeeded but the active identity was not confirmed - this node may be active", "err", confirmErr)
+	s.runPhaseHooks(hooks.PhaseOnFailure, true, err)
+
+	if !s.rollback.Enabled {
+		s.logger.Error("rollback disabled — this node may be active and the peer has switched to passive; check gossip and intervene manually")
+		if !s.rollback.ToPassive.ResolvedCmd.IsZero() {
+			s.logger.Errorf("if this node needs to revert to passive: %s", s.rollback.ToPassive.ResolvedCmd)
+		}
+		s.notifier.Flush()
+		s.logger.Fatal("set identity to active not confirmed — failover aborted", "err", confirmErr)
+		return
+	}
+
+	s.logger.Warn("rollback enabled: reverting this node to passive before signalling the active node to revert")
+	s.notifier.Notify(s.failoverStream.NotificationEvent(notifications.EventRollback,
+		fmt.Sprintf("%s did not confirm the active identity (%v) - reverting both nodes", s.failoverStream.GetPassiveNodeInfo().Hostname, confirmErr)))
+	if rbErr := s.revertBothNodes(err); rbErr != nil {
+		s.notifier.Flush()
+		s.logger.Fatal("set identity to active not confirmed and rollback failed", "err", rbErr)
+		return
+	}
+	s.notifier.Flush()
+	s.logger.Fatal("set identity to active not confirmed - this node rolled back to passive", "err", confirmErr)
+}
+
+// revertBothNodes rolls this node back to passive after it may have gone active and voted. Only
+// once its passive identity is confirmed, and its tower file read to send back, is the active node
+// told to revert to active with it - if any step fails the active node is told to stay passive
+// instead and the step's error is returned.
+func (s *Server) revertBothNodes(err error) error {
+	stayPassive := func(reason string, reasonErr error) error {
+		s.logger.Error(reason+" — manual intervention required", "err", reasonErr)
+		if !s.rollback.ToPassive.ResolvedCmd.IsZero() {
+			s.logger.Errorf("to recover this node: %s", s.rollback.ToPassive.ResolvedCmd)
+		}
+		s.failoverStream.SetErrorMessagef("%v - and %s: %v - %s was not reverted to active", err, reason, reasonErr, s.failoverStream.GetActiveNodeInfo().Hostname)
+		if encodeErr := s.failoverStream.Encode(); encodeErr != nil {
+			s.logger.Error("failed to send error message to client", "err", encodeErr)
+		}
+		return fmt.Errorf("%s: %w", reason, reasonErr)
 	}
+
+	if rbErr := RunRollbackToPassive(s.failoverStream.HookContext(s.ctx), s.rollback, s.getHookEnvMap(hookEnvMapParams{
+		isDryRunFailover: s.isDryRunFailover,
+		isPostFailover:   true,
+		err:              err,
+	}), s.isDryRunFailover, s.logger); rbErr != nil {
+		return stayPassive("rollback to passive failed", rbErr)
+	}
+	if _, confirmErr := waitForLocalIdentity(s.ctx, s.solanaRPCClient, s.failoverStream.GetPassiveNodeInfo().Identities.Passive.PubKey(), identityConfirmTimeout, identityConfirmInterval); confirmErr != nil {
+		return stayPassive("failed to confirm passive identity after rollback", confirmErr)
+	}
+
+	// the active node's tower is from before the failover - send this node's back in case it voted
+	if !s.skipTowerSync {
+		if towerErr := s.failoverStream.GetPassiveNodeInfo().SetTowerFileBytes(); towerErr != nil {
+			return stayPassive("failed to read tower file to send back", towerErr)
+		}
+	}
+
+	s.failoverStream.SetErrorMessage(err.Error())
+	s.failoverStream.SetHandoverUnverified(true)
+	s.failoverStream.SetRollbackRequired(true)
+	if encodeErr := s.failoverStream.Encode(); encodeErr != nil {
+		s.logger.Error(fmt.Sprintf("failed to signal %s to revert to active — it remains passive; manual intervention required", s.failoverStream.GetActiveNodeInfo().Hostname), "err", encodeErr)
+	}
+	return nil
+}
+
+// monitorVotesPostFailover watches the active identity's vote account for the configured duration,
+// returning an error as soon as votes stop landing
+func (s *Server) monitorVotesPostFailover() (err error) {
+	cfg := s.monitorConfig.Votes
+	identity := s.failoverStream.GetPassiveNodeInfo().Identities.Active.PubKey()
+	startSlot := max(s.failoverStream.GetFailoverEndSlot(), s.failoverStream.GetFailoverStartSlot())
+	var result VoteMonitorResult
+
+	s.logger.Info("monitoring votes post-failover...", "start_slot", startSlot, "within_slots", cfg.WithinSlots, "duration", cfg.Duration)
+	sp := spinner.New().TitleStyle(style.SpinnerTitleStyle).Title(style.RenderPinkString("monitoring votes..."))
+	sp.ActionWithErr(func(ctx context.Context) error {
+		result, err = monitorVotes(ctx, s.solanaRPCClient, identity, startSlot, cfg, voteMonitorInterval, func(r VoteMonitorResult) {
+			if r.FirstVoteSlot == 0 {
+				sp.Title(style.RenderPinkString(fmt.Sprintf("monitoring votes: waiting for a vote after slot %d (last vote %d)...", startSlot, r.LastVote)))
+				return
+			}
+			sp.Title(style.RenderPinkString(fmt.Sprintf("monitoring votes: last vote %d, root %d, +%d credits (cluster median +%d)...",
+				r.LastVote, r.RootSlot, r.CreditsEarned, r.ClusterMedianCreditsEarned)))
+		})
+		return err
+	})
+	if runErr := sp.Run(); runErr != nil && err == nil {
+		err = runErr
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("votes landing post-failover",
+		"first_vote_at_slot", result.FirstVoteSlot,
+		"last_vote", result.LastVote,
+		"root_slot", result.RootSlot,
+		"credits_earned", result.CreditsEarned,
+		"cluster_median_credits_earned", result.ClusterMedianCreditsEarned,
+	)
+	if result.CreditsBelowMedian() {
+		s.logger.Warn("vote credits earned post-failover are less than half the cluster median - check vote latency",
+			"credits_earned", result.CreditsEarned,
+			"cluster_median_credits_earned", result.ClusterMedianCreditsEarned,
+		)
+	}
+	return nil
+}
+
+// runPreflight runs the last checks before the active node gives up its identity, if any. On failure
+// it tells the active node, runs on_abort hooks and exits - both nodes keep their identities.
+func (s *Server) runPreflight() {
+	if s.preflight == nil {
+		return
+	}
+	if err := s.preflight(s.failoverStream.GetActiveNodeInfo()); err != nil {
+		s.runPhaseHooks(hooks.PhaseOnAbort, false, fmt.Errorf("preflight failed: %w", err))
+		s.failoverStream.SetErrorMessagef("server preflight failed: %v", err)
+		if encodeErr := s.failoverStream.Encode(); encodeErr != nil {
+			s.logger.Error("failed to send error message to client", "err", encodeErr)
+		}
+		s.notifier.Flush()
+		s.logger.Fatal("preflight failed - failover aborted", "err", err)
+	}
+}
+
+// runPhaseHooks runs the hooks for a failover phase on this node, which is passive until it has
+// switched to its active identity
+func (s *Server) runPhaseHooks(phase string, wentActive bool, err error) {
+	role := constants.NodeRolePassive
+	if wentActive {
+		role = constants.NodeRoleActive
+	}
+	s.hooks.RunPhase(s.failoverStream.HookContext(s.ctx), phase, role, s.getHookEnvMap(hookEnvMapParams{
+		isDryRunFailover: s.isDryRunFailover,
+		isPreFailover:    !wentActive,
+		isPostFailover:   wentActive,
+		phase:            phase,
+		err:              err,
+	}), &s.phaseHooks)
 }
 
 // getEnvMap returns a map of environment variables to pass to the hooks
@@ -746,6 +1154,11 @@ func (s *Server) getHookEnvMap(params hookEnvMapParams) (envMap map[string]strin
 	envMap = map[string]string{}
 
 	envMap["IS_DRY_RUN_FAILOVER"] = fmt.Sprintf("%t", params.isDryRunFailover)
+	envMap["PHASE"] = params.phase
+	if params.err != nil {
+		envMap["ERROR"] = params.err.Error()
+	}
+	envMap["CLUSTER"] = s.passiveNodeInfo.Cluster
 
 	// this node is passive
 	if params.isPreFailover {
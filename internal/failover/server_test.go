@@ -0,0 +1,117 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
+)
+
+// errUnconfirmedActiveIdentity is the error behind a set-identity command that exited zero without
+// the local validator reporting the active identity
+var errUnconfirmedActiveIdentity = errors.New("failed to set identity to active: failed to confirm active identity: local validator still reports the passive identity")
+
+// testIdentities returns identities with throwaway active and passive pubkeys
+func testIdentities(t *testing.T) *identities.Identities {
+	active, err := identities.NewIdentityFromPubkey(solanago.NewWallet().PublicKey().String())
+	if err != nil {
+		t.Fatalf("failed to create identity: %v", err)
+	}
+	passive, err := identities.NewIdentityFromPubkey(solanago.NewWallet().PublicKey().String())
+	if err != nil {
+		t.Fatalf("failed to create identity: %v", err)
+	}
+	return &identities.Identities{Active: active, Passive: passive}
+}
+
+// unconfirmedActiveTestServer builds a server whose set-identity command succeeded without the
+// local validator confirming the active identity, with a tower file to send back and a rollback
+// to passive command. It returns the server and the active node's end of the stream.
+func unconfirmedActiveTestServer(t *testing.T, rollbackCmd []string) (*Server, *Stream) {
+	dir := t.TempDir()
+	towerFile := filepath.Join(dir, "tower.bin")
+	if err := os.WriteFile(towerFile, []byte("passive node tower"), 0o600); err != nil {
+		t.Fatalf("failed to write tower file: %v", err)
+	}
+
+	passiveNodeInfo := &NodeInfo{Hostname: "passive", Identities: testIdentities(t), TowerFile: towerFile}
+	client, server := newTestStreamPair(t)
+	server.SetPassiveNodeInfo(passiveNodeInfo)
+	server.SetActiveNodeInfo(&NodeInfo{Hostname: "active", Identities: testIdentities(t)})
+
+	// the validator only reports the passive identity - the switch was never confirmed
+	mock := solana.NewMockClient().WithGetLocalNodeIdentity(func() (string, error) {
+		return passiveNodeInfo.Identities.Passive.PubKey(), nil
+	})
+
+	s := &Server{
+		ctx:             context.Background(),
+		logger:          log.Default(),
+		solanaRPCClient: mock,
+		failoverStream:  server,
+		passiveNodeInfo: passiveNodeInfo,
+		rollback: hooks.RollbackConfig{
+			Enabled:   true,
+			ToPassive: hooks.RollbackDirectionConfig{ResolvedCmd: utils.Command{Argv: rollbackCmd}},
+		},
+	}
+	return s, client
+}
+
+// TestRevertBothNodes_UnconfirmedActiveIdentity checks that when set-identity succeeded but the
+// active identity was never confirmed, this node rolls back and confirms its passive identity
+// before telling the active node to revert with this node's tower file
+func TestRevertBothNodes_UnconfirmedActiveIdentity(t *testing.T) {
+	rolledBack := filepath.Join(t.TempDir(), "rolled-back")
+	s, client := unconfirmedActiveTestServer(t, []string{"touch", rolledBack})
+
+	if err := s.revertBothNodes(errUnconfirmedActiveIdentity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Decode(); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if _, err := os.Stat(rolledBack); err != nil {
+		t.Errorf("expected the rollback to passive command to have run: %v", err)
+	}
+	if !client.GetRollbackRequired() || !client.GetHandoverUnverified() {
+		t.Errorf("expected the active node to be told to revert with this node's tower file")
+	}
+	if got := string(client.GetPassiveNodeInfo().TowerFileBytes); got != "passive node tower" {
+		t.Errorf("expected this node's tower file sent back, got %q", got)
+	}
+	if !strings.Contains(client.GetErrorMessage(), "failed to confirm active identity") {
+		t.Errorf("unexpected error message: %s", client.GetErrorMessage())
+	}
+}
+
+// TestRevertBothNodes_RollbackFailsActiveStaysPassive checks that the active node is told to stay
+// passive, not revert, when this node can't roll back to passive
+func TestRevertBothNodes_RollbackFailsActiveStaysPassive(t *testing.T) {
+	s, client := unconfirmedActiveTestServer(t, []string{"false"})
+
+	err := s.revertBothNodes(errUnconfirmedActiveIdentity)
+	if err == nil || !strings.Contains(err.Error(), "rollback to passive failed") {
+		t.Fatalf("expected rollback to passive failure, got: %v", err)
+	}
+	if err := client.Decode(); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if client.GetRollbackRequired() {
+		t.Errorf("expected the active node not to be told to revert")
+	}
+	if !strings.Contains(client.GetErrorMessage(), "was not reverted to active") {
+		t.Errorf("unexpected error message: %s", client.GetErrorMessage())
+	}
+}
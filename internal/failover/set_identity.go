@@ -0,0 +1,49 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/sol-strategies/solana-validator-failover/internal/agave"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
+)
+
+// AdminRPC switches the local validator's identity over its admin rpc socket instead of running
+// the set-identity command
+type AdminRPC interface {
+	SetIdentityFromBytes(ctx context.Context, keypair []byte, requireTower bool) error
+}
+
+// setIdentityParams are the parameters for setIdentity
+type setIdentityParams struct {
+	adminRPC     AdminRPC // nil runs command
+	identity     *identities.Identity
+	requireTower bool
+	command      utils.Command
+	dryRun       bool
+	logger       *log.Logger
+}
+
+// setIdentity switches the local validator to the given identity - over the admin rpc socket when
+// configured, otherwise (or if the socket can't be reached) by running the set-identity command
+func setIdentity(ctx context.Context, params setIdentityParams) error {
+	if params.adminRPC != nil {
+		if params.dryRun {
+			params.logger.Debugf("dry run: admin rpc setIdentityFromBytes %s require_tower=%t", params.identity.PubKey(), params.requireTower)
+			return nil
+		}
+		err := params.adminRPC.SetIdentityFromBytes(ctx, params.identity.Key, params.requireTower)
+		if !errors.Is(err, agave.ErrAdminRPCUnavailable) {
+			if err != nil {
+				return fmt.Errorf("admin rpc set identity failed: %w", err)
+			}
+			return nil
+		}
+		params.logger.Warn("admin rpc unavailable - falling back to set-identity command", "err", err)
+	}
+
+	return params.command.Run(params.dryRun, params.logger.GetLevel() <= log.DebugLevel)
+}
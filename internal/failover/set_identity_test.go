@@ -0,0 +1,119 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/sol-strategies/solana-validator-failover/internal/agave"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
+)
+
+// fakeAdminRPC records setIdentityFromBytes calls and returns err
+type fakeAdminRPC struct {
+	calls        int
+	keypair      []byte
+	requireTower bool
+	err          error
+}
+
+func (f *fakeAdminRPC) SetIdentityFromBytes(_ context.Context, keypair []byte, requireTower bool) error {
+	f.calls++
+	f.keypair = keypair
+	f.requireTower = requireTower
+	return f.err
+}
+
+func testIdentity(t *testing.T) *identities.Identity {
+	t.Helper()
+	return &identities.Identity{Key: solanago.NewWallet().PrivateKey}
+}
+
+// touchCommand returns a command that creates a file, and the path of that file
+func touchCommand(t *testing.T) (utils.Command, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ran")
+	return utils.Command{Argv: []string{"touch", path}}, path
+}
+
+func TestSetIdentity_AdminRPC(t *testing.T) {
+	adminRPC := &fakeAdminRPC{}
+	identity := testIdentity(t)
+	command, ranPath := touchCommand(t)
+
+	err := setIdentity(context.Background(), setIdentityParams{
+		adminRPC:     adminRPC,
+		identity:     identity,
+		requireTower: true,
+		command:      command,
+		logger:       log.Default(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adminRPC.calls != 1 || !adminRPC.requireTower || string(adminRPC.keypair) != string(identity.Key) {
+		t.Errorf("expected one call with the identity keypair and requireTower, got %+v", adminRPC)
+	}
+	if _, err := os.Stat(ranPath); err == nil {
+		t.Error("expected the set-identity command not to run")
+	}
+}
+
+func TestSetIdentity_AdminRPCRejected(t *testing.T) {
+	adminRPC := &fakeAdminRPC{err: errors.New("tower not found")}
+	command, ranPath := touchCommand(t)
+
+	err := setIdentity(context.Background(), setIdentityParams{
+		adminRPC: adminRPC,
+		identity: testIdentity(t),
+		command:  command,
+		logger:   log.Default(),
+	})
+	if err == nil {
+		t.Fatal("expected the rejection to be returned")
+	}
+	if _, err := os.Stat(ranPath); err == nil {
+		t.Error("expected no fallback to the command when the validator rejects the request")
+	}
+}
+
+func TestSetIdentity_AdminRPCUnavailableFallsBackToCommand(t *testing.T) {
+	adminRPC := &fakeAdminRPC{err: fmt.Errorf("%w: connection refused", agave.ErrAdminRPCUnavailable)}
+	command, ranPath := touchCommand(t)
+
+	err := setIdentity(context.Background(), setIdentityParams{
+		adminRPC: adminRPC,
+		identity: testIdentity(t),
+		command:  command,
+		logger:   log.Default(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(ranPath); err != nil {
+		t.Errorf("expected the set-identity command to run: %v", err)
+	}
+}
+
+func TestSetIdentity_DryRunSkipsAdminRPC(t *testing.T) {
+	adminRPC := &fakeAdminRPC{}
+
+	err := setIdentity(context.Background(), setIdentityParams{
+		adminRPC: adminRPC,
+		identity: testIdentity(t),
+		dryRun:   true,
+		logger:   log.Default(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adminRPC.calls != 0 {
+		t.Errorf("expected no admin rpc calls in a dry run, got %d", adminRPC.calls)
+	}
+}
@@ -96,6 +96,11 @@ func (s *Stream) SetPassiveNodeInfo(passiveNodeInfo *NodeInfo) {
 	s.message.PassiveNodeInfo = *passiveNodeInfo
 }
 
+// SetPassiveNodeHookOutputs records the outputs of hooks run on the passive node
+func (s *Stream) SetPassiveNodeHookOutputs(outputs map[string]map[string]string) {
+	s.message.PassiveNodeInfo.HookOutputs = outputs
+}
+
 // GetPassiveNodeInfo returns the passive node info
 func (s *Stream) GetPassiveNodeInfo() *NodeInfo {
 	return &s.message.PassiveNodeInfo
@@ -106,6 +111,11 @@ func (s *Stream) SetActiveNodeInfo(activeNodeInfo *NodeInfo) {
 	s.message.ActiveNodeInfo = *activeNodeInfo
 }
 
+// SetActiveNodeHookOutputs records the outputs of hooks run on the active node
+func (s *Stream) SetActiveNodeHookOutputs(outputs map[string]map[string]string) {
+	s.message.ActiveNodeInfo.HookOutputs = outputs
+}
+
 // GetActiveNodeInfo returns the active node info
 func (s *Stream) GetActiveNodeInfo() *NodeInfo {
 	return &s.message.ActiveNodeInfo
@@ -162,11 +172,50 @@ func (s Stream) GetActiveRollbackEnabled() bool {
 	return s.message.ActiveRollbackEnabled
 }
 
+// SetVerifyHandover records whether the server will verify its votes land before completing the
+// failover. The server is the authority on this, as it is for skipping tower sync.
+func (s *Stream) SetVerifyHandover(verify bool) {
+	s.message.VerifyHandover = verify
+}
+
+// GetVerifyHandover returns whether the server will verify its votes land before completing the failover
+func (s Stream) GetVerifyHandover() bool {
+	return s.message.VerifyHandover
+}
+
+// SetReadyToSwitch signals to the server that the client has finished waiting for the schedule,
+// leader checks and pre hooks, and that it should re-run its preflight before the client goes passive
+func (s *Stream) SetReadyToSwitch(ready bool) {
+	s.message.ReadyToSwitch = ready
+}
+
+// GetReadyToSwitch returns whether the client has finished waiting and is ready to switch
+func (s Stream) GetReadyToSwitch() bool {
+	return s.message.ReadyToSwitch
+}
+
+// SetHandoverUnverified signals to the client that the server may have gone active - its votes
+// didn't land or it never confirmed the active identity - and that it has gone back to passive,
+// sending its tower file unless skipping tower sync
+func (s *Stream) SetHandoverUnverified(unverified bool) {
+	s.message.HandoverUnverified = unverified
+}
+
+// GetHandoverUnverified returns true if the server may have gone active and has gone back to passive
+func (s Stream) GetHandoverUnverified() bool {
+	return s.message.HandoverUnverified
+}
+
 // SetFailoverStartSlot sets the failover start slot
 func (s *Stream) SetFailoverStartSlot(failoverStartSlot uint64) {
 	s.message.FailoverStartSlot = failoverStartSlot
 }
 
+// SetFailoverStartSlotDetection sets how the start of the failover start slot was detected
+func (s *Stream) SetFailoverStartSlotDetection(transition solana.SlotTransition) {
+	s.message.FailoverStartSlotDetection = transition
+}
+
 // GetFailoverStartSlot returns the failover start slot
 func (s Stream) GetFailoverStartSlot() uint64 {
 	return s.message.FailoverStartSlot
@@ -182,13 +231,26 @@ func (s Stream) GetFailoverEndSlot() uint64 {
 	return s.message.FailoverEndSlot
 }
 
+// SetSchedule sets the failover schedule
+func (s *Stream) SetSchedule(schedule Schedule) {
+	s.message.Schedule = schedule
+}
+
+// GetSchedule returns the failover schedule
+func (s Stream) GetSchedule() Schedule {
+	return s.message.Schedule
+}
+
 // buildHookTemplateDataForActiveNode builds HookTemplateData for the active node (client) from Stream data
 func (s *Stream) buildHookTemplateDataForActiveNode(isPreFailover bool, rpcURL string) hooks.HookTemplateData {
 	data := hooks.HookTemplateData{
 		IsDryRunFailover: s.message.IsDryRunFailover,
+		Phase:            hooks.PhasePost,
+		Cluster:          s.message.ActiveNodeInfo.Cluster,
 	}
 
 	if isPreFailover {
+		data.Phase = hooks.PhasePre
 		data.ThisNodeRole = "active"
 		data.PeerNodeRole = "passive"
 	} else {
@@ -222,9 +284,12 @@ func (s *Stream) buildHookTemplateDataForActiveNode(isPreFailover bool, rpcURL s
 func (s *Stream) buildHookTemplateDataForPassiveNode(isPreFailover bool, rpcURL string) hooks.HookTemplateData {
 	data := hooks.HookTemplateData{
 		IsDryRunFailover: s.message.IsDryRunFailover,
+		Phase:            hooks.PhasePost,
+		Cluster:          s.message.PassiveNodeInfo.Cluster,
 	}
 
 	if isPreFailover {
+		data.Phase = hooks.PhasePre
 		data.ThisNodeRole = "passive"
 		data.PeerNodeRole = "active"
 	} else {
@@ -262,6 +327,8 @@ func (s *Stream) ConfirmFailover(failoverHooks hooks.FailoverHooks, rollback hoo
 	data := PlanData{
 		IsDryRun:            s.message.IsDryRunFailover,
 		SkipTowerSync:       s.message.SkipTowerSync,
+		Schedule:            s.message.Schedule,
+		SlotDuration:        s.message.ActiveNodeInfo.SlotDuration,
 		ActiveNodeInfo:      s.message.ActiveNodeInfo,
 		PassiveNodeInfo:     s.message.PassiveNodeInfo,
 		AppVersion:          pkgconstants.AppVersion,
@@ -318,6 +385,14 @@ func (s *Stream) GetFailoverSlotsDuration() uint64 {
 	return s.GetFailoverEndSlot() - s.GetFailoverStartSlot()
 }
 
+// sinceStart returns the time from start to end, or zero if end was never recorded
+func sinceStart(start, end time.Time) time.Duration {
+	if end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
+
 // BuildSummaryData builds a SummaryData from the current stream message state.
 // Call this after the failover is complete and all timing fields are set.
 func (s *Stream) BuildSummaryData() SummaryData {
@@ -334,9 +409,13 @@ func (s *Stream) BuildSummaryData() SummaryData {
 		OrigPassiveSetIdentityDuration: s.message.PassiveNodeSetIdentityEndTime.Sub(s.message.PassiveNodeSetIdentityStartTime),
 		TotalDuration:                  s.GetFailoverDuration(),
 
-		FailoverStartSlot: s.message.FailoverStartSlot,
-		FailoverEndSlot:   s.message.FailoverEndSlot,
-		SlotsDuration:     s.GetFailoverSlotsDuration(),
+		OrigActiveIdentityConfirmedDuration:  sinceStart(s.message.ActiveNodeSetIdentityStartTime, s.message.ActiveNodeIdentityConfirmedTime),
+		OrigPassiveIdentityConfirmedDuration: sinceStart(s.message.PassiveNodeSetIdentityStartTime, s.message.PassiveNodeIdentityConfirmedTime),
+
+		FailoverStartSlot:          s.message.FailoverStartSlot,
+		FailoverStartSlotDetection: s.message.FailoverStartSlotDetection,
+		FailoverEndSlot:            s.message.FailoverEndSlot,
+		SlotsDuration:              s.GetFailoverSlotsDuration(),
 	}
 }
 
@@ -350,6 +429,11 @@ func (s *Stream) SetActiveNodeSetIdentityEndTime() {
 	s.message.ActiveNodeSetIdentityEndTime = time.Now()
 }
 
+// SetActiveNodeIdentityConfirmedTime sets when the active node's validator reported its new identity
+func (s *Stream) SetActiveNodeIdentityConfirmedTime(t time.Time) {
+	s.message.ActiveNodeIdentityConfirmedTime = t
+}
+
 // SetActiveNodeSyncTowerFileStartTime sets the active node sync tower file start time
 func (s *Stream) SetActiveNodeSyncTowerFileStartTime() {
 	s.message.ActiveNodeSyncTowerFileStartTime = time.Now()
@@ -370,6 +454,11 @@ func (s *Stream) SetPassiveNodeSetIdentityEndTime() {
 	s.message.PassiveNodeSetIdentityEndTime = time.Now()
 }
 
+// SetPassiveNodeIdentityConfirmedTime sets when the passive node's validator reported its new identity
+func (s *Stream) SetPassiveNodeIdentityConfirmedTime(t time.Time) {
+	s.message.PassiveNodeIdentityConfirmedTime = t
+}
+
 // SetPassiveNodeSyncTowerFileEndTime sets the passive node sync tower file end time
 func (s *Stream) SetPassiveNodeSyncTowerFileEndTime() {
 	s.message.PassiveNodeSyncTowerFileEndTime = time.Now()
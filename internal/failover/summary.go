@@ -10,6 +10,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 )
 
@@ -29,11 +30,16 @@ type SummaryData struct {
 	TowerFileSizeBytes             int64
 	OrigPassiveSetIdentityDuration time.Duration
 	TotalDuration                  time.Duration
+	// time from set-identity start until the local validator reported the new identity, zero when
+	// not checked (dry runs)
+	OrigActiveIdentityConfirmedDuration  time.Duration
+	OrigPassiveIdentityConfirmedDuration time.Duration
 
 	// Slots
-	FailoverStartSlot uint64
-	FailoverEndSlot   uint64
-	SlotsDuration     uint64
+	FailoverStartSlot          uint64
+	FailoverStartSlotDetection solana.SlotTransition
+	FailoverEndSlot            uint64
+	SlotsDuration              uint64
 
 	// Vote credit rank (optional, populated after credit monitoring)
 	HasVoteRankData bool
@@ -105,7 +111,16 @@ func RenderFailoverSummary(data SummaryData) (string, error) {
         {{ Muted "identity =" }} {{ Passive (truncPubkey .OrigActiveNode.Identities.Passive.PubKey) false }}
         {{ Muted "ip       =" }} {{ LightGrey .OrigActiveNode.PublicIP }}
         {{ Muted "took     =" }} {{ LightGrey (FormatDuration .OrigActiveSetIdentityDuration) }}
+{{- if .OrigActiveIdentityConfirmedDuration }}
+        {{ Muted "verified =" }} {{ LightGrey (printf "after %s" (FormatDuration .OrigActiveIdentityConfirmedDuration)) }}
+{{- end }}
         {{ Muted "at_slot  =" }} {{ LightGrey (FormatSlot .FailoverStartSlot) }}
+{{- if .FailoverStartSlotDetection.Source }}
+        {{ Muted "detected =" }} {{ LightGrey .FailoverStartSlotDetection.String }}
+{{- end }}
+{{- range $hook, $outputs := .OrigActiveNode.HookOutputs }}{{ range $key, $value := $outputs }}
+        {{ Muted "output   =" }} {{ LightGrey (printf "%s.%s=%s" $hook $key $value) }}
+{{- end }}{{ end }}
 {{ if not .SkipTowerSync }}
   {{ LightGrey "tower" }}
         {{ Muted "took     =" }} {{ LightGrey (FormatDuration .TowerSyncDuration) }}
@@ -116,7 +131,13 @@ func RenderFailoverSummary(data SummaryData) (string, error) {
         {{ Muted "identity =" }} {{ Active (truncPubkey .OrigPassiveNode.Identities.Active.PubKey) false }}
         {{ Muted "ip       =" }} {{ LightGrey .OrigPassiveNode.PublicIP }}
         {{ Muted "took     =" }} {{ LightGrey (FormatDuration .OrigPassiveSetIdentityDuration) }}
+{{- if .OrigPassiveIdentityConfirmedDuration }}
+        {{ Muted "verified =" }} {{ LightGrey (printf "after %s" (FormatDuration .OrigPassiveIdentityConfirmedDuration)) }}
+{{- end }}
         {{ Muted "at_slot  =" }} {{ LightGrey (FormatSlot .FailoverEndSlot) }}
+{{- range $hook, $outputs := .OrigPassiveNode.HookOutputs }}{{ range $key, $value := $outputs }}
+        {{ Muted "output   =" }} {{ LightGrey (printf "%s.%s=%s" $hook $key $value) }}
+{{- end }}{{ end }}
 {{ if .HasVoteRankData }}
   {{ Purple "Vote credits:" }} {{ Muted "rank" }} {{ if gt .VoteRankDiff 0 }}{{ Active (printf "improved by +%d" .VoteRankDiff) false }}{{ else if lt .VoteRankDiff 0 }}{{ Passive (printf "worsened by %d" .VoteRankDiff) false }}{{ else }}{{ LightGrey "unchanged" }}{{ end }} {{ Muted (printf "(%d → %d)" .VoteRankFirst .VoteRankLast) }}
 {{ end }}
@@ -128,23 +149,26 @@ func RenderFailoverSummary(data SummaryData) (string, error) {
 
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, map[string]any{
-		"IsDryRun":                       data.IsDryRun,
-		"SkipTowerSync":                  data.SkipTowerSync,
-		"OrigActiveNode":                 data.OrigActiveNode,
-		"OrigPassiveNode":                data.OrigPassiveNode,
-		"LabelWidth":                     labelWidth,
-		"OrigActiveSetIdentityDuration":  data.OrigActiveSetIdentityDuration,
-		"TowerSyncDuration":              data.TowerSyncDuration,
-		"TowerFileSizeBytes":             data.TowerFileSizeBytes,
-		"OrigPassiveSetIdentityDuration": data.OrigPassiveSetIdentityDuration,
-		"TotalDuration":                  data.TotalDuration,
-		"FailoverStartSlot":              data.FailoverStartSlot,
-		"FailoverEndSlot":                data.FailoverEndSlot,
-		"SlotsDuration":                  data.SlotsDuration,
-		"HasVoteRankData":                data.HasVoteRankData,
-		"VoteRankDiff":                   data.VoteRankDiff,
-		"VoteRankFirst":                  data.VoteRankFirst,
-		"VoteRankLast":                   data.VoteRankLast,
+		"IsDryRun":                             data.IsDryRun,
+		"SkipTowerSync":                        data.SkipTowerSync,
+		"OrigActiveNode":                       data.OrigActiveNode,
+		"OrigPassiveNode":                      data.OrigPassiveNode,
+		"LabelWidth":                           labelWidth,
+		"OrigActiveSetIdentityDuration":        data.OrigActiveSetIdentityDuration,
+		"TowerSyncDuration":                    data.TowerSyncDuration,
+		"TowerFileSizeBytes":                   data.TowerFileSizeBytes,
+		"OrigPassiveSetIdentityDuration":       data.OrigPassiveSetIdentityDuration,
+		"TotalDuration":                        data.TotalDuration,
+		"OrigActiveIdentityConfirmedDuration":  data.OrigActiveIdentityConfirmedDuration,
+		"OrigPassiveIdentityConfirmedDuration": data.OrigPassiveIdentityConfirmedDuration,
+		"FailoverStartSlot":                    data.FailoverStartSlot,
+		"FailoverStartSlotDetection":           data.FailoverStartSlotDetection,
+		"FailoverEndSlot":                      data.FailoverEndSlot,
+		"SlotsDuration":                        data.SlotsDuration,
+		"HasVoteRankData":                      data.HasVoteRankData,
+		"VoteRankDiff":                         data.VoteRankDiff,
+		"VoteRankFirst":                        data.VoteRankFirst,
+		"VoteRankLast":                         data.VoteRankLast,
 	}); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
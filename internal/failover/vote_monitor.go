@@ -0,0 +1,137 @@
+package failover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+const (
+	// voteMonitorInterval is how often the vote account is sampled while monitoring votes
+	voteMonitorInterval = 2 * time.Second
+	// rootLagSlots is how far a voting validator's root normally trails its last vote - a vote is
+	// rooted once 31 more have landed on top of it
+	rootLagSlots = 32
+)
+
+// VoteMonitorConfig holds the configuration for post-failover vote monitoring
+type VoteMonitorConfig struct {
+	Enabled     bool
+	WithinSlots uint64        // votes must land within this many slots of the failover, and keep landing
+	Duration    time.Duration // how long to monitor for
+}
+
+// VoteMonitorResult is what post-failover vote monitoring saw
+type VoteMonitorResult struct {
+	StartSlot                  uint64
+	FirstVoteSlot              uint64 // cluster slot a vote after StartSlot was first seen at, 0 if never
+	LastVote                   uint64
+	RootSlot                   uint64
+	Samples                    int
+	CreditsEarned              int64 // credits the vote account earned while monitoring
+	ClusterMedianCreditsEarned int64 // median credits current vote accounts earned while monitoring
+}
+
+// CreditsBelowMedian returns true when the vote account earned less than half the cluster median
+// while monitoring
+func (r VoteMonitorResult) CreditsBelowMedian() bool {
+	return r.ClusterMedianCreditsEarned > 0 && r.CreditsEarned*2 < r.ClusterMedianCreditsEarned
+}
+
+// monitorVotes samples identity's voting every interval until cfg.Duration has passed. It returns an
+// error as soon as votes after startSlot haven't landed within cfg.WithinSlots slots, stop landing
+// for that long, or the root hasn't moved past startSlot within cfg.WithinSlots + rootLagSlots -
+// and at the end if no vote landed at all. onSample, when set, is called after each sample.
+func monitorVotes(ctx context.Context, client solana.ClientInterface, identity string, startSlot uint64, cfg VoteMonitorConfig, interval time.Duration, onSample func(VoteMonitorResult)) (result VoteMonitorResult, err error) {
+	result.StartSlot = startSlot
+	deadline := time.Now().Add(cfg.Duration)
+	var first *solana.VoteProgress
+	var lastErr error
+
+	for {
+		progress, sampleErr := client.GetVoteProgress(identity)
+		if sampleErr != nil {
+			lastErr = sampleErr
+		} else {
+			result.Samples++
+			if first == nil || progress.EpochCredits < first.EpochCredits {
+				// first sample, or the epoch rolled over and credits restarted
+				first = progress
+			}
+			result.LastVote = progress.LastVote
+			result.RootSlot = progress.RootSlot
+			result.CreditsEarned = progress.EpochCredits - first.EpochCredits
+			result.ClusterMedianCreditsEarned = progress.ClusterMedianCredits - first.ClusterMedianCredits
+
+			if result.FirstVoteSlot == 0 && progress.LastVote > startSlot {
+				result.FirstVoteSlot = progress.Slot
+			}
+			switch {
+			case result.FirstVoteSlot == 0 && progress.Slot > startSlot+cfg.WithinSlots:
+				return result, fmt.Errorf("no votes landed within %d slots of the failover at slot %d - last vote %d, cluster slot %d",
+					cfg.WithinSlots, startSlot, progress.LastVote, progress.Slot)
+			case result.FirstVoteSlot != 0 && progress.Slot > progress.LastVote+cfg.WithinSlots:
+				return result, fmt.Errorf("votes stalled - last vote %d is %d slots behind cluster slot %d",
+					progress.LastVote, progress.Slot-progress.LastVote, progress.Slot)
+			case progress.RootSlot <= startSlot && progress.Slot > startSlot+cfg.WithinSlots+rootLagSlots:
+				return result, fmt.Errorf("root slot %d has not advanced past the failover at slot %d - cluster slot %d",
+					progress.RootSlot, startSlot, progress.Slot)
+			}
+		}
+		if onSample != nil {
+			onSample(result)
+		}
+
+		if !time.Now().Add(interval).Before(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	switch {
+	case result.Samples == 0:
+		return result, fmt.Errorf("failed to sample vote account: %w", lastErr)
+	case result.FirstVoteSlot == 0:
+		return result, fmt.Errorf("no votes landed after the failover at slot %d within %s - last vote %d", startSlot, cfg.Duration, result.LastVote)
+	}
+	return result, nil
+}
+
+// waitForVotes blocks until a vote by identity after startSlot has landed, returning the progress
+// it was seen in. It fails once the cluster is more than withinSlots past startSlot without one,
+// or when ctx is done - with the last sampling error, if any.
+func waitForVotes(ctx context.Context, client solana.ClientInterface, identity string, startSlot, withinSlots uint64, interval time.Duration, onSample func(*solana.VoteProgress)) (*solana.VoteProgress, error) {
+	var lastErr error
+	for {
+		progress, err := client.GetVoteProgress(identity)
+		if err != nil {
+			lastErr = err
+		} else {
+			if onSample != nil {
+				onSample(progress)
+			}
+			if progress.LastVote > startSlot {
+				return progress, nil
+			}
+			if progress.Slot > startSlot+withinSlots {
+				return nil, fmt.Errorf("no votes landed within %d slots of the failover at slot %d - last vote %d, cluster slot %d",
+					withinSlots, startSlot, progress.LastVote, progress.Slot)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("no votes seen landing after the failover at slot %d: %w", startSlot, lastErr)
+			}
+			return nil, fmt.Errorf("no votes seen landing after the failover at slot %d: %w", startSlot, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+// voteProgressSequenceMock builds a MockClient whose GetVoteProgress returns successive values
+// from the provided slice, repeating the last one once it is exhausted
+func voteProgressSequenceMock(progress []solana.VoteProgress) *solana.MockClient {
+	i := 0
+	return solana.NewMockClient().WithGetVoteProgress(func(identity string) (*solana.VoteProgress, error) {
+		p := progress[i]
+		if i < len(progress)-1 {
+			i++
+		}
+		return &p, nil
+	})
+}
+
+var testVoteMonitorConfig = VoteMonitorConfig{
+	Enabled:     true,
+	WithinSlots: 64,
+	Duration:    20 * time.Millisecond,
+}
+
+// TestMonitorVotes_Landing checks that votes landing after the failover, with the root following,
+// pass and the credits earned are measured from the first sample
+func TestMonitorVotes_Landing(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1002, LastVote: 998, RootSlot: 966, EpochCredits: 5000, ClusterMedianCredits: 5200},
+		{Slot: 1010, LastVote: 1008, RootSlot: 976, EpochCredits: 5100, ClusterMedianCredits: 5320},
+		{Slot: 1040, LastVote: 1039, RootSlot: 1007, EpochCredits: 5400, ClusterMedianCredits: 5600},
+	})
+
+	samples := 0
+	result, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, func(VoteMonitorResult) {
+		samples++
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FirstVoteSlot != 1010 {
+		t.Errorf("expected first vote seen at slot 1010, got %d", result.FirstVoteSlot)
+	}
+	if result.LastVote != 1039 || result.RootSlot != 1007 {
+		t.Errorf("expected last vote 1039 root 1007, got %d %d", result.LastVote, result.RootSlot)
+	}
+	if result.CreditsEarned != 400 || result.ClusterMedianCreditsEarned != 400 {
+		t.Errorf("expected 400 credits earned against a median of 400, got %d %d", result.CreditsEarned, result.ClusterMedianCreditsEarned)
+	}
+	if result.CreditsBelowMedian() {
+		t.Error("expected credits not below median")
+	}
+	if samples != result.Samples || samples < 3 {
+		t.Errorf("expected onSample called once per sample, got %d calls for %d samples", samples, result.Samples)
+	}
+}
+
+// TestMonitorVotes_NoVotesWithinSlots checks that a vote account still showing its last vote from
+// before the failover fails once the cluster is more than within_slots past it
+func TestMonitorVotes_NoVotesWithinSlots(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1002, LastVote: 998, RootSlot: 966},
+		{Slot: 1065, LastVote: 998, RootSlot: 966},
+	})
+
+	_, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "no votes landed within 64 slots") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestMonitorVotes_Stalled checks that votes which land and then stop fail once the cluster is
+// more than within_slots past the last one
+func TestMonitorVotes_Stalled(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1010, LastVote: 1008, RootSlot: 976},
+		{Slot: 1080, LastVote: 1012, RootSlot: 1001},
+	})
+
+	_, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "votes stalled - last vote 1012 is 68 slots behind") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestMonitorVotes_RootNotAdvancing checks that votes landing without the root moving past the
+// failover slot fail once the root should have caught up
+func TestMonitorVotes_RootNotAdvancing(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1010, LastVote: 1008, RootSlot: 966},
+		{Slot: 1100, LastVote: 1099, RootSlot: 966},
+	})
+
+	_, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "root slot 966 has not advanced past the failover at slot 1000") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestMonitorVotes_NoVoteBeforeDuration checks that monitoring ending before the cluster passes
+// within_slots still fails when no vote landed
+func TestMonitorVotes_NoVoteBeforeDuration(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1002, LastVote: 998, RootSlot: 966},
+	})
+
+	_, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "no votes landed after the failover at slot 1000") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestMonitorVotes_RPCErrors checks that sampling errors are tolerated, and returned when no sample
+// succeeded at all
+func TestMonitorVotes_RPCErrors(t *testing.T) {
+	boom := errors.New("connection refused")
+	calls := 0
+	mock := solana.NewMockClient().WithGetVoteProgress(func(identity string) (*solana.VoteProgress, error) {
+		calls++
+		return nil, boom
+	})
+
+	_, err := monitorVotes(context.Background(), mock, "identity", 1000, testVoteMonitorConfig, time.Millisecond, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped rpc error, got: %v", err)
+	}
+	if calls < 2 {
+		t.Errorf("expected vote progress to be sampled repeatedly, got %d calls", calls)
+	}
+}
+
+// TestVoteMonitorResult_CreditsBelowMedian checks the credits warning threshold
+func TestVoteMonitorResult_CreditsBelowMedian(t *testing.T) {
+	cases := []struct {
+		earned, median int64
+		want           bool
+	}{
+		{earned: 100, median: 100, want: false},
+		{earned: 50, median: 100, want: false},
+		{earned: 49, median: 100, want: true},
+		{earned: 0, median: 0, want: false},
+	}
+	for _, c := range cases {
+		r := VoteMonitorResult{CreditsEarned: c.earned, ClusterMedianCreditsEarned: c.median}
+		if got := r.CreditsBelowMedian(); got != c.want {
+			t.Errorf("earned %d median %d: expected %v, got %v", c.earned, c.median, c.want, got)
+		}
+	}
+}
+
+// TestWaitForVotes_Landed checks that waiting returns as soon as a vote after the failover lands
+func TestWaitForVotes_Landed(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1002, LastVote: 998},
+		{Slot: 1006, LastVote: 999},
+		{Slot: 1009, LastVote: 1007},
+	})
+
+	progress, err := waitForVotes(context.Background(), mock, "identity", 1000, 64, time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.LastVote != 1007 {
+		t.Errorf("expected last vote 1007, got %d", progress.LastVote)
+	}
+}
+
+// TestWaitForVotes_NotWithinSlots checks that waiting fails once the cluster is more than
+// withinSlots past the failover without a vote
+func TestWaitForVotes_NotWithinSlots(t *testing.T) {
+	mock := voteProgressSequenceMock([]solana.VoteProgress{
+		{Slot: 1002, LastVote: 998},
+		{Slot: 1070, LastVote: 998},
+	})
+
+	_, err := waitForVotes(context.Background(), mock, "identity", 1000, 64, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "no votes landed within 64 slots of the failover at slot 1000") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForVotes_Timeout checks that waiting gives up when ctx is done, with the last rpc error
+func TestWaitForVotes_Timeout(t *testing.T) {
+	boom := errors.New("connection refused")
+	mock := solana.NewMockClient().WithGetVoteProgress(func(identity string) (*solana.VoteProgress, error) {
+		return nil, boom
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := waitForVotes(ctx, mock, "identity", 1000, 64, time.Millisecond, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped rpc error, got: %v", err)
+	}
+}
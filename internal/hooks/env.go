@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	inheritEnvNone = "none"
+	inheritEnvAll  = "all"
+)
+
+// inheritEnv is the parsed form of Hook.InheritEnv
+type inheritEnv struct {
+	all   bool
+	names []string // exact names, or prefixes ending in *
+}
+
+// parseInheritEnv parses inherit_env, which is "none", "all" or a list of variable names
+func parseInheritEnv(v any) (inherit inheritEnv, err error) {
+	switch v := v.(type) {
+	case nil:
+		return inherit, nil
+	case string:
+		switch v {
+		case "", inheritEnvNone:
+			return inherit, nil
+		case inheritEnvAll:
+			inherit.all = true
+			return inherit, nil
+		default:
+			return inherit, fmt.Errorf("must be %q, %q or a list of variable names, got %q", inheritEnvNone, inheritEnvAll, v)
+		}
+	case []string:
+		inherit.names = v
+	case []any:
+		for _, name := range v {
+			s, ok := name.(string)
+			if !ok {
+				return inherit, fmt.Errorf("variable names must be strings, got %v", name)
+			}
+			inherit.names = append(inherit.names, s)
+		}
+	default:
+		return inherit, fmt.Errorf("must be %q, %q or a list of variable names, got %v", inheritEnvNone, inheritEnvAll, v)
+	}
+
+	for _, name := range inherit.names {
+		if strings.TrimSuffix(name, "*") == "" || strings.Contains(name, "=") {
+			return inherit, fmt.Errorf("invalid variable name %q", name)
+		}
+	}
+	return inherit, nil
+}
+
+// matches returns true if the variable should be inherited
+func (i inheritEnv) matches(key string) bool {
+	if i.all {
+		return true
+	}
+	for _, name := range i.names {
+		if prefix, isPrefix := strings.CutSuffix(name, "*"); isPrefix {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// environ returns the variables from this process's environment to inherit
+func (i inheritEnv) environ() map[string]string {
+	env := map[string]string{}
+	if !i.all && len(i.names) == 0 {
+		return env
+	}
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if i.matches(key) {
+			env[key] = value
+		}
+	}
+	return env
+}
@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInheritEnv(t *testing.T) {
+	for _, v := range []any{nil, "", "none"} {
+		inherit, err := parseInheritEnv(v)
+		require.NoError(t, err)
+		assert.Empty(t, inherit.environ())
+	}
+
+	inherit, err := parseInheritEnv("all")
+	require.NoError(t, err)
+	assert.True(t, inherit.all)
+
+	inherit, err = parseInheritEnv([]any{"PATH", "LC_*"})
+	require.NoError(t, err)
+	assert.True(t, inherit.matches("PATH"))
+	assert.True(t, inherit.matches("LC_ALL"))
+	assert.False(t, inherit.matches("HOME"))
+
+	for _, bad := range []any{"some", []any{1}, []any{"*"}, []any{"A=B"}, 3} {
+		_, err := parseInheritEnv(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestHookRun_InheritEnv(t *testing.T) {
+	t.Setenv("HOOK_TEST_INHERITED", "from-parent")
+	t.Setenv("HOOK_TEST_OVERRIDDEN", "from-parent")
+	t.Setenv("HOOK_TEST_NOT_LISTED", "from-parent")
+
+	got := filepath.Join(t.TempDir(), "env")
+	h := Hook{
+		Name:        "env",
+		Command:     "sh",
+		Args:        []string{"-c", `echo "$HOOK_TEST_INHERITED $HOOK_TEST_OVERRIDDEN [$HOOK_TEST_NOT_LISTED]" > ` + got},
+		Environment: map[string]string{"HOOK_TEST_OVERRIDDEN": "from-config"},
+		InheritEnv:  []any{"HOOK_TEST_INHERITED", "HOOK_TEST_OVER*"},
+	}
+	require.NoError(t, h.Configure())
+	_, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+
+	out, err := os.ReadFile(got)
+	require.NoError(t, err)
+	assert.Equal(t, "from-parent from-config []\n", string(out))
+}
@@ -3,29 +3,205 @@ package hooks
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"text/template"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 )
 
+const (
+	// HookTypeCommand runs a local command (the default)
+	HookTypeCommand = "command"
+	// HookTypeHTTP sends an HTTP request
+	HookTypeHTTP = "http"
+)
+
 // Hook is a hook that is called before or after a failover
 type Hook struct {
-	Name        string            `mapstructure:"name"`
+	Name string `mapstructure:"name"`
+	// Type is either "command" (default) or "http"
+	Type        string            `mapstructure:"type"`
 	Command     string            `mapstructure:"command"`
 	Args        []string          `mapstructure:"args"`
 	MustSucceed bool              `mapstructure:"must_succeed"`
 	Environment map[string]string `mapstructure:"environment"`
+	// HTTP configures the request sent by http hooks
+	HTTP HTTPConfig `mapstructure:"http"`
+	// Timeout bounds a single attempt, e.g. "30s". Empty means no timeout.
+	Timeout string `mapstructure:"timeout"`
+	// Retries is the number of extra attempts made after a failed attempt. Default: 0.
+	Retries int `mapstructure:"retries"`
+	// RetryBackoff is how long to wait between attempts, e.g. "2s". Empty means retry immediately.
+	RetryBackoff string `mapstructure:"retry_backoff"`
+	// When is an optional template evaluated against HookTemplateData before the hook runs, e.g.
+	// "{{ not .IsDryRunFailover }}". The hook is skipped unless it renders to true.
+	When string `mapstructure:"when"`
+	// Group runs the hook concurrently with the consecutive hooks in the same group. Parallel is
+	// shorthand for a shared default group. The next hook waits until the whole group finishes.
+	Group    string `mapstructure:"group"`
+	Parallel bool   `mapstructure:"parallel"`
+	// InheritEnv controls which of this process's environment variables a command hook inherits:
+	// "none" (the default), "all", or a list of names where a trailing * matches a prefix,
+	// e.g. ["PATH", "HOME", "LC_*"]
+	InheritEnv any `mapstructure:"inherit_env"`
+	// User runs a command hook as another user, as "user" or "user:group" (names or IDs). Needs root.
+	User string `mapstructure:"user"`
+	// WorkingDir is the directory a command hook runs in. Default: this process's working directory.
+	WorkingDir string `mapstructure:"working_dir"`
+	// Umask is the octal umask a command hook runs with, e.g. "027"
+	Umask string `mapstructure:"umask"`
+	// Background runs a failover.hooks phase hook without waiting for it, so it never delays the
+	// handover. Its outputs may not be available to later hooks or the summary. Ignored for pre/post hooks.
+	Background bool `mapstructure:"background"`
+
+	// TimeoutDuration and RetryBackoffDuration are parsed from Timeout and RetryBackoff by
+	// Configure (not read from YAML).
+	TimeoutDuration      time.Duration `mapstructure:"-"`
+	RetryBackoffDuration time.Duration `mapstructure:"-"`
+
+	// inheritEnv and runAs are parsed from InheritEnv, User, WorkingDir and Umask by Configure,
+	// which sets configured once it succeeds
+	inheritEnv inheritEnv
+	runAs      runAs
+	configured bool
+}
+
+// hookWaitDelay is how long to wait for a killed hook's output pipes to close before giving up
+// on them - a child that escaped the process group could otherwise hold them open forever.
+const hookWaitDelay = 5 * time.Second
+
+// Attempt records a single run of a hook
+type Attempt struct {
+	Number    int
+	StartTime time.Time
+	Duration  time.Duration
+	TimedOut  bool
+	Err       error
+}
+
+// Result is the outcome of running a hook, with every attempt that was made
+type Result struct {
+	Name     string
+	Attempts []Attempt
+	// Skipped is true when the hook's when expression was false, with the reason in SkipReason
+	Skipped    bool
+	SkipReason string
+	// Outputs are the key/value outputs of the successful attempt
+	Outputs map[string]string
+}
+
+// Succeeded returns true if the last attempt succeeded
+func (r Result) Succeeded() bool {
+	return len(r.Attempts) > 0 && r.Attempts[len(r.Attempts)-1].Err == nil
+}
+
+// String returns a one-line summary of the attempt history, e.g. "1: timed out after 30s, 2: ok (1.2s)"
+func (r Result) String() string {
+	if r.Skipped {
+		return "skipped: " + r.SkipReason
+	}
+	parts := make([]string, len(r.Attempts))
+	for i, a := range r.Attempts {
+		switch {
+		case a.Err == nil:
+			parts[i] = fmt.Sprintf("%d: ok (%s)", a.Number, a.Duration.Round(time.Millisecond))
+		default:
+			parts[i] = fmt.Sprintf("%d: %v (%s)", a.Number, a.Err, a.Duration.Round(time.Millisecond))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Configure validates the hook's type and retry settings and parses its durations
+func (h *Hook) Configure() error {
+	h.configured = false
+	switch h.Type {
+	case "", HookTypeCommand:
+		h.Type = HookTypeCommand
+		if h.Command == "" {
+			return fmt.Errorf("hook %s: command is required", h.Name)
+		}
+	case HookTypeHTTP:
+		if err := h.HTTP.configure(); err != nil {
+			return fmt.Errorf("hook %s: %w", h.Name, err)
+		}
+	default:
+		return fmt.Errorf("hook %s: unknown type %q, must be one of: %s, %s", h.Name, h.Type, HookTypeCommand, HookTypeHTTP)
+	}
+
+	if h.Retries < 0 {
+		return fmt.Errorf("hook %s: retries must be >= 0, got %d", h.Name, h.Retries)
+	}
+
+	if _, err := template.New("when").Funcs(templateFuncs).Parse(h.When); err != nil {
+		return fmt.Errorf("hook %s: invalid when %q: %w", h.Name, h.When, err)
+	}
+
+	inherit, err := parseInheritEnv(h.InheritEnv)
+	if err != nil {
+		return fmt.Errorf("hook %s: invalid inherit_env: %w", h.Name, err)
+	}
+	h.inheritEnv = inherit
+
+	if err := h.configureRunAs(); err != nil {
+		return fmt.Errorf("hook %s: %w", h.Name, err)
+	}
+
+	h.TimeoutDuration = 0
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil {
+			return fmt.Errorf("hook %s: invalid timeout %q: %w", h.Name, h.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("hook %s: timeout must be > 0, got %s", h.Name, h.Timeout)
+		}
+		h.TimeoutDuration = d
+	}
+
+	h.RetryBackoffDuration = 0
+	if h.RetryBackoff != "" {
+		d, err := time.ParseDuration(h.RetryBackoff)
+		if err != nil {
+			return fmt.Errorf("hook %s: invalid retry_backoff %q: %w", h.Name, h.RetryBackoff, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("hook %s: retry_backoff must be >= 0, got %s", h.Name, h.RetryBackoff)
+		}
+		h.RetryBackoffDuration = d
+	}
+
+	h.configured = true
+	return nil
 }
 
 // Hooks is a collection of hooks
 type Hooks []Hook
 
+// Configure configures every hook in the collection in place, returning every problem found
+func (hs Hooks) Configure() error {
+	var errs []error
+	for i := range hs {
+		errs = append(errs, hs[i].Configure())
+	}
+	return errors.Join(errs...)
+}
+
 // PreHooks is a collection of pre hooks
 type PreHooks struct {
 	WhenPassive Hooks `mapstructure:"when_passive"`
@@ -38,10 +214,35 @@ type PostHooks struct {
 	WhenActive  Hooks `mapstructure:"when_active"`
 }
 
-// FailoverHooks is a collection of hooks for pre and post failover
+// FailoverHooks is a collection of hooks for pre and post failover, and for each phase in between
 type FailoverHooks struct {
 	Pre  PreHooks  `mapstructure:"pre"`
 	Post PostHooks `mapstructure:"post"`
+
+	// phase hooks never fail the failover and can run in the background - see RunPhase
+	OnConnect         PhaseHooks `mapstructure:"on_connect"`
+	BeforeSetIdentity PhaseHooks `mapstructure:"before_set_identity"`
+	AfterSetIdentity  PhaseHooks `mapstructure:"after_set_identity"`
+	BeforeTowerSync   PhaseHooks `mapstructure:"before_tower_sync"`
+	AfterTowerSync    PhaseHooks `mapstructure:"after_tower_sync"`
+	OnFailure         PhaseHooks `mapstructure:"on_failure"`
+	OnAbort           PhaseHooks `mapstructure:"on_abort"`
+
+	// Outputs collects hook outputs so later hooks can use them. Nil records nothing.
+	Outputs *Outputs `mapstructure:"-"`
+}
+
+// Configure configures all pre, post and phase hooks in place, returning every problem found
+func (h FailoverHooks) Configure() error {
+	var errs []error
+	errs = append(errs, prefixErrors("pre.when_active", h.Pre.WhenActive.Configure())...)
+	errs = append(errs, prefixErrors("pre.when_passive", h.Pre.WhenPassive.Configure())...)
+	errs = append(errs, prefixErrors("post.when_active", h.Post.WhenActive.Configure())...)
+	errs = append(errs, prefixErrors("post.when_passive", h.Post.WhenPassive.Configure())...)
+	for _, phase := range Phases {
+		errs = append(errs, h.Phase(phase).configure(phase))
+	}
+	return errors.Join(errs...)
 }
 
 // HasPreHooksWhenActive returns true if there are any pre hooks when the validator is active
@@ -54,23 +255,40 @@ func (h FailoverHooks) HasPreHooksWhenPassive() bool {
 	return len(h.Pre.WhenPassive) > 0
 }
 
-// RollbackHooksConfig holds hooks to run after a rollback set-identity command.
-// Pre hooks are intentionally omitted: a pre hook with must_succeed could block the
-// rollback set-identity command from running, defeating the purpose of rollback.
+// RollbackHooksConfig holds hooks to run before and after a rollback set-identity command.
+// Pre hooks never block: a failing pre hook - even one with must_succeed - must not stop the
+// rollback set-identity command from running, as that would defeat the purpose of rollback.
 type RollbackHooksConfig struct {
+	Pre  Hooks `mapstructure:"pre"`
 	Post Hooks `mapstructure:"post"`
 }
 
+// CommandConfig configures a command as an argv list. Cmd, each of Args and each Env value are Go
+// templates rendered separately, so a value containing spaces stays a single argument.
+type CommandConfig struct {
+	Cmd     string            `mapstructure:"cmd"`
+	Args    []string          `mapstructure:"args"`
+	Env     map[string]string `mapstructure:"env"`
+	Timeout string            `mapstructure:"timeout"` // optional, e.g. 30s
+}
+
+// IsZero returns true if no command is configured
+func (c CommandConfig) IsZero() bool {
+	return c.Cmd == ""
+}
+
 // RollbackDirectionConfig configures one rollback direction (to_active or to_passive).
 type RollbackDirectionConfig struct {
-	// CmdTemplate is a Go template string for the set-identity rollback command.
-	// When empty, it defaults to the corresponding normal set-identity command
-	// (resolved during validator startup and stored in ResolvedCmd).
+	// Command is the set-identity rollback command as an argv list; it takes precedence over CmdTemplate.
+	Command CommandConfig `mapstructure:"command"`
+	// CmdTemplate is a Go template string for the set-identity rollback command, split into
+	// arguments like a shell would. When both are empty, it defaults to the corresponding normal
+	// set-identity command (resolved during validator startup and stored in ResolvedCmd).
 	CmdTemplate string              `mapstructure:"cmd_template"`
 	Hooks       RollbackHooksConfig `mapstructure:"hooks"`
 	// ResolvedCmd is populated during validator configuration (not read from YAML).
-	// It holds the fully-expanded command string that will be run on rollback.
-	ResolvedCmd string `mapstructure:"-"`
+	// It holds the fully-expanded command that will be run on rollback.
+	ResolvedCmd utils.Command `mapstructure:"-"`
 }
 
 // RollbackConfig is the full rollback configuration under failover.rollback.
@@ -82,6 +300,21 @@ type RollbackConfig struct {
 	ToActive RollbackDirectionConfig `mapstructure:"to_active"`
 	// ToPassive is used by the passive node (which failed to become active) to re-assert passive.
 	ToPassive RollbackDirectionConfig `mapstructure:"to_passive"`
+	// VerifyHandover keeps the nodes connected after the failover until the new active node's
+	// votes are seen landing, rolling both back if they aren't. Only applies when Enabled.
+	VerifyHandover VerifyHandoverConfig `mapstructure:"verify_handover"`
+	// Outputs makes failover hook outputs available to rollback hooks, and collects theirs
+	Outputs *Outputs `mapstructure:"-"`
+}
+
+// VerifyHandoverConfig is the configuration under failover.rollback.verify_handover
+type VerifyHandoverConfig struct {
+	Enabled bool `mapstructure:"enabled"` // default: false
+	// WithinSlots is how many slots after the failover the new active node has to land a vote
+	WithinSlots int `mapstructure:"within_slots"`
+	// Timeout bounds the wait in case slots stop advancing or rpc is unreachable
+	Timeout         string        `mapstructure:"timeout"`
+	TimeoutDuration time.Duration `mapstructure:"-"` // parsed Timeout, set during configuration
 }
 
 // HookTemplateData is the data structure available for hook templates
@@ -90,6 +323,11 @@ type HookTemplateData struct {
 	IsDryRunFailover bool
 	ThisNodeRole     string
 	PeerNodeRole     string
+	Phase            string // the failover phase the hook runs in, e.g. "pre" or "before_set_identity"
+	Error            string // the error that triggered on_failure/on_abort/rollback hooks, empty otherwise
+	Cluster          string // the solana cluster from config, e.g. "mainnet-beta" or "testnet"
+	// Outputs of hooks that already ran on this node, by hook name then key
+	Outputs map[string]map[string]string
 
 	// This node info
 	ThisNodeName                   string
@@ -123,6 +361,9 @@ func newHookTemplateData(envMap map[string]string) HookTemplateData {
 	// Parse roles
 	data.ThisNodeRole = envMap["THIS_NODE_ROLE"]
 	data.PeerNodeRole = envMap["PEER_NODE_ROLE"]
+	data.Phase = envMap["PHASE"]
+	data.Error = envMap["ERROR"]
+	data.Cluster = envMap["CLUSTER"]
 
 	// Parse this node info
 	data.ThisNodeName = envMap["THIS_NODE_NAME"]
@@ -146,6 +387,15 @@ func newHookTemplateData(envMap map[string]string) HookTemplateData {
 	return data
 }
 
+// templateFuncs are the functions available to hook templates. json quotes a value as JSON, e.g.
+// {"error": {{ json .Error }}}, so http bodies stay valid whatever the value contains.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
 // executeTemplate executes a template string with the given data
 func executeTemplate(tmplStr string, data HookTemplateData) (string, error) {
 	// If template string doesn't contain template syntax, return as-is
@@ -153,7 +403,7 @@ func executeTemplate(tmplStr string, data HookTemplateData) (string, error) {
 		return tmplStr, nil
 	}
 
-	tmpl, err := template.New("hook").Parse(tmplStr)
+	tmpl, err := template.New("hook").Funcs(templateFuncs).Parse(tmplStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -166,10 +416,45 @@ func executeTemplate(tmplStr string, data HookTemplateData) (string, error) {
 	return buf.String(), nil
 }
 
+// ShouldRun evaluates the hook's when expression against the template data. A hook without one
+// always runs; otherwise it runs only when the expression renders to true, and reason says why
+// it is skipped.
+func (h Hook) ShouldRun(templateData HookTemplateData) (shouldRun bool, reason string, err error) {
+	when := strings.TrimSpace(h.When)
+	if when == "" {
+		return true, "", nil
+	}
+
+	rendered, err := executeTemplate(when, templateData)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to evaluate when: %w", err)
+	}
+	rendered = strings.TrimSpace(rendered)
+	if rendered != "" {
+		shouldRun, err = strconv.ParseBool(rendered)
+		if err != nil {
+			return false, "", fmt.Errorf("when must render to true or false, got %q", rendered)
+		}
+	}
+	if !shouldRun {
+		return false, fmt.Sprintf("when %s is false", when), nil
+	}
+	return true, "", nil
+}
+
 // RenderHookCommand renders a hook's command and arguments with templates applied for display purposes.
 // This is used both for displaying the failover plan and during actual hook execution.
-// Returns the rendered command string (command + args joined with spaces).
+// Returns the rendered command string (command + args joined with spaces), or the method and URL
+// for http hooks.
 func RenderHookCommand(hook Hook, templateData HookTemplateData) (string, error) {
+	if hook.Type == HookTypeHTTP {
+		url, err := executeTemplate(hook.HTTP.URL, templateData)
+		if err != nil {
+			return "", fmt.Errorf("failed to execute url template: %w", err)
+		}
+		return hook.HTTP.method() + " " + url, nil
+	}
+
 	// Execute template for command
 	command, err := executeTemplate(hook.Command, templateData)
 	if err != nil {
@@ -193,33 +478,161 @@ func RenderHookCommand(hook Hook, templateData HookTemplateData) (string, error)
 	return command, nil
 }
 
-// Run runs the hook
-func (h Hook) Run(envMap map[string]string, hookType string, hookIndex int, totalHooks int) error {
+// Run runs the hook, retrying failed attempts up to h.Retries times. Each attempt is bounded by
+// h.TimeoutDuration (when set) and by ctx; on either, a command hook's whole process group is
+// killed. The returned Result holds the history of every attempt made. Outputs of earlier hooks
+// are available to templates, and the outputs of a successful run are added to outputs.
+func (h Hook) Run(ctx context.Context, envMap map[string]string, outputs *Outputs, hookType string, hookIndex int, totalHooks int) (result Result, err error) {
 	hookLogger := log.WithPrefix("hooks")
+	result.Name = h.Name
 
 	// Create template data from envMap
 	templateData := newHookTemplateData(envMap)
+	templateData.Outputs = outputs.Snapshot()
+
+	shouldRun, skipReason, err := h.ShouldRun(templateData)
+	if err != nil {
+		return result, fmt.Errorf("Hook %s %w", h.Name, err)
+	}
+	if !shouldRun {
+		result.Skipped = true
+		result.SkipReason = skipReason
+		hookLogger.Info(PrefixStyle.Render(fmt.Sprintf("hooks:%s:[%d/%d %s]:", hookType, hookIndex, totalHooks, h.outputName()))+" skipped", "reason", skipReason)
+		return result, nil
+	}
+
+	// render templates once up front - a broken template fails the hook without retrying
+	var runOnce func(ctx context.Context) error
+	switch h.Type {
+	case HookTypeHTTP:
+		req, err := h.HTTP.render(templateData)
+		if err != nil {
+			return result, fmt.Errorf("Hook %s %w", h.Name, err)
+		}
+		hookLogger.Debug("running hook",
+			"method", req.method,
+			"url", req.url,
+			"timeout", h.TimeoutDuration,
+			"retries", h.Retries,
+			"retry_backoff", h.RetryBackoffDuration,
+		)
+		runOnce = func(ctx context.Context) error {
+			return h.runHTTP(ctx, req, hookType, hookIndex, totalHooks)
+		}
+	default:
+		rendered, err := h.renderCommand(templateData, envMap)
+		if err != nil {
+			return result, err
+		}
+		rendered.stdin, err = h.stdinPayload(ctx, templateData)
+		if err != nil {
+			return result, fmt.Errorf("Hook %s %w", h.Name, err)
+		}
+		runOnce = func(ctx context.Context) (err error) {
+			result.Outputs, err = h.runCommand(ctx, rendered, hookType, hookIndex, totalHooks)
+			return err
+		}
+	}
+
+	maxAttempts := h.Retries + 1
+	for n := 1; n <= maxAttempts; n++ {
+		attempt := h.runAttempt(ctx, n, runOnce)
+		result.Attempts = append(result.Attempts, attempt)
+		if attempt.Err == nil {
+			hookLogger.Debugf("Hook %s completed successfully", h.Name)
+			outputs.set(h.Name, result.Outputs)
+			return result, nil
+		}
+		result.Outputs = nil
+
+		// cancelled from above (e.g. the failover is shutting down) - don't retry
+		if ctx.Err() != nil {
+			break
+		}
+
+		if n < maxAttempts {
+			hookLogger.Warn("hook attempt failed - retrying",
+				"hook", h.Name,
+				"attempt", fmt.Sprintf("%d/%d", n, maxAttempts),
+				"retry_in", h.RetryBackoffDuration,
+				"err", attempt.Err,
+			)
+			select {
+			case <-ctx.Done():
+			case <-time.After(h.RetryBackoffDuration):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	lastErr := result.Attempts[len(result.Attempts)-1].Err
+	if len(result.Attempts) > 1 {
+		return result, fmt.Errorf("Hook %s failed after %d attempts: %w", h.Name, len(result.Attempts), lastErr)
+	}
+	return result, fmt.Errorf("Hook %s failed: %w", h.Name, lastErr)
+}
+
+// runAttempt makes a single attempt at running the hook, bounded by h.TimeoutDuration, and records it
+func (h Hook) runAttempt(ctx context.Context, n int, runOnce func(ctx context.Context) error) (attempt Attempt) {
+	attempt.Number = n
+	attempt.StartTime = time.Now()
+	defer func() {
+		attempt.Duration = time.Since(attempt.StartTime)
+	}()
+
+	attemptCtx := ctx
+	if h.TimeoutDuration > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, h.TimeoutDuration)
+		defer cancel()
+	}
+
+	err := runOnce(attemptCtx)
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		attempt.Err = fmt.Errorf("cancelled: %w", ctx.Err())
+	case errors.Is(attemptCtx.Err(), context.DeadlineExceeded):
+		attempt.TimedOut = true
+		attempt.Err = fmt.Errorf("timed out after %s", h.TimeoutDuration)
+	default:
+		attempt.Err = err
+	}
+	return
+}
+
+// renderedCommand is a command hook with its templates applied
+type renderedCommand struct {
+	command      string
+	args         []string
+	env          []string
+	inheritedEnv []string // inherited from this process, kept out of debug logs
+	stdin        []byte
+}
+
+// renderCommand applies templates to the hook's command, args and environment
+func (h Hook) renderCommand(templateData HookTemplateData, envMap map[string]string) (rendered renderedCommand, err error) {
+	hookLogger := log.WithPrefix("hooks")
 
 	// Execute templates for command and args
 	// Note: We keep this separate from RenderHookCommand to properly handle
 	// arguments with spaces for exec.Command
-	command, err := executeTemplate(h.Command, templateData)
+	rendered.command, err = executeTemplate(h.Command, templateData)
 	if err != nil {
-		return fmt.Errorf("Hook %s failed to execute command template: %w", h.Name, err)
+		return rendered, fmt.Errorf("Hook %s failed to execute command template: %w", h.Name, err)
 	}
 
-	args := make([]string, len(h.Args))
+	rendered.args = make([]string, len(h.Args))
 	for i, arg := range h.Args {
 		executedArg, err := executeTemplate(arg, templateData)
 		if err != nil {
-			return fmt.Errorf("Hook %s failed to execute arg[%d] template: %w", h.Name, i, err)
+			return rendered, fmt.Errorf("Hook %s failed to execute arg[%d] template: %w", h.Name, i, err)
 		}
-		args[i] = executedArg
+		rendered.args[i] = executedArg
 	}
 
-	// run the command passing in custom env variables about the state using os.exec
-	cmd := exec.Command(command, args...)
-
 	// Build environment variables as a map first
 	envVars := make(map[string]string)
 
@@ -230,7 +643,7 @@ func (h Hook) Run(envMap map[string]string, hookType string, hookIndex int, tota
 			// Execute template for environment variable value
 			executedValue, err := executeTemplate(envValue, templateData)
 			if err != nil {
-				return fmt.Errorf("Hook %s failed to execute environment variable %s template: %w", h.Name, envKey, err)
+				return rendered, fmt.Errorf("Hook %s failed to execute environment variable %s template: %w", h.Name, envKey, err)
 			}
 			// Trim newlines and whitespace from the value
 			cleanValue := strings.TrimSpace(executedValue)
@@ -245,32 +658,87 @@ func (h Hook) Run(envMap map[string]string, hookType string, hookIndex int, tota
 		envVars[fmt.Sprintf("SOLANA_VALIDATOR_FAILOVER_%s", k)] = cleanValue
 	}
 
-	// Append all environment variables to cmd.Env, ensuring all keys are uppercase
+	// Build the command environment, ensuring all keys are uppercase
 	for envKey, envValue := range utils.SortStringMap(envVars) {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", strings.ToUpper(envKey), envValue))
+		rendered.env = append(rendered.env, fmt.Sprintf("%s=%s", strings.ToUpper(envKey), envValue))
+	}
+
+	// inherited variables come first and never override the ones above
+	inherited := h.inheritEnv.environ()
+	for _, envKey := range slices.Sorted(maps.Keys(inherited)) {
+		if !slices.ContainsFunc(rendered.env, func(kv string) bool { return strings.HasPrefix(kv, strings.ToUpper(envKey)+"=") }) {
+			rendered.inheritedEnv = append(rendered.inheritedEnv, fmt.Sprintf("%s=%s", envKey, inherited[envKey]))
+		}
 	}
 
 	hookLogger.Debug("running hook",
 		"command_template", h.Command,
-		"command_executed", command,
+		"command_executed", rendered.command,
 		"args_template", fmt.Sprintf("[%s]", strings.Join(h.Args, ", ")),
-		"args_executed", fmt.Sprintf("[%s]", strings.Join(args, ", ")),
-		"env", fmt.Sprintf("[%s]", strings.Join(cmd.Env, ", ")),
+		"args_executed", fmt.Sprintf("[%s]", strings.Join(rendered.args, ", ")),
+		"env", fmt.Sprintf("[%s]", strings.Join(rendered.env, ", ")),
+		"inherited_env_count", len(rendered.inheritedEnv),
+		"timeout", h.TimeoutDuration,
+		"retries", h.Retries,
+		"retry_backoff", h.RetryBackoffDuration,
 	)
 
-	// Capture stdout and stderr separately
-	stdout, err := cmd.StdoutPipe()
+	return rendered, nil
+}
+
+// runCommand runs the rendered hook command once, streaming its output and returning the outputs
+// it set with ::set-output lines or in its output file
+func (h Hook) runCommand(ctx context.Context, rendered renderedCommand, hookType string, hookIndex int, totalHooks int) (outputs map[string]string, err error) {
+	hookLogger := log.WithPrefix("hooks")
+
+	// give every attempt a fresh output file
+	outputFile, err := os.CreateTemp("", "solana-validator-failover-output-*.json")
 	if err != nil {
-		return fmt.Errorf("Hook %s failed to create stdout pipe: %v", h.Name, err)
+		return nil, fmt.Errorf("failed to create output file: %v", err)
 	}
-	stderr, err := cmd.StderrPipe()
+	utils.SafeCloseFile(outputFile)
+	defer os.Remove(outputFile.Name())
+
+	// resolve the command the way Validate did - in the hook's own PATH, as the hook's user
+	env := slices.Concat(rendered.env, rendered.inheritedEnv)
+	resolved, err := h.runAs.resolveCommand(rendered.command, commandPath(env))
 	if err != nil {
-		return fmt.Errorf("Hook %s failed to create stderr pipe: %v", h.Name, err)
+		return nil, fmt.Errorf("command %s is not runnable: %w", rendered.command, err)
 	}
 
-	hookLogger.Debug("running hook", "command", command, "args", fmt.Sprintf("[%s]", strings.Join(args, ", ")), "name", h.Name)
+	// run the command in its own process group so a timeout kills anything it spawned too
+	command, args := h.runAs.command(resolved, rendered.args)
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = h.runAs.workingDir
+	cmd.Env = slices.Concat(rendered.inheritedEnv, rendered.env, []string{OutputFileEnvVar + "=" + outputFile.Name()})
+	// the failover context as JSON - hooks that don't read it are unaffected
+	cmd.Stdin = bytes.NewReader(rendered.stdin)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if credential := h.runAs.credential; credential != nil {
+		cmd.SysProcAttr.Credential = credential
+		// the hook must be able to write its output file
+		if err := os.Chown(outputFile.Name(), int(credential.Uid), int(credential.Gid)); err != nil {
+			return nil, fmt.Errorf("failed to chown output file: %v", err)
+		}
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = hookWaitDelay
+
+	// Capture stdout and stderr separately. Writing through io.Pipe (rather than cmd.StdoutPipe)
+	// makes cmd.Wait wait until all output has been read, bounded by WaitDelay, so no lines -
+	// and no ::set-output lines - are lost when the command exits.
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	defer stdoutWriter.Close()
+	defer stderrWriter.Close()
+
+	hookLogger.Debug("running hook", "command", rendered.command, "args", fmt.Sprintf("[%s]", strings.Join(rendered.args, ", ")), "name", h.Name)
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("Hook %s failed to start: %v", h.Name, err)
+		return nil, fmt.Errorf("failed to start: %v", err)
 	}
 
 	// get the command pid (only after successful start)
@@ -284,16 +752,21 @@ func (h Hook) Run(envMap map[string]string, hookType string, hookIndex int, tota
 	// Stream stdout and stderr in real-time.
 	// Use the base logger (no prefix) since styledStreamOutputString already
 	// embeds the full "hooks:<type>:[N/N name]:" prefix in the message.
+	lineOutputs := map[string]string{}
 	go func() {
 		defer wg.Done()
 		scanner := bufio.NewScanner(stdout)
 		for scanner.Scan() {
 			line := strings.TrimSpace(scanner.Text())
+			if key, value, ok := parseSetOutputLine(line); ok {
+				lineOutputs[key] = value
+			}
 			if line != "" {
-				styledOutput := styledStreamOutputString("stdout", line, h.Name, hookType, hookIndex, totalHooks)
+				styledOutput := styledStreamOutputString("stdout", line, h.outputName(), hookType, hookIndex, totalHooks)
 				log.Info(styledOutput)
 			}
 		}
+		_, _ = io.Copy(io.Discard, stdout) // keep draining if a line was too long to scan
 	}()
 
 	go func() {
@@ -302,24 +775,32 @@ func (h Hook) Run(envMap map[string]string, hookType string, hookIndex int, tota
 		for scanner.Scan() {
 			line := strings.TrimSpace(scanner.Text())
 			if line != "" {
-				styledOutput := styledStreamOutputString("stderr", line, h.Name, hookType, hookIndex, totalHooks)
+				styledOutput := styledStreamOutputString("stderr", line, h.outputName(), hookType, hookIndex, totalHooks)
 				log.Info(styledOutput)
 			}
 		}
+		_, _ = io.Copy(io.Discard, stderr)
 	}()
 
-	// Wait for the command to complete
+	// Wait for the command to complete and its output to be copied, then end the streams
 	err = cmd.Wait()
+	stdoutWriter.Close()
+	stderrWriter.Close()
 
 	// Wait for streaming goroutines to finish
 	wg.Wait()
 
 	if err != nil {
-		return fmt.Errorf("Hook %s failed: %v", h.Name, err)
+		return nil, err
 	}
 
-	hookLogger.Debugf("Hook %s completed successfully", h.Name)
-	return nil
+	// the output file wins over ::set-output lines for the same key
+	fileOutputs, err := readOutputFile(outputFile.Name())
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(lineOutputs, fileOutputs)
+	return lineOutputs, nil
 }
 
 // Define styles using lipgloss - matching the reference repository colors
@@ -349,50 +830,57 @@ func styledStreamOutputString(stream string, text string, hookName string, hookT
 }
 
 // RunPreWhenPassive runs the pre hooks when the validator is passive
-func (h FailoverHooks) RunPreWhenPassive(envMap map[string]string) error {
-	for i, hook := range h.Pre.WhenPassive {
-		err := hook.Run(envMap, "pre", i+1, len(h.Pre.WhenPassive))
-		if err != nil && hook.MustSucceed {
-			return err
-		}
-		if err != nil {
-			log.Error("pre hook failed - must_succeed is false, continuing...", "hook", hook.Name, "err", err)
-		}
-	}
-	return nil
+func (h FailoverHooks) RunPreWhenPassive(ctx context.Context, envMap map[string]string) error {
+	return runPreHooks(ctx, h.Pre.WhenPassive, envMap, h.Outputs)
 }
 
 // RunPreWhenActive runs the pre hooks when the validator is active
-func (h FailoverHooks) RunPreWhenActive(envMap map[string]string) error {
-	for i, hook := range h.Pre.WhenActive {
-		err := hook.Run(envMap, "pre", i+1, len(h.Pre.WhenActive))
-		if err != nil && hook.MustSucceed {
-			return err
-		}
-		if err != nil {
-			log.Error("pre hook failed - must_succeed is false, continuing...", "hook", hook.Name, "err", err)
-			continue
-		}
-	}
-	return nil
+func (h FailoverHooks) RunPreWhenActive(ctx context.Context, envMap map[string]string) error {
+	return runPreHooks(ctx, h.Pre.WhenActive, envMap, h.Outputs)
 }
 
 // RunPostWhenPassive runs the post hooks when the validator is passive
-func (h FailoverHooks) RunPostWhenPassive(envMap map[string]string) {
-	for i, hook := range h.Post.WhenPassive {
-		err := hook.Run(envMap, "post", i+1, len(h.Post.WhenPassive))
-		if err != nil {
-			log.Error("post hook failed", "hook", hook.Name, "err", err)
+func (h FailoverHooks) RunPostWhenPassive(ctx context.Context, envMap map[string]string) {
+	runPostHooks(ctx, h.Post.WhenPassive, envMap, h.Outputs, PhasePost)
+}
+
+// RunPostWhenActive runs the post hooks when the validator is active
+func (h FailoverHooks) RunPostWhenActive(ctx context.Context, envMap map[string]string) {
+	runPostHooks(ctx, h.Post.WhenActive, envMap, h.Outputs, PhasePost)
+}
+
+// runPreHooks runs pre hooks in order (parallel groups concurrently), returning the first error
+// from a must_succeed hook. Hooks after the stage with a failed must_succeed hook are not run.
+func runPreHooks(ctx context.Context, hs Hooks, envMap map[string]string, outputs *Outputs) error {
+	for _, stage := range hs.stages() {
+		var abortErr error
+		for _, r := range runStage(ctx, hs, stage, envMap, outputs, PhasePre) {
+			if r.err != nil && r.hook.MustSucceed {
+				log.Error("pre hook failed - must_succeed is true, aborting...", "hook", r.hook.Name, "attempts", r.result.String())
+				if abortErr == nil {
+					abortErr = r.err
+				}
+				continue
+			}
+			if r.err != nil {
+				log.Error("pre hook failed - must_succeed is false, continuing...", "hook", r.hook.Name, "err", r.err, "attempts", r.result.String())
+			}
+		}
+		if abortErr != nil {
+			return abortErr
 		}
 	}
+	return nil
 }
 
-// RunPostWhenActive runs the post hooks when the validator is active
-func (h FailoverHooks) RunPostWhenActive(envMap map[string]string) {
-	for i, hook := range h.Post.WhenActive {
-		err := hook.Run(envMap, "post", i+1, len(h.Post.WhenActive))
-		if err != nil {
-			log.Error("post hook failed", "hook", hook.Name, "err", err)
+// runPostHooks runs post (or other non-blocking) hooks in order (parallel groups concurrently),
+// logging any failures
+func runPostHooks(ctx context.Context, hs Hooks, envMap map[string]string, outputs *Outputs, hookType string) {
+	for _, stage := range hs.stages() {
+		for _, r := range runStage(ctx, hs, stage, envMap, outputs, hookType) {
+			if r.err != nil {
+				log.Error(hookType+" hook failed", "hook", r.hook.Name, "err", r.err, "attempts", r.result.String())
+			}
 		}
 	}
 }
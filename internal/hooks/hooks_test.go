@@ -0,0 +1,209 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookConfigure(t *testing.T) {
+	h := Hook{Name: "x", Command: "true", Timeout: "1500ms", RetryBackoff: "1s", Retries: 1}
+	require.NoError(t, h.Configure())
+	assert.Equal(t, 1500*time.Millisecond, h.TimeoutDuration)
+	assert.Equal(t, time.Second, h.RetryBackoffDuration)
+
+	for _, bad := range []Hook{
+		{Name: "no-command"},
+		{Name: "bad-timeout", Command: "true", Timeout: "nope"},
+		{Name: "zero-timeout", Command: "true", Timeout: "0s"},
+		{Name: "bad-backoff", Command: "true", RetryBackoff: "nope"},
+		{Name: "negative-retries", Command: "true", Retries: -1},
+	} {
+		assert.Error(t, bad.Configure(), bad.Name)
+	}
+}
+
+func TestHookRun_Success(t *testing.T) {
+	h := Hook{Name: "ok", Command: "true"}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Succeeded())
+	assert.Len(t, result.Attempts, 1)
+}
+
+func TestHookRun_TimeoutKillsProcessGroup(t *testing.T) {
+	// the child sleep would keep the output pipe open if only the shell were killed
+	h := Hook{Name: "hung", Command: "sh", Args: []string{"-c", "sleep 30 & sleep 30"}, Timeout: "200ms"}
+	require.NoError(t, h.Configure())
+
+	start := time.Now()
+	result, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	require.Len(t, result.Attempts, 1)
+	assert.True(t, result.Attempts[0].TimedOut)
+	assert.Contains(t, err.Error(), "timed out after 200ms")
+}
+
+func TestHookRun_RetriesUntilSuccess(t *testing.T) {
+	// fails on the first attempt, succeeds on the second
+	marker := filepath.Join(t.TempDir(), "marker")
+	h := Hook{
+		Name:         "flaky",
+		Command:      "sh",
+		Args:         []string{"-c", "test -f " + marker + " || { touch " + marker + "; exit 1; }"},
+		Retries:      2,
+		RetryBackoff: "10ms",
+	}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, result.Attempts, 2)
+	assert.Error(t, result.Attempts[0].Err)
+	assert.NoError(t, result.Attempts[1].Err)
+}
+
+func TestHookRun_RetriesExhausted(t *testing.T) {
+	h := Hook{Name: "broken", Command: "false", Retries: 2}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), nil, nil, "post", 1, 1)
+	require.Error(t, err)
+	assert.Len(t, result.Attempts, 3)
+	assert.False(t, result.Succeeded())
+	assert.Contains(t, err.Error(), "failed after 3 attempts")
+}
+
+func TestHookRun_CancelledStopsRetrying(t *testing.T) {
+	h := Hook{Name: "hung", Command: "sleep", Args: []string{"30"}, Retries: 5}
+	require.NoError(t, h.Configure())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	result, err := h.Run(ctx, nil, nil, "pre", 1, 1)
+	require.Error(t, err)
+	assert.Len(t, result.Attempts, 1)
+	assert.False(t, result.Attempts[0].TimedOut)
+	assert.Contains(t, err.Error(), "cancelled")
+}
+
+func TestRunPreWhenActive_MustSucceedTimeoutAborts(t *testing.T) {
+	h := FailoverHooks{Pre: PreHooks{WhenActive: Hooks{
+		{Name: "hung", Command: "sleep", Args: []string{"30"}, Timeout: "100ms", MustSucceed: true},
+		{Name: "never-runs", Command: "false", MustSucceed: true},
+	}}}
+	require.NoError(t, h.Configure())
+
+	err := h.RunPreWhenActive(context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Hook hung failed")
+}
+
+func TestRunPhase_RunsHooksForRole(t *testing.T) {
+	dir := t.TempDir()
+	activeOut := filepath.Join(dir, "active")
+	passiveOut := filepath.Join(dir, "passive")
+	h := FailoverHooks{
+		AfterSetIdentity: PhaseHooks{
+			WhenActive:  Hooks{{Name: "a", Command: "sh", Args: []string{"-c", `echo "$SOLANA_VALIDATOR_FAILOVER_PHASE $SOLANA_VALIDATOR_FAILOVER_ERROR" > ` + activeOut}}},
+			WhenPassive: Hooks{{Name: "p", Command: "touch", Args: []string{passiveOut}}},
+		},
+	}
+	require.NoError(t, h.Configure())
+
+	h.RunPhase(context.Background(), PhaseAfterSetIdentity, "active", map[string]string{"PHASE": PhaseAfterSetIdentity, "ERROR": "boom"}, nil)
+
+	out, err := os.ReadFile(activeOut)
+	require.NoError(t, err)
+	assert.Equal(t, "after_set_identity boom\n", string(out))
+	assert.NoFileExists(t, passiveOut)
+	assert.Equal(t, 2, h.Phase(PhaseAfterSetIdentity).Len())
+	assert.Equal(t, 0, h.Phase(PhasePost).Len())
+}
+
+func TestRunPhase_BackgroundHooksDontBlock(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	h := FailoverHooks{
+		BeforeSetIdentity: PhaseHooks{WhenActive: Hooks{
+			{Name: "slow", Command: "sh", Args: []string{"-c", "sleep 0.3; echo slow >> " + out}, Background: true},
+			{Name: "drain", Command: "sh", Args: []string{"-c", "echo drain >> " + out}},
+		}},
+		AfterSetIdentity: PhaseHooks{WhenPassive: Hooks{{Name: "after", Command: "sh", Args: []string{"-c", "echo after_set_identity >> " + out}}}},
+	}
+	require.NoError(t, h.Configure())
+
+	var queue PhaseQueue
+	start := time.Now()
+	h.RunPhase(context.Background(), PhaseBeforeSetIdentity, "active", nil, &queue)
+	assert.Less(t, time.Since(start), 200*time.Millisecond, "background hooks should not block")
+
+	// foreground hooks have finished by the time RunPhase returns
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "drain\n", string(got))
+
+	h.RunPhase(context.Background(), PhaseAfterSetIdentity, "passive", nil, &queue)
+	queue.Wait()
+	got, err = os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "drain\nafter_set_identity\nslow\n", string(got))
+}
+
+func TestFailoverHooksConfigure_PhaseHookErrorNamesPhase(t *testing.T) {
+	h := FailoverHooks{OnFailure: PhaseHooks{WhenPassive: Hooks{{Name: "bad"}}}}
+	assert.ErrorContains(t, h.Configure(), "on_failure.when_passive")
+}
+
+func TestHookShouldRun(t *testing.T) {
+	data := HookTemplateData{IsDryRunFailover: true, PeerNodeName: "chicago", Cluster: "testnet"}
+	for _, tc := range []struct {
+		when      string
+		shouldRun bool
+		err       bool
+	}{
+		{"", true, false},
+		{"{{ .IsDryRunFailover }}", true, false},
+		{"{{ not .IsDryRunFailover }}", false, false},
+		{`{{ eq .Cluster "mainnet-beta" }}`, false, false},
+		{`{{ if eq .PeerNodeName "chicago" }}true{{ end }}`, true, false},
+		{`{{ if eq .PeerNodeName "london" }}true{{ end }}`, false, false},
+		{"false", false, false},
+		{"{{ .PeerNodeName }}", false, true},
+	} {
+		shouldRun, reason, err := Hook{When: tc.when}.ShouldRun(data)
+		if tc.err {
+			assert.Error(t, err, tc.when)
+			continue
+		}
+		require.NoError(t, err, tc.when)
+		assert.Equal(t, tc.shouldRun, shouldRun, tc.when)
+		if !shouldRun {
+			assert.Equal(t, "when "+tc.when+" is false", reason)
+		}
+	}
+}
+
+func TestHookRun_SkippedByWhen(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+	h := Hook{Name: "real-only", Command: "touch", Args: []string{marker}, When: "{{ not .IsDryRunFailover }}", MustSucceed: true}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), map[string]string{"IS_DRY_RUN_FAILOVER": "true"}, nil, "pre", 1, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Empty(t, result.Attempts)
+	assert.Equal(t, "skipped: when {{ not .IsDryRunFailover }} is false", result.String())
+	assert.NoFileExists(t, marker)
+
+	bad := Hook{Name: "bad", Command: "true", When: "{{ .Nope"}
+	assert.ErrorContains(t, bad.Configure(), "invalid when")
+}
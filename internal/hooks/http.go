@@ -0,0 +1,180 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
+)
+
+// httpResponseBodyLimit caps how much of a response body is read and logged
+const httpResponseBodyLimit = 64 * 1024
+
+// HTTPConfig is the request sent by an http hook. URL, header values and body support
+// template interpolation with HookTemplateData.
+type HTTPConfig struct {
+	// Method is the HTTP method. Default: POST
+	Method string `mapstructure:"method"`
+	// URL is the request URL (required)
+	URL string `mapstructure:"url"`
+	// Headers are extra request headers. Content-Type defaults to application/json when a body is set.
+	Headers map[string]string `mapstructure:"headers"`
+	// Body is a JSON request body
+	Body string `mapstructure:"body"`
+	// ExpectedStatus lists the status codes that count as success. Default: any 2xx
+	ExpectedStatus []int `mapstructure:"expected_status"`
+	// TLS configures how the server certificate is verified and an optional client certificate
+	TLS HTTPTLSConfig `mapstructure:"tls"`
+
+	// client is built by Configure (not read from YAML)
+	client *http.Client
+}
+
+// HTTPTLSConfig holds the optional TLS settings for an http hook
+type HTTPTLSConfig struct {
+	CACert             string `mapstructure:"ca_cert"`              // path to a CA certificate to verify the server with
+	Cert               string `mapstructure:"cert"`                 // path to a client certificate
+	Key                string `mapstructure:"key"`                  // path to the client certificate's private key
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // don't verify the server certificate
+}
+
+// renderedHTTPRequest is an http hook request with its templates applied
+type renderedHTTPRequest struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    string
+}
+
+// configure validates the request config and builds its client
+func (c *HTTPConfig) configure() error {
+	if c.URL == "" {
+		return fmt.Errorf("http.url is required")
+	}
+	for _, code := range c.ExpectedStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("http.expected_status: invalid status code %d", code)
+		}
+	}
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		return fmt.Errorf("http.tls: cert and key must be set together")
+	}
+
+	tlsConfig, err := utils.BuildTLSClientConfig(c.TLS.CACert, c.TLS.Cert, c.TLS.Key)
+	if err != nil {
+		return fmt.Errorf("http.tls: %w", err)
+	}
+	tlsConfig.InsecureSkipVerify = c.TLS.InsecureSkipVerify
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c.client = &http.Client{Transport: transport}
+	return nil
+}
+
+// method returns the configured method, defaulting to POST
+func (c HTTPConfig) method() string {
+	if c.Method == "" {
+		return http.MethodPost
+	}
+	return strings.ToUpper(c.Method)
+}
+
+// isExpectedStatus returns true if code counts as a successful response
+func (c HTTPConfig) isExpectedStatus(code int) bool {
+	if len(c.ExpectedStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(c.ExpectedStatus, code)
+}
+
+// render applies templates to the URL, header values and body
+func (c HTTPConfig) render(templateData HookTemplateData) (req renderedHTTPRequest, err error) {
+	req.method = c.method()
+
+	req.url, err = executeTemplate(c.URL, templateData)
+	if err != nil {
+		return req, fmt.Errorf("failed to execute url template: %w", err)
+	}
+
+	req.headers = make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		executedValue, err := executeTemplate(v, templateData)
+		if err != nil {
+			return req, fmt.Errorf("failed to execute header %s template: %w", k, err)
+		}
+		req.headers[k] = strings.TrimSpace(executedValue)
+	}
+
+	req.body, err = executeTemplate(c.Body, templateData)
+	if err != nil {
+		return req, fmt.Errorf("failed to execute body template: %w", err)
+	}
+	if req.body != "" && !json.Valid([]byte(req.body)) {
+		return req, fmt.Errorf("rendered body is not valid JSON: %s", req.body)
+	}
+
+	return req, nil
+}
+
+// runHTTP sends the rendered request once, logging the response status and body
+func (h Hook) runHTTP(ctx context.Context, rendered renderedHTTPRequest, hookType string, hookIndex int, totalHooks int) error {
+	var body io.Reader
+	if rendered.body != "" {
+		body = strings.NewReader(rendered.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rendered.method, rendered.url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if rendered.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range rendered.headers {
+		req.Header.Set(k, v)
+	}
+
+	client := h.HTTP.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, httpResponseBodyLimit))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	ok := h.HTTP.isExpectedStatus(resp.StatusCode)
+	stream := "stdout"
+	if !ok {
+		stream = "stderr"
+	}
+	log.Info(styledStreamOutputString(stream, fmt.Sprintf("%s %s -> %s", rendered.method, rendered.url, resp.Status), h.outputName(), hookType, hookIndex, totalHooks))
+	scanner := bufio.NewScanner(bytes.NewReader(respBody))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			log.Info(styledStreamOutputString(stream, line, h.outputName(), hookType, hookIndex, totalHooks))
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package hooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHook_Configure(t *testing.T) {
+	h := Hook{Name: "no-url", Type: HookTypeHTTP}
+	assert.ErrorContains(t, h.Configure(), "http.url is required")
+
+	h = Hook{Name: "bad-status", Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "http://x", ExpectedStatus: []int{42}}}
+	assert.ErrorContains(t, h.Configure(), "invalid status code 42")
+
+	h = Hook{Name: "half-tls", Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "http://x", TLS: HTTPTLSConfig{Cert: "c.crt"}}}
+	assert.ErrorContains(t, h.Configure(), "cert and key must be set together")
+
+	h = Hook{Name: "bad-type", Type: "smoke-signal"}
+	assert.ErrorContains(t, h.Configure(), `unknown type "smoke-signal"`)
+}
+
+func TestHTTPHook_SendsTemplatedRequest(t *testing.T) {
+	var gotMethod, gotBody, gotAuth, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotAuth = r.Header.Get("Authorization")
+		gotContentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	h := Hook{
+		Name: "notify",
+		Type: HookTypeHTTP,
+		HTTP: HTTPConfig{
+			URL:            server.URL + "/nodes/{{ .ThisNodeName }}",
+			Headers:        map[string]string{"Authorization": "Bearer {{ .ThisNodeRole }}"},
+			Body:           `{"node":"{{ .ThisNodeName }}","peer":"{{ .PeerNodeName }}"}`,
+			ExpectedStatus: []int{http.StatusAccepted},
+		},
+	}
+	require.NoError(t, h.Configure())
+
+	_, err := h.Run(context.Background(), map[string]string{
+		"THIS_NODE_NAME": "london",
+		"THIS_NODE_ROLE": "active",
+		"PEER_NODE_NAME": "chicago",
+	}, nil, "pre", 1, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "Bearer active", gotAuth)
+	assert.Equal(t, "application/json", gotContentType)
+	assert.JSONEq(t, `{"node":"london","peer":"chicago"}`, gotBody)
+}
+
+func TestHTTPHook_UnexpectedStatusRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	h := Hook{Name: "lb", Type: HookTypeHTTP, Retries: 1, HTTP: HTTPConfig{Method: "put", URL: server.URL}}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+
+	require.NoError(t, err)
+	require.Len(t, result.Attempts, 2)
+	assert.ErrorContains(t, result.Attempts[0].Err, "unexpected status 503")
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestHTTPHook_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	h := Hook{Name: "slow", Type: HookTypeHTTP, Timeout: "100ms", MustSucceed: true, HTTP: HTTPConfig{URL: server.URL}}
+	require.NoError(t, h.Configure())
+
+	err := FailoverHooks{Pre: PreHooks{WhenPassive: Hooks{h}}}.RunPreWhenPassive(context.Background(), nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+}
+
+func TestHTTPHook_InvalidJSONBody(t *testing.T) {
+	h := Hook{Name: "broken", Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "http://127.0.0.1:1", Body: `{"node": {{ .ThisNodeName }}}`}}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), map[string]string{"THIS_NODE_NAME": "london"}, nil, "post", 1, 1)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid JSON")
+	assert.Empty(t, result.Attempts)
+}
+
+func TestHTTPConfigRender_JSONQuotesValues(t *testing.T) {
+	c := HTTPConfig{URL: "http://127.0.0.1:1", Body: `{"node": {{ json .ThisNodeName }}, "error": {{ json .Error }}}`}
+	require.NoError(t, c.configure())
+
+	req, err := c.render(HookTemplateData{ThisNodeName: "london", Error: "set-identity failed: \"exit 1\"\nC:\\tmp"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"node": "london", "error": "set-identity failed: \"exit 1\"\nC:\\tmp"}`, req.body)
+}
+
+func TestRenderHookCommand_HTTP(t *testing.T) {
+	h := Hook{Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "https://lb/{{ .ThisNodeName }}"}}
+
+	rendered, err := RenderHookCommand(h, HookTemplateData{ThisNodeName: "london"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "POST https://lb/london", rendered)
+}
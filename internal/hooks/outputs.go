@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// setOutputPrefix marks a stdout line as an output, e.g. "::set-output ticket=OPS-123"
+	setOutputPrefix = "::set-output "
+	// OutputFileEnvVar names the JSON file a command hook can write its outputs to, as an object
+	// of key/value pairs
+	OutputFileEnvVar = "SOLANA_VALIDATOR_FAILOVER_OUTPUT"
+)
+
+// Outputs holds the key/value outputs of hooks that have run on this node during a failover,
+// keyed by hook name. It is safe for concurrent use, and a nil *Outputs records nothing.
+type Outputs struct {
+	mu     sync.RWMutex
+	values map[string]map[string]string
+}
+
+// NewOutputs returns an empty output store
+func NewOutputs() *Outputs {
+	return &Outputs{values: map[string]map[string]string{}}
+}
+
+// set records the outputs of a hook, replacing any it set before
+func (o *Outputs) set(hookName string, values map[string]string) {
+	if o == nil || len(values) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.values[hookName] = maps.Clone(values)
+}
+
+// Snapshot returns a copy of every hook's outputs
+func (o *Outputs) Snapshot() map[string]map[string]string {
+	snapshot := map[string]map[string]string{}
+	if o == nil {
+		return snapshot
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for hookName, values := range o.values {
+		snapshot[hookName] = maps.Clone(values)
+	}
+	return snapshot
+}
+
+// parseSetOutputLine returns the key and value of a "::set-output key=value" stdout line
+func parseSetOutputLine(line string) (key, value string, ok bool) {
+	rest, found := strings.CutPrefix(line, setOutputPrefix)
+	if !found {
+		return "", "", false
+	}
+	key, value, found = strings.Cut(rest, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// readOutputFile reads the JSON object a hook wrote to its output file. An empty or missing file
+// means no outputs. Non-string values are kept in their JSON form.
+func readOutputFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(strings.TrimSpace(string(data))) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output file: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("output file must contain a JSON object: %w", err)
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			values[k] = s
+		} else {
+			values[k] = string(v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSetOutputLine(t *testing.T) {
+	key, value, ok := parseSetOutputLine("::set-output ticket=OPS-1=2")
+	require.True(t, ok)
+	assert.Equal(t, "ticket", key)
+	assert.Equal(t, "OPS-1=2", value)
+
+	for _, line := range []string{"ticket=OPS-1", "::set-output ticket", "::set-output =x"} {
+		_, _, ok := parseSetOutputLine(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestRunHooks_OutputsPassToLaterHooks(t *testing.T) {
+	got := filepath.Join(t.TempDir(), "got")
+	hs := Hooks{
+		{Name: "open-ticket", Command: "sh", Args: []string{"-c", `echo "::set-output ticket=OPS-1"; echo "::set-output queue=ops"`}},
+		{Name: "lb", Command: "sh", Args: []string{"-c", `echo '{"backend": "pool-2", "drained": 3}' > "$SOLANA_VALIDATOR_FAILOVER_OUTPUT"`}},
+		{Name: "close-ticket", Command: "sh", Args: []string{"-c", `echo "$TICKET $BACKEND" > ` + got}, Environment: map[string]string{
+			"TICKET":  `{{ index .Outputs "open-ticket" "ticket" }}`,
+			"BACKEND": `{{ .Outputs.lb.backend }}`,
+		}},
+	}
+	require.NoError(t, hs.Configure())
+
+	outputs := NewOutputs()
+	require.NoError(t, runPreHooks(context.Background(), hs, nil, outputs))
+
+	out, err := os.ReadFile(got)
+	require.NoError(t, err)
+	assert.Equal(t, "OPS-1 pool-2\n", string(out))
+	assert.Equal(t, map[string]map[string]string{
+		"open-ticket": {"ticket": "OPS-1", "queue": "ops"},
+		"lb":          {"backend": "pool-2", "drained": "3"},
+	}, outputs.Snapshot())
+}
+
+func TestRunHook_FailedAttemptOutputsDiscarded(t *testing.T) {
+	h := Hook{Name: "fails", Command: "sh", Args: []string{"-c", `echo "::set-output ticket=OPS-1"; exit 1`}}
+	require.NoError(t, h.Configure())
+
+	outputs := NewOutputs()
+	result, err := h.Run(context.Background(), nil, outputs, "pre", 1, 1)
+	require.Error(t, err)
+	assert.Empty(t, result.Outputs)
+	assert.Empty(t, outputs.Snapshot())
+}
+
+func TestRunHook_InvalidOutputFileFails(t *testing.T) {
+	h := Hook{Name: "bad-json", Command: "sh", Args: []string{"-c", `echo 'nope' > "$SOLANA_VALIDATOR_FAILOVER_OUTPUT"`}}
+	require.NoError(t, h.Configure())
+
+	_, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	assert.ErrorContains(t, err, "output file must contain a JSON object")
+}
@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultParallelGroup is the group of hooks with parallel: true and no group set
+const defaultParallelGroup = "parallel"
+
+// ParallelGroup returns the group the hook runs concurrently with, or empty if it runs on its own
+func (h Hook) ParallelGroup() string {
+	if h.Group != "" {
+		return h.Group
+	}
+	if h.Parallel {
+		return defaultParallelGroup
+	}
+	return ""
+}
+
+// outputName is the hook name used to prefix its output - hooks in a parallel group include the
+// group so interleaved lines from concurrent hooks stay attributable
+func (h Hook) outputName() string {
+	if group := h.ParallelGroup(); group != "" {
+		return group + "/" + h.Name
+	}
+	return h.Name
+}
+
+// stages splits the hooks into the batches they run in, as indexes into hs. Consecutive hooks in
+// the same parallel group form one batch that runs concurrently; every other hook is a batch of
+// its own. Each batch finishes before the next one starts.
+func (hs Hooks) stages() (stages [][]int) {
+	for i, hook := range hs {
+		group := hook.ParallelGroup()
+		if group != "" && len(stages) > 0 {
+			last := stages[len(stages)-1]
+			if hs[last[0]].ParallelGroup() == group {
+				stages[len(stages)-1] = append(last, i)
+				continue
+			}
+		}
+		stages = append(stages, []int{i})
+	}
+	return stages
+}
+
+// stageResult is the outcome of one hook in a stage
+type stageResult struct {
+	hook   Hook
+	result Result
+	err    error
+}
+
+// runStage runs the hooks at the given indexes concurrently and waits for all of them, returning
+// their results in config order
+func runStage(ctx context.Context, hs Hooks, stage []int, envMap map[string]string, outputs *Outputs, hookType string) []stageResult {
+	results := make([]stageResult, len(stage))
+	if len(stage) == 1 {
+		hook := hs[stage[0]]
+		result, err := hook.Run(ctx, envMap, outputs, hookType, stage[0]+1, len(hs))
+		results[0] = stageResult{hook: hook, result: result, err: err}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, idx := range stage {
+		wg.Add(1)
+		go func(i, idx int) {
+			defer wg.Done()
+			hook := hs[idx]
+			result, err := hook.Run(ctx, envMap, outputs, hookType, idx+1, len(hs))
+			results[i] = stageResult{hook: hook, result: result, err: err}
+		}(i, idx)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksStages(t *testing.T) {
+	hs := Hooks{
+		{Name: "a"},
+		{Name: "b", Group: "notify"},
+		{Name: "c", Group: "notify"},
+		{Name: "d", Parallel: true},
+		{Name: "e", Parallel: true},
+		{Name: "f"},
+		{Name: "g", Group: "notify"},
+	}
+	assert.Equal(t, [][]int{{0}, {1, 2}, {3, 4}, {5}, {6}}, hs.stages())
+	assert.Equal(t, "notify/b", hs[1].outputName())
+	assert.Equal(t, "a", hs[0].outputName())
+}
+
+func TestRunPreHooks_ParallelGroupRunsConcurrently(t *testing.T) {
+	sleep := func(name string) Hook {
+		return Hook{Name: name, Command: "sleep", Args: []string{"0.5"}, Group: "notify"}
+	}
+	hs := Hooks{sleep("one"), sleep("two"), sleep("three")}
+	require.NoError(t, hs.Configure())
+
+	start := time.Now()
+	require.NoError(t, runPreHooks(context.Background(), hs, nil, nil))
+	assert.Less(t, time.Since(start), 1200*time.Millisecond)
+}
+
+func TestRunPreHooks_ParallelMustSucceedFailureWaitsThenAborts(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "after")
+	hs := Hooks{
+		{Name: "fails", Command: "false", MustSucceed: true, Parallel: true},
+		{Name: "slow", Command: "sleep", Args: []string{"0.2"}, Parallel: true},
+		{Name: "after", Command: "touch", Args: []string{marker}},
+	}
+	require.NoError(t, hs.Configure())
+
+	start := time.Now()
+	err := runPreHooks(context.Background(), hs, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fails")
+	// the join barrier waited for the slow sibling
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.NoFileExists(t, marker)
+}
@@ -0,0 +1,168 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+)
+
+// Failover phases hooks run in, available to hooks as {{ .Phase }} and SOLANA_VALIDATOR_FAILOVER_PHASE
+const (
+	PhasePre               = "pre"
+	PhaseOnConnect         = "on_connect"
+	PhaseBeforeSetIdentity = "before_set_identity"
+	PhaseAfterSetIdentity  = "after_set_identity"
+	PhaseBeforeTowerSync   = "before_tower_sync"
+	PhaseAfterTowerSync    = "after_tower_sync"
+	PhasePost              = "post"
+	PhaseOnFailure         = "on_failure"
+	PhaseOnAbort           = "on_abort"
+	PhaseRollbackPre       = "rollback-pre"
+	PhaseRollbackPost      = "rollback-post"
+)
+
+// Phases lists the phases with PhaseHooks under failover.hooks, in the order they can occur
+var Phases = []string{
+	PhaseOnConnect,
+	PhaseBeforeSetIdentity,
+	PhaseBeforeTowerSync,
+	PhaseAfterTowerSync,
+	PhaseAfterSetIdentity,
+	PhaseOnFailure,
+	PhaseOnAbort,
+}
+
+// CriticalPathPhases are the phases on the handover's critical path - from the active node catching
+// the start of the switch slot until the passive node has taken the active identity. Their hooks
+// run in the foreground unless they set background, so each one delays the handover by however
+// long it takes.
+var CriticalPathPhases = []string{
+	PhaseBeforeSetIdentity,
+	PhaseBeforeTowerSync,
+	PhaseAfterTowerSync,
+	PhaseAfterSetIdentity,
+}
+
+// PhaseHooks is a collection of hooks for a single failover phase. when_active and when_passive
+// refer to the role this node has at the moment the phase runs - e.g. after_set_identity
+// when_passive runs on the node that just gave up the active identity, and both nodes are
+// passive during tower sync.
+type PhaseHooks struct {
+	WhenPassive Hooks `mapstructure:"when_passive"`
+	WhenActive  Hooks `mapstructure:"when_active"`
+}
+
+// configure configures the phase's hooks in place
+func (p PhaseHooks) configure(phase string) error {
+	errs := slices.Concat(
+		prefixErrors(phase+".when_active", p.WhenActive.Configure()),
+		prefixErrors(phase+".when_passive", p.WhenPassive.Configure()),
+	)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, h := range slices.Concat(p.WhenActive, p.WhenPassive) {
+		if h.MustSucceed {
+			log.Warn("must_succeed has no effect on phase hooks - they never fail the failover", "phase", phase, "hook", h.Name)
+		}
+		if slices.Contains(CriticalPathPhases, phase) && !h.Background && h.TimeoutDuration == 0 {
+			log.Warn("hook on the handover's critical path has no timeout - set timeout, or background if it needn't finish first", "phase", phase, "hook", h.Name)
+		}
+	}
+	return nil
+}
+
+// forRole returns the hooks to run for a node with the given role
+func (p PhaseHooks) forRole(role string) Hooks {
+	if role == constants.NodeRoleActive {
+		return p.WhenActive
+	}
+	return p.WhenPassive
+}
+
+// Len returns the number of hooks in the phase
+func (p PhaseHooks) Len() int {
+	return len(p.WhenActive) + len(p.WhenPassive)
+}
+
+// Phase returns the hooks for one of Phases, or empty hooks for any other phase
+func (h FailoverHooks) Phase(phase string) PhaseHooks {
+	switch phase {
+	case PhaseOnConnect:
+		return h.OnConnect
+	case PhaseBeforeSetIdentity:
+		return h.BeforeSetIdentity
+	case PhaseAfterSetIdentity:
+		return h.AfterSetIdentity
+	case PhaseBeforeTowerSync:
+		return h.BeforeTowerSync
+	case PhaseAfterTowerSync:
+		return h.AfterTowerSync
+	case PhaseOnFailure:
+		return h.OnFailure
+	case PhaseOnAbort:
+		return h.OnAbort
+	default:
+		return PhaseHooks{}
+	}
+}
+
+// PhaseQueue runs background phase hooks one phase at a time, in the order they were queued.
+// The zero value is ready to use.
+type PhaseQueue struct {
+	mu   sync.Mutex
+	last chan struct{}
+}
+
+// Go queues fn to run once everything queued before it has finished
+func (q *PhaseQueue) Go(fn func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	prev, done := q.last, make(chan struct{})
+	q.last = done
+	go func() {
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
+		fn()
+	}()
+}
+
+// Wait blocks until everything queued so far has finished
+func (q *PhaseQueue) Wait() {
+	q.mu.Lock()
+	last := q.last
+	q.mu.Unlock()
+	if last != nil {
+		<-last
+	}
+}
+
+// RunPhase runs the hooks for a phase on a node with the given current role. Phase hooks run in
+// order and failures are logged, even for must_succeed hooks - they never fail the failover.
+// Hooks that set background are queued on queue and don't delay the caller; the rest run before
+// RunPhase returns. A nil queue runs every hook in the foreground.
+func (h FailoverHooks) RunPhase(ctx context.Context, phase string, role string, envMap map[string]string, queue *PhaseQueue) {
+	hs := h.Phase(phase).forRole(role)
+	if queue == nil {
+		runPostHooks(ctx, hs, envMap, h.Outputs, phase)
+		return
+	}
+	var foreground, background Hooks
+	for _, hook := range hs {
+		if hook.Background {
+			background = append(background, hook)
+		} else {
+			foreground = append(foreground, hook)
+		}
+	}
+	if len(background) > 0 {
+		queue.Go(func() { runPostHooks(ctx, background, envMap, h.Outputs, phase) })
+	}
+	runPostHooks(ctx, foreground, envMap, h.Outputs, phase)
+}
@@ -0,0 +1,221 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// runAs is the parsed form of a command hook's user, working_dir and umask options
+type runAs struct {
+	credential *syscall.Credential // nil runs as this process's user
+	workingDir string
+	umask      *uint32
+}
+
+// configureRunAs validates the hook's user, working_dir and umask options
+func (h *Hook) configureRunAs() (err error) {
+	h.runAs = runAs{}
+	if h.User == "" && h.WorkingDir == "" && h.Umask == "" {
+		return nil
+	}
+	if h.Type != HookTypeCommand {
+		return fmt.Errorf("user, working_dir and umask only apply to command hooks")
+	}
+
+	if h.User != "" {
+		h.runAs.credential, err = lookupCredential(h.User)
+		if err != nil {
+			return err
+		}
+	}
+
+	if h.WorkingDir != "" {
+		info, err := os.Stat(h.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("invalid working_dir: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("invalid working_dir %s: not a directory", h.WorkingDir)
+		}
+		h.runAs.workingDir = h.WorkingDir
+	}
+
+	if h.Umask != "" {
+		umask, err := strconv.ParseUint(h.Umask, 8, 32)
+		if err != nil || umask > 0o777 {
+			return fmt.Errorf("invalid umask %q: must be an octal value up to 0777, e.g. 027", h.Umask)
+		}
+		u := uint32(umask)
+		h.runAs.umask = &u
+	}
+
+	return nil
+}
+
+// lookupCredential resolves "user" or "user:group" - names or numeric IDs - to the credential a
+// hook runs with. The user's supplementary groups are kept.
+func lookupCredential(spec string) (*syscall.Credential, error) {
+	userName, groupName, hasGroup := strings.Cut(spec, ":")
+
+	u, err := user.Lookup(userName)
+	if err != nil {
+		if u, err = user.LookupId(userName); err != nil {
+			return nil, fmt.Errorf("user %q does not exist", userName)
+		}
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user %q has a non-numeric uid %q", userName, u.Uid)
+	}
+
+	gidStr := u.Gid
+	if hasGroup {
+		g, err := user.LookupGroup(groupName)
+		if err != nil {
+			if g, err = user.LookupGroupId(groupName); err != nil {
+				return nil, fmt.Errorf("group %q does not exist", groupName)
+			}
+		}
+		gidStr = g.Gid
+	}
+	gid, err := strconv.ParseUint(gidStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("group %q has a non-numeric gid %q", groupName, gidStr)
+	}
+
+	if euid := os.Geteuid(); euid != 0 && uint64(euid) != uid {
+		return nil, fmt.Errorf("running a hook as user %q requires running as root", userName)
+	}
+
+	credential := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	if groupIDs, err := u.GroupIds(); err == nil {
+		for _, id := range groupIDs {
+			if n, err := strconv.ParseUint(id, 10, 32); err == nil {
+				credential.Groups = append(credential.Groups, uint32(n))
+			}
+		}
+	}
+	return credential, nil
+}
+
+// command returns the program and args to exec for the rendered command. Go cannot set a child's
+// umask directly, so with a umask the command is started through sh, which sets it and execs.
+func (r runAs) command(command string, args []string) (string, []string) {
+	if r.umask == nil {
+		return command, args
+	}
+	return "/bin/sh", append([]string{"-c", fmt.Sprintf(`umask %04o && exec "$0" "$@"`, *r.umask), command}, args...)
+}
+
+// commandPath returns the PATH a command hook's command is looked up in: the first PATH in env,
+// the hook's environment, or this process's PATH when the hook neither sets nor inherits one
+func commandPath(env []string) string {
+	for _, kv := range env {
+		if path, ok := strings.CutPrefix(kv, "PATH="); ok {
+			return path
+		}
+	}
+	return os.Getenv("PATH")
+}
+
+// resolveCommand returns the file the hook will execute for command: relative paths are resolved
+// from the working directory and bare names are looked up in path, the hook's own PATH. The file
+// and every directory above it must be usable by the user the hook runs as.
+func (r runAs) resolveCommand(command string, path string) (string, error) {
+	if strings.Contains(command, "/") {
+		if r.workingDir != "" && !filepath.IsAbs(command) {
+			command = filepath.Join(r.workingDir, command)
+		}
+		return command, r.canExecute(command)
+	}
+
+	var firstErr error
+	for _, dir := range filepath.SplitList(path) {
+		if !filepath.IsAbs(dir) {
+			// like exec.LookPath, never run a command from a relative PATH entry
+			continue
+		}
+		candidate := filepath.Join(dir, command)
+		err := r.canExecute(candidate)
+		if err == nil {
+			return candidate, nil
+		}
+		if firstErr == nil && !errors.Is(err, fs.ErrNotExist) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return "", fmt.Errorf("executable file not found in PATH %q", path)
+}
+
+// canExecute checks that the user the hook runs as - this process's user when none is set - can
+// execute file, including searching every directory above it
+func (r runAs) canExecute(file string) error {
+	uid, gids := uint32(os.Geteuid()), []uint32{uint32(os.Getegid())}
+	if r.credential != nil {
+		uid, gids = r.credential.Uid, append([]uint32{r.credential.Gid}, r.credential.Groups...)
+	} else if groups, err := os.Getgroups(); err == nil {
+		for _, g := range groups {
+			gids = append(gids, uint32(g))
+		}
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if err := checkExecBits(dir, uid, gids); err != nil {
+			return err
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", file)
+	}
+	return checkExecBits(abs, uid, gids)
+}
+
+// checkExecBits checks the execute (or, for a directory, search) permission bits of path for uid
+// and gids. Root may execute anything with an execute bit set.
+func checkExecBits(path string, uid uint32, gids []uint32) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mode := info.Mode().Perm()
+	var bit os.FileMode
+	switch stat, ok := info.Sys().(*syscall.Stat_t); {
+	case uid == 0:
+		bit = 0o111
+	case !ok:
+		bit = 0o001
+	case stat.Uid == uid:
+		bit = 0o100
+	case slices.Contains(gids, stat.Gid):
+		bit = 0o010
+	default:
+		bit = 0o001
+	}
+	if mode&bit == 0 {
+		return &fs.PathError{Op: "exec", Path: path, Err: fs.ErrPermission}
+	}
+	return nil
+}
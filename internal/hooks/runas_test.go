@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookConfigure_RunAsValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		hook Hook
+		err  string
+	}{
+		{"unknown user", Hook{Command: "true", User: "no-such-user-here"}, "does not exist"},
+		{"unknown group", Hook{Command: "true", User: "root:no-such-group-here"}, `group "no-such-group-here" does not exist`},
+		{"missing working dir", Hook{Command: "true", WorkingDir: "/no/such/dir"}, "invalid working_dir"},
+		{"bad umask", Hook{Command: "true", Umask: "999"}, "invalid umask"},
+		{"http hook", Hook{Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "http://localhost"}, WorkingDir: "/"}, "only apply to command hooks"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorContains(t, tc.hook.Configure(), tc.err)
+		})
+	}
+}
+
+func TestHookRun_WorkingDirAndUmask(t *testing.T) {
+	dir := t.TempDir()
+	h := Hook{Name: "runas", Command: "sh", Args: []string{"-c", "umask > umask.txt; touch created"}, WorkingDir: dir, Umask: "027"}
+	require.NoError(t, h.Configure())
+
+	_, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+
+	out, err := os.ReadFile(filepath.Join(dir, "umask.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "0027\n", string(out))
+	info, err := os.Stat(filepath.Join(dir, "created"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+}
+
+func TestHookRun_AsUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("switching user needs root")
+	}
+	nobody, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("no nobody user")
+	}
+	h := Hook{
+		Name:    "unprivileged",
+		Command: "sh",
+		Args:    []string{"-c", `echo "::set-output uid=$(id -u)"; echo '{"file": "ok"}' > "$SOLANA_VALIDATOR_FAILOVER_OUTPUT"`},
+		User:    "nobody",
+	}
+	require.NoError(t, h.Configure())
+
+	result, err := h.Run(context.Background(), nil, nil, "post", 1, 1)
+	require.NoError(t, err)
+	// the output file was writable by the hook's user
+	assert.Equal(t, map[string]string{"uid": nobody.Uid, "file": "ok"}, result.Outputs)
+}
@@ -0,0 +1,49 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// failoverContextKey is the context key for the failover context passed to hooks on stdin
+type failoverContextKey struct{}
+
+// WithFailoverContext returns a context carrying the failover context document - any value that
+// marshals to JSON - that command hooks run with it receive on stdin
+func WithFailoverContext(ctx context.Context, failover any) context.Context {
+	return context.WithValue(ctx, failoverContextKey{}, failover)
+}
+
+// stdinDocument is the JSON document written to a command hook's stdin
+type stdinDocument struct {
+	Hook             string                       `json:"hook"`
+	Phase            string                       `json:"phase"`
+	IsDryRunFailover bool                         `json:"is_dry_run_failover"`
+	ThisNodeRole     string                       `json:"this_node_role"`
+	PeerNodeRole     string                       `json:"peer_node_role"`
+	Cluster          string                       `json:"cluster"`
+	Error            string                       `json:"error,omitempty"`
+	Outputs          map[string]map[string]string `json:"outputs"`
+	Failover         any                          `json:"failover,omitempty"`
+}
+
+// stdinPayload builds the JSON document for a command hook's stdin from the template data and
+// the failover context carried by ctx
+func (h Hook) stdinPayload(ctx context.Context, templateData HookTemplateData) ([]byte, error) {
+	payload, err := json.Marshal(stdinDocument{
+		Hook:             h.Name,
+		Phase:            templateData.Phase,
+		IsDryRunFailover: templateData.IsDryRunFailover,
+		ThisNodeRole:     templateData.ThisNodeRole,
+		PeerNodeRole:     templateData.PeerNodeRole,
+		Cluster:          templateData.Cluster,
+		Error:            templateData.Error,
+		Outputs:          templateData.Outputs,
+		Failover:         ctx.Value(failoverContextKey{}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stdin payload: %w", err)
+	}
+	return append(payload, '\n'), nil
+}
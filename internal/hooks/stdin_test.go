@@ -0,0 +1,42 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookRun_WritesFailoverContextToStdin(t *testing.T) {
+	got := filepath.Join(t.TempDir(), "stdin.json")
+	h := Hook{Name: "reader", Command: "sh", Args: []string{"-c", "cat > " + got}}
+	require.NoError(t, h.Configure())
+
+	ctx := WithFailoverContext(context.Background(), map[string]any{"failover_start_slot": 42})
+	outputs := NewOutputs()
+	outputs.set("ticket", map[string]string{"id": "OPS-1"})
+	_, err := h.Run(ctx, map[string]string{"PHASE": PhasePre, "THIS_NODE_ROLE": "active", "CLUSTER": "testnet"}, outputs, "pre", 1, 1)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(got)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "reader", doc["hook"])
+	assert.Equal(t, "pre", doc["phase"])
+	assert.Equal(t, "active", doc["this_node_role"])
+	assert.Equal(t, "testnet", doc["cluster"])
+	assert.Equal(t, "OPS-1", doc["outputs"].(map[string]any)["ticket"].(map[string]any)["id"])
+	assert.Equal(t, float64(42), doc["failover"].(map[string]any)["failover_start_slot"])
+}
+
+func TestHookRun_IgnoringStdinIsFine(t *testing.T) {
+	h := Hook{Name: "ignores-stdin", Command: "true"}
+	require.NoError(t, h.Configure())
+	_, err := h.Run(WithFailoverContext(context.Background(), map[string]string{"big": string(make([]byte, 1<<20))}), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+}
@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+)
+
+// SampleTemplateData returns template data with a placeholder for every field, used to check
+// hook templates render before a failover needs them
+func SampleTemplateData() HookTemplateData {
+	return HookTemplateData{
+		IsDryRunFailover:               true,
+		ThisNodeRole:                   constants.NodeRoleActive,
+		PeerNodeRole:                   constants.NodeRolePassive,
+		Phase:                          PhasePre,
+		Error:                          "sample error",
+		Cluster:                        "mainnet-beta",
+		Outputs:                        map[string]map[string]string{},
+		ThisNodeName:                   "this-node",
+		ThisNodePublicIP:               "192.0.2.1",
+		ThisNodeActiveIdentityPubkey:   "ActiveIdentityPubkey11111111111111111111111",
+		ThisNodeActiveIdentityKeyFile:  "/path/to/active-identity.json",
+		ThisNodePassiveIdentityPubkey:  "PassiveIdentityPubkey1111111111111111111111",
+		ThisNodePassiveIdentityKeyFile: "/path/to/passive-identity.json",
+		ThisNodeClientVersion:          "0.0.0",
+		ThisNodeClientVersionLocalRPC:  "0.0.0",
+		ThisNodeRPCAddress:             "http://localhost:8899",
+		PeerNodeName:                   "peer-node",
+		PeerNodePublicIP:               "192.0.2.2",
+		PeerNodeActiveIdentityPubkey:   "ActiveIdentityPubkey11111111111111111111111",
+		PeerNodePassiveIdentityPubkey:  "PeerPassiveIdentityPubkey111111111111111111",
+		PeerNodeClientVersion:          "0.0.0",
+		PeerNodeClientVersionLocalRPC:  "0.0.0",
+	}
+}
+
+// Validate renders the hook's templates against the sample data and checks a command hook's
+// command resolves to a file the hook's user can execute - looked up in the hook's own PATH -
+// returning every problem found. Commands that are themselves templates are only resolved at
+// run time. A hook that failed Configure is skipped - its Configure error says what is wrong.
+func (h Hook) Validate(sample HookTemplateData) error {
+	if !h.configured {
+		return nil
+	}
+
+	var errs []error
+	addErr := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf("hook %s: "+format, append([]any{h.Name}, a...)...))
+	}
+
+	if _, _, err := h.ShouldRun(sample); err != nil {
+		addErr("invalid when: %w", err)
+	}
+
+	if h.Type == HookTypeHTTP {
+		if _, err := h.HTTP.render(sample); err != nil {
+			addErr("%w", err)
+		}
+		return errors.Join(errs...)
+	}
+
+	command, err := executeTemplate(h.Command, sample)
+	if err != nil {
+		addErr("failed to execute command template: %w", err)
+	}
+	for i, arg := range h.Args {
+		if _, err := executeTemplate(arg, sample); err != nil {
+			addErr("failed to execute arg[%d] template: %w", i, err)
+		}
+	}
+	// the hook's environment as it runs with it - its own variables win over inherited ones
+	var env []string
+	for envKey, envValue := range h.Environment {
+		rendered, err := executeTemplate(envValue, sample)
+		if err != nil {
+			addErr("failed to execute environment variable %s template: %w", envKey, err)
+		}
+		env = append(env, strings.ToUpper(envKey)+"="+strings.TrimSpace(rendered))
+	}
+	for envKey, envValue := range h.inheritEnv.environ() {
+		env = append(env, envKey+"="+envValue)
+	}
+
+	if err == nil && !strings.Contains(h.Command, "{{") {
+		if _, err := h.runAs.resolveCommand(command, commandPath(env)); err != nil {
+			addErr("command %s is not runnable: %w", command, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate validates every hook in the collection, returning every problem found
+func (hs Hooks) Validate(sample HookTemplateData) error {
+	var errs []error
+	for _, h := range hs {
+		errs = append(errs, h.Validate(sample))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate validates all pre, post and phase hooks against the sample data, returning every
+// problem found
+func (h FailoverHooks) Validate(sample HookTemplateData) error {
+	var errs []error
+	sample.Phase = PhasePre
+	errs = append(errs, prefixErrors("pre.when_active", h.Pre.WhenActive.Validate(sample))...)
+	errs = append(errs, prefixErrors("pre.when_passive", h.Pre.WhenPassive.Validate(sample))...)
+	sample.Phase = PhasePost
+	errs = append(errs, prefixErrors("post.when_active", h.Post.WhenActive.Validate(sample))...)
+	errs = append(errs, prefixErrors("post.when_passive", h.Post.WhenPassive.Validate(sample))...)
+	for _, phase := range Phases {
+		sample.Phase = phase
+		errs = append(errs, prefixErrors(phase+".when_active", h.Phase(phase).WhenActive.Validate(sample))...)
+		errs = append(errs, prefixErrors(phase+".when_passive", h.Phase(phase).WhenPassive.Validate(sample))...)
+	}
+	return errors.Join(errs...)
+}
+
+// prefixErrors splits an errors.Join error and prefixes each error in it, so every line of the
+// joined message says where the problem is
+func prefixErrors(prefix string, err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, prefixErrors(prefix, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", prefix, err)}
+}
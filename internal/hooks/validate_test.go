@@ -0,0 +1,138 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookValidate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "not-executable.sh"), []byte("#!/bin/sh\n"), 0o644))
+
+	tests := []struct {
+		name    string
+		hook    Hook
+		wantErr []string
+	}{
+		{name: "valid", hook: Hook{Name: "ok", Command: "echo", Args: []string{"{{ .ThisNodeName }}"}, Environment: map[string]string{"PEER": "{{ .PeerNodePublicIP }}"}}},
+		{name: "relative to working_dir", hook: Hook{Name: "ok", Command: "./run.sh", WorkingDir: dir}},
+		{name: "templated command is not resolved", hook: Hook{Name: "ok", Command: "{{ .ThisNodeName }}-notify"}},
+		{name: "unknown field in arg", hook: Hook{Name: "typo", Command: "echo", Args: []string{"{{ .ThisNodeNmae }}"}}, wantErr: []string{"hook typo: failed to execute arg[0] template"}},
+		{name: "unknown field in environment", hook: Hook{Name: "typo", Command: "echo", Environment: map[string]string{"X": "{{ .Nope }}"}}, wantErr: []string{"environment variable X template"}},
+		{name: "when not a bool", hook: Hook{Name: "when", Command: "echo", When: "{{ .Cluster }}"}, wantErr: []string{"hook when: invalid when"}},
+		{name: "missing command", hook: Hook{Name: "missing", Command: "no-such-command-here"}, wantErr: []string{"hook missing: command no-such-command-here is not runnable"}},
+		{name: "not executable", hook: Hook{Name: "perms", Command: filepath.Join(dir, "not-executable.sh")}, wantErr: []string{"hook perms: command", "permission denied"}},
+		{name: "http url", hook: Hook{Name: "lb", Type: HookTypeHTTP, HTTP: HTTPConfig{URL: "https://lb/{{ .Nope }}"}}, wantErr: []string{"hook lb: failed to execute url template"}},
+		{
+			name:    "every problem at once",
+			hook:    Hook{Name: "many", Command: "no-such-command-here", Args: []string{"{{ .Nope }}"}, When: "{{ .Nope }}"},
+			wantErr: []string{"invalid when", "arg[0] template", "is not runnable"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.hook.Configure())
+			err := tt.hook.Validate(SampleTemplateData())
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestHookValidate_ResolvesInHookPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "only-in-hook-path"), []byte("#!/bin/sh\necho ::set-output ran=yes\n"), 0o755))
+
+	h := Hook{Name: "own-path", Command: "only-in-hook-path", Environment: map[string]string{"PATH": dir}}
+	require.NoError(t, h.Configure())
+	assert.NoError(t, h.Validate(SampleTemplateData()))
+
+	// the hook runs the command it was validated against
+	result, err := h.Run(context.Background(), nil, nil, "pre", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "yes", result.Outputs["ran"])
+
+	h = Hook{Name: "process-path", Command: "only-in-hook-path"}
+	require.NoError(t, h.Configure())
+	assert.ErrorContains(t, h.Validate(SampleTemplateData()), "command only-in-hook-path is not runnable")
+}
+
+func TestRunAsCanExecute_TargetUser(t *testing.T) {
+	dir := t.TempDir()
+	rootOnly := filepath.Join(dir, "root-only.sh")
+	require.NoError(t, os.WriteFile(rootOnly, []byte("#!/bin/sh\n"), 0o700))
+	// t.TempDir directories are only searchable by their owner
+	require.NoError(t, os.Chmod(filepath.Dir(dir), 0o755))
+	require.NoError(t, os.Chmod(dir, 0o755))
+	owner := uint32(os.Geteuid())
+	if owner == 65534 {
+		t.Skip("running as nobody")
+	}
+
+	nobody := runAs{credential: &syscall.Credential{Uid: 65534, Gid: 65534}}
+	assert.ErrorIs(t, nobody.canExecute(rootOnly), os.ErrPermission)
+
+	// the owner can run it
+	assert.NoError(t, runAs{credential: &syscall.Credential{Uid: owner, Gid: 65534}}.canExecute(rootOnly))
+
+	// a group with execute permission can run it
+	require.NoError(t, os.Chmod(rootOnly, 0o750))
+	info, err := os.Stat(rootOnly)
+	require.NoError(t, err)
+	gid := info.Sys().(*syscall.Stat_t).Gid
+	assert.NoError(t, runAs{credential: &syscall.Credential{Uid: 65534, Gid: 65534, Groups: []uint32{gid}}}.canExecute(rootOnly))
+
+	// an executable file in a directory the user can't search can't be run either
+	require.NoError(t, os.Chmod(rootOnly, 0o755))
+	require.NoError(t, os.Chmod(dir, 0o700))
+	t.Cleanup(func() { _ = os.Chmod(dir, 0o755) })
+	assert.ErrorIs(t, nobody.canExecute(rootOnly), os.ErrPermission)
+}
+
+func TestFailoverHooksValidate_PrefixesEveryProblem(t *testing.T) {
+	h := FailoverHooks{
+		Pre: PreHooks{WhenActive: Hooks{
+			{Name: "a", Command: "no-such-command-here"},
+			{Name: "b", Command: "echo", Args: []string{"{{ .Nope }}"}},
+		}},
+		OnFailure: PhaseHooks{WhenPassive: Hooks{{Name: "c", Command: "echo", When: `{{ eq .Phase "on_failure" }}`}}},
+	}
+	require.NoError(t, h.Configure())
+
+	err := h.Validate(SampleTemplateData())
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "pre.when_active: hook a: "), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "pre.when_active: hook b: "), lines[1])
+}
+
+func TestFailoverHooksConfigure_ReportsEveryProblem(t *testing.T) {
+	h := FailoverHooks{
+		Pre:       PreHooks{WhenPassive: Hooks{{Name: "a"}}},
+		Post:      PostHooks{WhenActive: Hooks{{Name: "b", Command: "echo", Timeout: "soon"}}},
+		OnConnect: PhaseHooks{WhenActive: Hooks{{Name: "c", Type: "smoke-signal"}}},
+	}
+
+	err := h.Configure()
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"pre.when_passive: hook a: command is required",
+		`post.when_active: hook b: invalid timeout "soon": time: invalid duration "soon"`,
+		`on_connect.when_active: hook c: unknown type "smoke-signal", must be one of: command, http`,
+	}, strings.Split(err.Error(), "\n"))
+}
@@ -12,4 +12,7 @@ type Config struct {
 	ActivePubkey  string `mapstructure:"active_pubkey"`
 	Passive       string `mapstructure:"passive"`
 	PassivePubkey string `mapstructure:"passive_pubkey"`
+	// AuthorizedVoter is an optional path to the vote account's authorized voter keypair, for
+	// vote accounts whose authorized voter is not the active identity
+	AuthorizedVoter string `mapstructure:"authorized_voter"`
 }
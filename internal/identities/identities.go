@@ -8,8 +8,9 @@ import (
 
 // Identities holds the information for the identities
 type Identities struct {
-	Active  *Identity
-	Passive *Identity
+	Active          *Identity
+	Passive         *Identity
+	AuthorizedVoter *Identity // nil unless identities.authorized_voter is set
 }
 
 // NewFromConfig creates a new identities from a config.
@@ -58,5 +59,14 @@ func NewFromConfig(cfg *Config) (identities *Identities, err error) {
 		return nil, fmt.Errorf("active and passive identities must be different")
 	}
 
+	// load the authorized voter - a keypair file only, since it must be present to vote with
+	if cfg.AuthorizedVoter != "" {
+		logger.Debug("loading authorized voter from keypair file", "file", cfg.AuthorizedVoter)
+		identities.AuthorizedVoter, err = NewIdentityFromFile(cfg.AuthorizedVoter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load authorized voter: %w", err)
+		}
+	}
+
 	return
 }
@@ -410,3 +410,36 @@ func TestNewFromConfig_KeypairFileOverridesPubkey(t *testing.T) {
 	assert.Equal(t, activeKey.PublicKey().String(), identities.Active.PubKey())
 	assert.NotEqual(t, "11111111111111111111111111111111", identities.Active.PubKey())
 }
+
+func TestNewFromConfig_AuthorizedVoter(t *testing.T) {
+	tempDir := t.TempDir()
+	voterKeyFile := filepath.Join(tempDir, "authorized-voter.json")
+	voterKey := solana.NewWallet().PrivateKey
+	voterKeyData, err := json.Marshal([]byte(voterKey))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(voterKeyFile, voterKeyData, 0600))
+
+	cfg := &Config{
+		ActivePubkey:    "11111111111111111111111111111111",
+		PassivePubkey:   "SysvarC1ock11111111111111111111111111111111",
+		AuthorizedVoter: voterKeyFile,
+	}
+
+	identities, err := NewFromConfig(cfg)
+
+	require.NoError(t, err)
+	require.NotNil(t, identities.AuthorizedVoter)
+	assert.Equal(t, voterKey.PublicKey().String(), identities.AuthorizedVoter.PubKey())
+
+	// unset, there is none
+	cfg.AuthorizedVoter = ""
+	identities, err = NewFromConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, identities.AuthorizedVoter)
+
+	// it must be a keypair file
+	cfg.AuthorizedVoter = filepath.Join(tempDir, "missing.json")
+	_, err = NewFromConfig(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load authorized voter")
+}
@@ -0,0 +1,309 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// ProviderSlack posts to a Slack incoming webhook
+	ProviderSlack = "slack"
+	// ProviderDiscord posts to a Discord webhook
+	ProviderDiscord = "discord"
+	// ProviderPagerDuty triggers a PagerDuty Events v2 alert
+	ProviderPagerDuty = "pagerduty"
+	// ProviderTelegram sends a message through the Telegram bot API
+	ProviderTelegram = "telegram"
+	// ProviderWebhook posts the event as JSON to any URL
+	ProviderWebhook = "webhook"
+
+	// DefaultTimeout is the default timeout for sending a single notification
+	DefaultTimeout = "10s"
+
+	defaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	defaultTelegramURL  = "https://api.telegram.org"
+)
+
+// EventType is a failover lifecycle event that notifications can subscribe to
+type EventType string
+
+const (
+	// EventPlanned fires on the passive node once the failover plan is confirmed
+	EventPlanned EventType = "planned"
+	// EventStarted fires on the active node just before it changes identity
+	EventStarted EventType = "started"
+	// EventCompleted fires on the new active node once the failover succeeds, with the summary
+	EventCompleted EventType = "completed"
+	// EventRollback fires on any node that starts an automatic rollback
+	EventRollback EventType = "rollback"
+	// EventGossipConfirmationFailed fires on the new active node when gossip does not show the role switch
+	EventGossipConfirmationFailed EventType = "gossip_confirmation_failed"
+	// EventVotesStalled fires on the new active node when post-failover vote monitoring finds votes not landing
+	EventVotesStalled EventType = "votes_stalled"
+)
+
+// AllEvents lists every event type, in lifecycle order
+var AllEvents = []EventType{EventPlanned, EventStarted, EventCompleted, EventRollback, EventGossipConfirmationFailed, EventVotesStalled}
+
+// Config is a single notification target under failover.notifications
+type Config struct {
+	Name string `mapstructure:"name"`
+	// Type is one of: slack, discord, pagerduty, telegram, webhook
+	Type string `mapstructure:"type"`
+	// Events limits which events are sent. Default: all events, except pagerduty which defaults
+	// to rollback, gossip_confirmation_failed and votes_stalled
+	Events []string `mapstructure:"events"`
+	// URL is the webhook URL for slack, discord and webhook, and an optional API URL
+	// override for pagerduty and telegram
+	URL string `mapstructure:"url"`
+	// Headers are extra request headers, e.g. auth for a generic webhook
+	Headers map[string]string `mapstructure:"headers"`
+	// RoutingKey is the PagerDuty integration key
+	RoutingKey string `mapstructure:"routing_key"`
+	// BotToken and ChatID are the Telegram bot token and target chat
+	BotToken string `mapstructure:"bot_token"`
+	ChatID   string `mapstructure:"chat_id"`
+	// Timeout bounds sending a single notification. Default: 10s
+	Timeout string `mapstructure:"timeout"`
+}
+
+// Node describes one side of the failover in an event
+type Node struct {
+	Name          string `json:"name"`
+	PublicIP      string `json:"public_ip"`
+	ActivePubkey  string `json:"active_pubkey"`
+	PassivePubkey string `json:"passive_pubkey"`
+}
+
+// Event is a failover lifecycle event
+type Event struct {
+	Type     EventType `json:"type"`
+	Time     time.Time `json:"time"`
+	IsDryRun bool      `json:"is_dry_run"`
+	// From is the node giving up the active identity, To is the node taking it
+	From Node `json:"from"`
+	To   Node `json:"to"`
+	// Schedule describes when a planned failover will run, if scheduled
+	Schedule string `json:"schedule,omitempty"`
+	// Detail is extra context, e.g. the error behind a rollback
+	Detail string `json:"detail,omitempty"`
+	// Timing, set once the failover has completed
+	TotalDuration time.Duration `json:"total_duration_ns,omitempty"`
+	StartSlot     uint64        `json:"start_slot,omitempty"`
+	EndSlot       uint64        `json:"end_slot,omitempty"`
+	Slots         uint64        `json:"slots,omitempty"`
+	// Summary is the plain-text post-failover summary table, set on completed events
+	Summary string `json:"summary,omitempty"`
+}
+
+// Title returns a one-line description of the event
+func (e Event) Title() string {
+	prefix := ""
+	if e.IsDryRun {
+		prefix = "(dry run) "
+	}
+	var what string
+	switch e.Type {
+	case EventPlanned:
+		what = "failover planned"
+	case EventStarted:
+		what = "failover started"
+	case EventCompleted:
+		what = "failover completed"
+	case EventRollback:
+		what = "failover rollback triggered"
+	case EventGossipConfirmationFailed:
+		what = "gossip does not confirm failover"
+	case EventVotesStalled:
+		what = "votes not landing after failover"
+	default:
+		what = string(e.Type)
+	}
+	return fmt.Sprintf("%s%s: %s → %s", prefix, what, e.From.Name, e.To.Name)
+}
+
+// Text returns the multi-line body of the event
+func (e Event) Text() string {
+	lines := []string{
+		fmt.Sprintf("from: %s (%s) active=%s passive=%s", e.From.Name, e.From.PublicIP, e.From.ActivePubkey, e.From.PassivePubkey),
+		fmt.Sprintf("to:   %s (%s) active=%s passive=%s", e.To.Name, e.To.PublicIP, e.To.ActivePubkey, e.To.PassivePubkey),
+	}
+	if e.Schedule != "" {
+		lines = append(lines, "scheduled: "+e.Schedule)
+	}
+	if e.TotalDuration > 0 {
+		lines = append(lines, fmt.Sprintf("took: %s over %d slots (%d → %d)", e.TotalDuration.Round(time.Millisecond), e.Slots, e.StartSlot, e.EndSlot))
+	}
+	if e.Detail != "" {
+		lines = append(lines, e.Detail)
+	}
+	if e.Summary != "" {
+		lines = append(lines, "", strings.TrimRight(e.Summary, "\n"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notifier sends events to a single configured target
+type notifier struct {
+	config  Config
+	events  []EventType
+	timeout time.Duration
+	client  *http.Client
+}
+
+// Dispatcher sends events to every configured notifier. Sends are asynchronous so they never
+// delay the failover; call Flush to wait for in-flight sends before exiting.
+// A nil Dispatcher is valid and sends nothing.
+type Dispatcher struct {
+	notifiers []notifier
+	logger    *log.Logger
+	wg        sync.WaitGroup
+}
+
+// New validates the notification configs and returns a dispatcher for them
+func New(configs []Config) (*Dispatcher, error) {
+	d := &Dispatcher{logger: log.WithPrefix("notifications")}
+	for i, cfg := range configs {
+		n, err := newNotifier(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("notifications[%d] %s: %w", i, cfg.Name, err)
+		}
+		d.notifiers = append(d.notifiers, n)
+	}
+	return d, nil
+}
+
+// newNotifier validates a single config
+func newNotifier(cfg Config) (n notifier, err error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+
+	switch cfg.Type {
+	case ProviderSlack, ProviderDiscord, ProviderWebhook:
+		if cfg.URL == "" {
+			return n, fmt.Errorf("url is required for %s", cfg.Type)
+		}
+	case ProviderPagerDuty:
+		if cfg.RoutingKey == "" {
+			return n, fmt.Errorf("routing_key is required for pagerduty")
+		}
+	case ProviderTelegram:
+		if cfg.BotToken == "" || cfg.ChatID == "" {
+			return n, fmt.Errorf("bot_token and chat_id are required for telegram")
+		}
+	default:
+		return n, fmt.Errorf("unknown type %q, must be one of: %s", cfg.Type,
+			strings.Join([]string{ProviderSlack, ProviderDiscord, ProviderPagerDuty, ProviderTelegram, ProviderWebhook}, ", "))
+	}
+
+	n.events = AllEvents
+	if cfg.Type == ProviderPagerDuty {
+		n.events = pagerDutyDefaultEvents
+	}
+	if len(cfg.Events) > 0 {
+		n.events = nil
+		for _, e := range cfg.Events {
+			if !slices.Contains(AllEvents, EventType(e)) {
+				return n, fmt.Errorf("unknown event %q", e)
+			}
+			n.events = append(n.events, EventType(e))
+		}
+	}
+
+	timeout := cfg.Timeout
+	if timeout == "" {
+		timeout = DefaultTimeout
+	}
+	n.timeout, err = time.ParseDuration(timeout)
+	if err != nil {
+		return n, fmt.Errorf("invalid timeout %q: %w", cfg.Timeout, err)
+	}
+
+	n.config = cfg
+	n.client = &http.Client{}
+	return n, nil
+}
+
+// Len returns the number of configured notifiers
+func (d *Dispatcher) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.notifiers)
+}
+
+// Notify sends the event to every notifier subscribed to its type, without waiting
+func (d *Dispatcher) Notify(event Event) {
+	if d == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	for _, n := range d.notifiers {
+		if !slices.Contains(n.events, event.Type) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(n notifier) {
+			defer d.wg.Done()
+			if err := n.send(event); err != nil {
+				d.logger.Error("failed to send notification", "name", n.config.Name, "type", n.config.Type, "event", event.Type, "err", err)
+				return
+			}
+			d.logger.Debug("notification sent", "name", n.config.Name, "type", n.config.Type, "event", event.Type)
+		}(n)
+	}
+}
+
+// Flush waits for in-flight notifications to finish sending
+func (d *Dispatcher) Flush() {
+	if d == nil {
+		return
+	}
+	d.wg.Wait()
+}
+
+// send delivers the event to the notifier's target
+func (n notifier) send(event Event) error {
+	url, payload := n.request(event)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a stand-in for every provider's API, recording the requests it receives
+type recorder struct {
+	mu       sync.Mutex
+	paths    []string
+	payloads []map[string]any
+	status   int
+}
+
+func newRecorder(t *testing.T) (*recorder, *httptest.Server) {
+	r := &recorder{status: http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var payload map[string]any
+		_ = json.Unmarshal(body, &payload)
+		r.mu.Lock()
+		r.paths = append(r.paths, req.URL.Path)
+		r.payloads = append(r.payloads, payload)
+		status := r.status
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func testEvent(eventType EventType) Event {
+	return Event{
+		Type: eventType,
+		From: Node{Name: "london", PublicIP: "10.0.0.1", ActivePubkey: "ACTIVE", PassivePubkey: "LONDON-PASSIVE"},
+		To:   Node{Name: "chicago", PublicIP: "10.0.0.2", ActivePubkey: "ACTIVE", PassivePubkey: "CHICAGO-PASSIVE"},
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{"unknown type", Config{Type: "carrier-pigeon"}, `unknown type "carrier-pigeon"`},
+		{"slack without url", Config{Type: ProviderSlack}, "url is required"},
+		{"pagerduty without key", Config{Type: ProviderPagerDuty}, "routing_key is required"},
+		{"telegram without chat", Config{Type: ProviderTelegram, BotToken: "t"}, "bot_token and chat_id are required"},
+		{"unknown event", Config{Type: ProviderWebhook, URL: "http://x", Events: []string{"exploded"}}, `unknown event "exploded"`},
+		{"bad timeout", Config{Type: ProviderWebhook, URL: "http://x", Timeout: "later"}, "invalid timeout"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New([]Config{tc.cfg})
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestNilDispatcher(t *testing.T) {
+	var d *Dispatcher
+	d.Notify(testEvent(EventStarted))
+	d.Flush()
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestNotify_Providers(t *testing.T) {
+	r, server := newRecorder(t)
+
+	d, err := New([]Config{
+		{Name: "slack", Type: ProviderSlack, URL: server.URL + "/slack"},
+		{Name: "discord", Type: ProviderDiscord, URL: server.URL + "/discord"},
+		{Name: "pd", Type: ProviderPagerDuty, URL: server.URL + "/pagerduty", RoutingKey: "rk", Events: []string{"completed"}},
+		{Name: "tg", Type: ProviderTelegram, URL: server.URL, BotToken: "TOKEN", ChatID: "42"},
+		{Name: "hook", Type: ProviderWebhook, URL: server.URL + "/webhook"},
+	})
+	require.NoError(t, err)
+
+	event := testEvent(EventCompleted)
+	event.TotalDuration = 1500 * time.Millisecond
+	event.StartSlot, event.EndSlot, event.Slots = 100, 102, 2
+	event.Summary = "summary table"
+	d.Notify(event)
+	d.Flush()
+
+	require.Len(t, r.payloads, 5)
+	byPath := map[string]map[string]any{}
+	for i, p := range r.paths {
+		byPath[p] = r.payloads[i]
+	}
+
+	assert.Contains(t, byPath["/slack"]["text"], "failover completed: london → chicago")
+	assert.Contains(t, byPath["/slack"]["text"], "took: 1.5s over 2 slots (100 → 102)")
+	assert.Contains(t, byPath["/discord"]["content"], "summary table")
+
+	pd := byPath["/pagerduty"]
+	assert.Equal(t, "rk", pd["routing_key"])
+	assert.Equal(t, "trigger", pd["event_action"])
+	assert.Equal(t, "solana-validator-failover/completed/london->chicago", pd["dedup_key"])
+	assert.Equal(t, "info", pd["payload"].(map[string]any)["severity"])
+
+	tg := byPath["/botTOKEN/sendMessage"]
+	assert.Equal(t, "42", tg["chat_id"])
+	assert.Contains(t, tg["text"], "CHICAGO-PASSIVE")
+
+	hook := byPath["/webhook"]
+	assert.Equal(t, "completed", hook["type"])
+	assert.Equal(t, "chicago", hook["to"].(map[string]any)["name"])
+}
+
+func TestNotify_EventFilter(t *testing.T) {
+	r, server := newRecorder(t)
+
+	d, err := New([]Config{{Type: ProviderWebhook, URL: server.URL, Events: []string{"rollback", "gossip_confirmation_failed"}}})
+	require.NoError(t, err)
+
+	d.Notify(testEvent(EventStarted))
+	d.Notify(testEvent(EventRollback))
+	d.Flush()
+
+	require.Len(t, r.payloads, 1)
+	assert.Equal(t, "rollback", r.payloads[0]["type"])
+}
+
+func TestNotify_PagerDutyDefaultEvents(t *testing.T) {
+	r, server := newRecorder(t)
+
+	d, err := New([]Config{{Type: ProviderPagerDuty, URL: server.URL, RoutingKey: "rk"}})
+	require.NoError(t, err)
+
+	for _, eventType := range AllEvents {
+		d.Notify(testEvent(eventType))
+	}
+	d.Flush()
+
+	require.Len(t, r.payloads, 3)
+	for _, p := range r.payloads {
+		assert.Equal(t, "critical", p["payload"].(map[string]any)["severity"])
+	}
+}
+
+func TestNotify_DiscordTruncatesOnRuneBoundaries(t *testing.T) {
+	r, server := newRecorder(t)
+
+	d, err := New([]Config{{Type: ProviderDiscord, URL: server.URL}})
+	require.NoError(t, err)
+
+	event := testEvent(EventCompleted)
+	event.Summary = strings.Repeat("─→", discordContentLimit)
+	d.Notify(event)
+	d.Flush()
+
+	require.Len(t, r.payloads, 1)
+	content := r.payloads[0]["content"].(string)
+	assert.True(t, utf8.ValidString(content))
+	assert.Equal(t, discordContentLimit, utf8.RuneCountInString(content))
+	assert.True(t, strings.HasSuffix(content, "…```"))
+}
+
+func TestEvent_Title(t *testing.T) {
+	event := testEvent(EventGossipConfirmationFailed)
+	event.IsDryRun = true
+	assert.Equal(t, "(dry run) gossip does not confirm failover: london → chicago", event.Title())
+}
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// discordContentLimit is the maximum length of a Discord message, in characters
+const discordContentLimit = 2000
+
+// pagerDutyDefaultEvents are the events sent to PagerDuty when none are configured - only those
+// that need someone to act, as every trigger opens an incident
+var pagerDutyDefaultEvents = []EventType{EventRollback, EventGossipConfirmationFailed, EventVotesStalled}
+
+// request returns the URL and JSON payload to post for the event
+func (n notifier) request(event Event) (url string, payload any) {
+	switch n.config.Type {
+	case ProviderSlack:
+		return n.config.URL, map[string]any{
+			"text": "*" + event.Title() + "*\n```" + event.Text() + "```",
+		}
+	case ProviderDiscord:
+		content := "**" + event.Title() + "**\n```" + event.Text() + "```"
+		if utf8.RuneCountInString(content) > discordContentLimit {
+			// truncate on rune boundaries so multi-byte characters like → aren't split
+			content = string([]rune(content)[:discordContentLimit-utf8.RuneCountInString("…```")]) + "…```"
+		}
+		return n.config.URL, map[string]any{
+			"content": content,
+		}
+	case ProviderPagerDuty:
+		url = n.config.URL
+		if url == "" {
+			url = defaultPagerDutyURL
+		}
+		return url, map[string]any{
+			"routing_key":  n.config.RoutingKey,
+			"event_action": "trigger",
+			"dedup_key":    pagerDutyDedupKey(event),
+			"payload": map[string]any{
+				"summary":        event.Title(),
+				"source":         event.To.Name,
+				"severity":       pagerDutySeverity(event.Type),
+				"timestamp":      event.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+				"component":      "solana-validator-failover",
+				"custom_details": event,
+			},
+		}
+	case ProviderTelegram:
+		base := n.config.URL
+		if base == "" {
+			base = defaultTelegramURL
+		}
+		return strings.TrimRight(base, "/") + "/bot" + n.config.BotToken + "/sendMessage", map[string]any{
+			"chat_id": n.config.ChatID,
+			"text":    event.Title() + "\n\n" + event.Text(),
+		}
+	default:
+		return n.config.URL, event
+	}
+}
+
+// pagerDutySeverity maps an event to a PagerDuty Events v2 severity
+func pagerDutySeverity(eventType EventType) string {
+	switch eventType {
+	case EventRollback, EventGossipConfirmationFailed, EventVotesStalled:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// pagerDutyDedupKey returns the key PagerDuty groups alerts by, so repeats of the same event for
+// the same pair of nodes add to the open incident rather than opening another
+func pagerDutyDedupKey(event Event) string {
+	return "solana-validator-failover/" + string(event.Type) + "/" + event.From.Name + "->" + event.To.Name
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -24,9 +25,13 @@ type RPCClientInterface interface {
 	GetVoteAccounts(ctx context.Context, opts *rpc.GetVoteAccountsOpts) (*rpc.GetVoteAccountsResult, error)
 	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
 	GetLeaderSchedule(ctx context.Context) (rpc.GetLeaderScheduleResult, error)
+	GetLeaderScheduleWithOpts(ctx context.Context, opts *rpc.GetLeaderScheduleOpts) (rpc.GetLeaderScheduleResult, error)
+	GetRecentPerformanceSamples(ctx context.Context, limit *uint) ([]*rpc.GetRecentPerformanceSamplesResult, error)
 	GetHealth(ctx context.Context) (string, error)
 	GetEpochInfo(ctx context.Context, commitment rpc.CommitmentType) (*rpc.GetEpochInfoResult, error)
 	GetVersion(ctx context.Context) (*rpc.GetVersionResult, error)
+	GetIdentity(ctx context.Context) (*rpc.GetIdentityResult, error)
+	GetAccountInfoWithOpts(ctx context.Context, account solanago.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error)
 }
 
 // ClientInterface defines the interface for solana rpc operations - just simple wrappers around the rpc client
@@ -46,8 +51,17 @@ type ClientInterface interface {
 	GetCreditRankedVoteAccountFromPubkey(pubkey string) (*rpc.VoteAccountsResult, int, error)
 	// GetCurrentSlot returns the current slot
 	GetCurrentSlot() (slot uint64, err error)
+	// GetEpochInfo returns the cluster's current epoch info
+	GetEpochInfo() (*rpc.GetEpochInfoResult, error)
 	// GetTimeToNextLeaderSlotForPubkey returns the time to the next leader slot for the given pubkey
 	GetTimeToNextLeaderSlotForPubkey(pubkey solanago.PublicKey) (isOnLeaderSchedule bool, timeToNextLeaderSlot time.Duration, err error)
+	// GetLeaderFreeWindows scans the current and next epoch's leader schedule for the given pubkey
+	// and returns the leader-free windows from the current slot onwards
+	GetLeaderFreeWindows(pubkey solanago.PublicKey) (*LeaderFreeWindows, error)
+	// NewSlotSource returns a source of slot transitions for detecting slot starts, which must be closed
+	NewSlotSource(ctx context.Context) SlotSource
+	// GetSlotDuration returns the cluster's current slot time and whether it was measured or configured
+	GetSlotDuration() SlotDuration
 	// GetLocalNodeHealth returns the health of the local node
 	GetLocalNodeHealth() (string, error)
 	// IsLocalNodeHealthy returns true if the local node is healthy
@@ -56,6 +70,18 @@ type ClientInterface interface {
 	// This may differ from the gossip-reported version for clients like jito-solana or firedancer.
 	// Returns an empty string and an error if the call fails.
 	GetLocalNodeVersion() (string, error)
+	// GetLocalNodeIdentity returns the identity pubkey the local validator reports via its getIdentity RPC call
+	GetLocalNodeIdentity() (string, error)
+	// GetVoteAccountForIdentity returns the vote account whose node pubkey is identity, delinquent or not,
+	// along with its authorized voter for the current epoch
+	GetVoteAccountForIdentity(identity string) (*VoteAccount, error)
+	// GetVoteProgress returns how the vote account whose node pubkey is identity is voting, against the
+	// cluster slot and the median credits of current vote accounts
+	GetVoteProgress(identity string) (*VoteProgress, error)
+	// GetLocalNodeSlot returns the local validator's slot at the given commitment
+	GetLocalNodeSlot(commitment rpc.CommitmentType) (uint64, error)
+	// GetLocalNodeSlotLag compares the local validator's slot against the cluster rpc's at the given commitment
+	GetLocalNodeSlotLag(commitment rpc.CommitmentType) (SlotLag, error)
 }
 
 // Client implements Interface using an RPC client
@@ -65,6 +91,11 @@ type Client struct {
 	loggerLocal         *log.Logger
 	loggerNetwork       *log.Logger
 	averageSlotDuration time.Duration
+	localWSURL          string // local validator pubsub websocket, empty to always poll for slots
+
+	slotDurationMu         sync.Mutex
+	slotDuration           SlotDuration // last measured slot duration
+	slotDurationMeasuredAt time.Time
 }
 
 // NewClientParams is the parameters for creating a new client
@@ -72,6 +103,7 @@ type NewClientParams struct {
 	LocalRPCURL         string
 	ClusterRPCURL       string
 	AverageSlotDuration time.Duration // average slot duration, defaults to 400ms
+	LocalWSURL          string        // local validator websocket url, defaults to LocalRPCURL with port + 1
 }
 
 // NewRPCClient creates a new client for the given solana cluster
@@ -80,12 +112,21 @@ func NewRPCClient(params NewClientParams) ClientInterface {
 	if avgSlotDuration <= 0 {
 		avgSlotDuration = 400 * time.Millisecond
 	}
+	localWSURL := params.LocalWSURL
+	if localWSURL == "" {
+		var err error
+		localWSURL, err = websocketURLFromRPCURL(params.LocalRPCURL)
+		if err != nil {
+			log.Debug("could not derive local websocket url - slot changes will be polled", "err", err)
+		}
+	}
 	return &Client{
 		localRPCClient:      rpc.New(params.LocalRPCURL),
 		networkRPCClient:    rpc.New(params.ClusterRPCURL),
 		loggerLocal:         log.With("rpc_client", "local"),
 		loggerNetwork:       log.With("rpc_client", "network"),
 		averageSlotDuration: avgSlotDuration,
+		localWSURL:          localWSURL,
 	}
 }
 
@@ -121,6 +162,15 @@ func (c *Client) GetLocalNodeVersion() (string, error) {
 	return result.SolanaCore, nil
 }
 
+// GetLocalNodeIdentity returns the identity pubkey the local validator reports via its getIdentity RPC call
+func (c *Client) GetLocalNodeIdentity() (string, error) {
+	result, err := c.localRPCClient.GetIdentity(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to get local node identity: %w", err)
+	}
+	return result.Identity.String(), nil
+}
+
 // NodeFromIP returns a Node from an IP address
 func (c *Client) NodeFromIP(ip string) (*Node, error) {
 	gossipNode, err := c.nodeFromIP(ip)
@@ -286,6 +336,15 @@ func (c *Client) GetCurrentSlot() (slot uint64, err error) {
 	return slot, nil
 }
 
+// GetEpochInfo returns the cluster's current epoch info
+func (c *Client) GetEpochInfo() (*rpc.GetEpochInfoResult, error) {
+	epochInfo, err := c.networkRPCClient.GetEpochInfo(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	return epochInfo, nil
+}
+
 // GetTimeToNextLeaderSlotForPubkey returns the time to the next leader slot for the given pubkey
 func (c *Client) GetTimeToNextLeaderSlotForPubkey(pubkey solanago.PublicKey) (isOnLeaderSchedule bool, timeToNextLeaderSlot time.Duration, err error) {
 	// get epoch information, includes the current slot (absolute slot) and its offset from the first slot of the epoch
@@ -346,9 +405,9 @@ func (c *Client) GetTimeToNextLeaderSlotForPubkey(pubkey solanago.PublicKey) (is
 		return false, time.Duration(0), nil
 	}
 
-	// Calculate time to next leader slot using slot difference and average slot time
+	// Calculate time to next leader slot using slot difference and the measured (or configured) slot time
 	slotDifference := nextLeaderSlot - epochInfo.AbsoluteSlot
-	timeToNextLeaderSlot = time.Duration(slotDifference) * c.averageSlotDuration
+	timeToNextLeaderSlot = time.Duration(slotDifference) * c.GetSlotDuration().Duration
 
 	c.loggerNetwork.Debug("Next leader slot",
 		"duration", timeToNextLeaderSlot.String(),
@@ -3,6 +3,7 @@ package solana
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -39,6 +40,16 @@ func (m *MockRPCClient) GetLeaderSchedule(ctx context.Context) (rpc.GetLeaderSch
 	return args.Get(0).(rpc.GetLeaderScheduleResult), args.Error(1)
 }
 
+func (m *MockRPCClient) GetLeaderScheduleWithOpts(ctx context.Context, opts *rpc.GetLeaderScheduleOpts) (rpc.GetLeaderScheduleResult, error) {
+	args := m.Called(ctx, opts)
+	return args.Get(0).(rpc.GetLeaderScheduleResult), args.Error(1)
+}
+
+func (m *MockRPCClient) GetRecentPerformanceSamples(ctx context.Context, limit *uint) ([]*rpc.GetRecentPerformanceSamplesResult, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*rpc.GetRecentPerformanceSamplesResult), args.Error(1)
+}
+
 func (m *MockRPCClient) GetHealth(ctx context.Context) (string, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(string), args.Error(1)
@@ -54,6 +65,18 @@ func (m *MockRPCClient) GetVersion(ctx context.Context) (*rpc.GetVersionResult,
 	return args.Get(0).(*rpc.GetVersionResult), args.Error(1)
 }
 
+func (m *MockRPCClient) GetIdentity(ctx context.Context) (*rpc.GetIdentityResult, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).(*rpc.GetIdentityResult)
+	return result, args.Error(1)
+}
+
+func (m *MockRPCClient) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error) {
+	args := m.Called(ctx, account, opts)
+	result, _ := args.Get(0).(*rpc.GetAccountInfoResult)
+	return result, args.Error(1)
+}
+
 // createTestClient creates a test client with mock RPC clients
 func createTestClient() (*Client, *MockRPCClient, *MockRPCClient) {
 	localMock := &MockRPCClient{}
@@ -699,6 +722,229 @@ func TestGossipClient_GetCurrentSlot_RPCError(t *testing.T) {
 	networkMock.AssertExpectations(t)
 }
 
+func TestGossipClient_GetLocalNodeSlotLag_Success(t *testing.T) {
+	// Create test client with mocks
+	client, localMock, networkMock := createTestClient()
+
+	// Setup mock expectations
+	localMock.On("GetSlot", mock.Anything, rpc.CommitmentProcessed).Return(uint64(1000), nil)
+	networkMock.On("GetSlot", mock.Anything, rpc.CommitmentProcessed).Return(uint64(1012), nil)
+
+	// Test the function
+	lag, err := client.GetLocalNodeSlotLag(rpc.CommitmentProcessed)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), lag.Behind())
+	assert.Equal(t, "12 slots behind (local 1000, cluster 1012, processed)", lag.String())
+
+	localMock.AssertExpectations(t)
+	networkMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetLocalNodeSlotLag_ClusterError(t *testing.T) {
+	// Create test client with mocks
+	client, localMock, networkMock := createTestClient()
+
+	// Setup mock expectations
+	localMock.On("GetSlot", mock.Anything, rpc.CommitmentConfirmed).Return(uint64(1000), nil)
+	networkMock.On("GetSlot", mock.Anything, rpc.CommitmentConfirmed).Return(uint64(0), errors.New("RPC connection failed"))
+
+	// Test the function
+	_, err := client.GetLocalNodeSlotLag(rpc.CommitmentConfirmed)
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get cluster slot")
+
+	localMock.AssertExpectations(t)
+	networkMock.AssertExpectations(t)
+}
+
+// jsonParsedVoteAccount returns jsonParsed vote account data with the given authorized voters by epoch
+func jsonParsedVoteAccount(t *testing.T, voters map[uint64]string) *rpc.DataBytesOrJSON {
+	t.Helper()
+	raw := `{"program":"vote","space":3762,"parsed":{"type":"vote","info":{"authorizedVoters":[`
+	first := true
+	for epoch, voter := range voters {
+		if !first {
+			raw += ","
+		}
+		first = false
+		raw += fmt.Sprintf(`{"authorizedVoter":%q,"epoch":%d}`, voter, epoch)
+	}
+	raw += `],"nodePubkey":"11111111111111111111111111111112"}}}`
+	data := &rpc.DataBytesOrJSON{}
+	require.NoError(t, data.UnmarshalJSON([]byte(raw)))
+	return data
+}
+
+func TestParseAuthorizedVoter(t *testing.T) {
+	current := "11111111111111111111111111111112"
+	next := "SysvarC1ock11111111111111111111111111111111"
+	data := jsonParsedVoteAccount(t, map[uint64]string{698: "SysvarRent111111111111111111111111111111111", 700: current, 701: next})
+
+	voter, err := parseAuthorizedVoter(data.GetRawJSON(), 700)
+	require.NoError(t, err)
+	assert.Equal(t, current, voter, "the voter for the current epoch, not one authorized for the next")
+
+	voter, err = parseAuthorizedVoter(data.GetRawJSON(), 705)
+	require.NoError(t, err)
+	assert.Equal(t, next, voter)
+
+	_, err = parseAuthorizedVoter(data.GetRawJSON(), 600)
+	assert.ErrorContains(t, err, "no authorized voter for epoch 600")
+
+	_, err = parseAuthorizedVoter([]byte(`{"program":"spl-token","parsed":{"type":"account","info":{}}}`), 700)
+	assert.ErrorContains(t, err, "not a vote account")
+
+	_, err = parseAuthorizedVoter(nil, 700)
+	assert.ErrorContains(t, err, "not jsonParsed")
+}
+
+func TestGossipClient_GetVoteAccountForIdentity_Delinquent(t *testing.T) {
+	// Create test client with mocks
+	client, _, networkMock := createTestClient()
+
+	// Setup mock expectations
+	identity := solana.MustPublicKeyFromBase58("11111111111111111111111111111112")
+	votePubkey := solana.MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+	networkMock.On("GetVoteAccounts", mock.Anything, mock.Anything).Return(&rpc.GetVoteAccountsResult{
+		Current:    []rpc.VoteAccountsResult{{VotePubkey: solana.NewWallet().PublicKey(), NodePubkey: solana.NewWallet().PublicKey()}},
+		Delinquent: []rpc.VoteAccountsResult{{VotePubkey: votePubkey, NodePubkey: identity, LastVote: 1000}},
+	}, nil)
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(&rpc.GetEpochInfoResult{Epoch: 700}, nil)
+	networkMock.On("GetAccountInfoWithOpts", mock.Anything, votePubkey, mock.Anything).Return(&rpc.GetAccountInfoResult{
+		Value: &rpc.Account{Data: jsonParsedVoteAccount(t, map[uint64]string{700: identity.String()})},
+	}, nil)
+
+	// Test the function
+	voteAccount, err := client.GetVoteAccountForIdentity(identity.String())
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, votePubkey.String(), voteAccount.VotePubkey)
+	assert.Equal(t, identity.String(), voteAccount.AuthorizedVoter)
+	assert.True(t, voteAccount.Delinquent)
+	assert.Equal(t, uint64(1000), voteAccount.LastVote)
+
+	networkMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetVoteAccountForIdentity_NotFound(t *testing.T) {
+	// Create test client with mocks
+	client, _, networkMock := createTestClient()
+
+	// Setup mock expectations
+	networkMock.On("GetVoteAccounts", mock.Anything, mock.Anything).Return(&rpc.GetVoteAccountsResult{}, nil)
+
+	// Test the function
+	_, err := client.GetVoteAccountForIdentity("11111111111111111111111111111112")
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrVoteAccountNotFound)
+
+	networkMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetVoteProgress_Success(t *testing.T) {
+	// Create test client with mocks
+	client, _, networkMock := createTestClient()
+
+	// Setup mock expectations
+	identity := solana.MustPublicKeyFromBase58("11111111111111111111111111111112")
+	networkMock.On("GetSlot", mock.Anything, rpc.CommitmentConfirmed).Return(uint64(1040), nil)
+	networkMock.On("GetVoteAccounts", mock.Anything, mock.Anything).Return(&rpc.GetVoteAccountsResult{
+		Current: []rpc.VoteAccountsResult{
+			{NodePubkey: solana.NewWallet().PublicKey(), EpochCredits: [][]int64{{699, 9000, 4000}, {700, 6000, 0}}},
+			{NodePubkey: identity, LastVote: 1038, RootSlot: 1006, EpochCredits: [][]int64{{700, 5500, 0}}},
+			{NodePubkey: solana.NewWallet().PublicKey(), EpochCredits: [][]int64{{700, 4000, 0}}},
+		},
+		Delinquent: []rpc.VoteAccountsResult{{NodePubkey: solana.NewWallet().PublicKey(), EpochCredits: [][]int64{{700, 10, 0}}}},
+	}, nil)
+
+	// Test the function
+	progress, err := client.GetVoteProgress(identity.String())
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1040), progress.Slot)
+	assert.Equal(t, uint64(1038), progress.LastVote)
+	assert.Equal(t, uint64(1006), progress.RootSlot)
+	assert.False(t, progress.Delinquent)
+	assert.Equal(t, int64(5500), progress.EpochCredits)
+	assert.Equal(t, int64(5500), progress.ClusterMedianCredits) // delinquent accounts excluded
+
+	networkMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetVoteProgress_Delinquent(t *testing.T) {
+	// Create test client with mocks
+	client, _, networkMock := createTestClient()
+
+	// Setup mock expectations
+	identity := solana.MustPublicKeyFromBase58("11111111111111111111111111111112")
+	networkMock.On("GetSlot", mock.Anything, rpc.CommitmentConfirmed).Return(uint64(1200), nil)
+	networkMock.On("GetVoteAccounts", mock.Anything, mock.Anything).Return(&rpc.GetVoteAccountsResult{
+		Current:    []rpc.VoteAccountsResult{{NodePubkey: solana.NewWallet().PublicKey(), EpochCredits: [][]int64{{700, 6000, 0}}}},
+		Delinquent: []rpc.VoteAccountsResult{{NodePubkey: identity, LastVote: 1000, RootSlot: 968}},
+	}, nil)
+
+	// Test the function
+	progress, err := client.GetVoteProgress(identity.String())
+
+	// Assertions
+	require.NoError(t, err)
+	assert.True(t, progress.Delinquent)
+	assert.Equal(t, uint64(1000), progress.LastVote)
+	assert.Equal(t, int64(0), progress.EpochCredits)
+	assert.Equal(t, int64(6000), progress.ClusterMedianCredits)
+
+	networkMock.AssertExpectations(t)
+}
+
+func TestSlotLag_BehindWhenAhead(t *testing.T) {
+	lag := SlotLag{LocalSlot: 1005, ClusterSlot: 1000}
+
+	assert.Equal(t, uint64(0), lag.Behind())
+}
+
+func TestGossipClient_GetLocalNodeIdentity_Success(t *testing.T) {
+	// Create test client with mocks
+	client, localMock, _ := createTestClient()
+
+	// Setup mock expectations
+	identity := solana.MustPublicKeyFromBase58("11111111111111111111111111111112")
+	localMock.On("GetIdentity", mock.Anything).Return(&rpc.GetIdentityResult{Identity: identity}, nil)
+
+	// Test the function
+	pubkey, err := client.GetLocalNodeIdentity()
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, identity.String(), pubkey)
+
+	localMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetLocalNodeIdentity_RPCError(t *testing.T) {
+	// Create test client with mocks
+	client, localMock, _ := createTestClient()
+
+	// Setup mock expectations
+	localMock.On("GetIdentity", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	// Test the function
+	pubkey, err := client.GetLocalNodeIdentity()
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Empty(t, pubkey)
+	assert.Contains(t, err.Error(), "failed to get local node identity")
+
+	localMock.AssertExpectations(t)
+}
+
 func TestGossipClient_GetLocalNodeHealth_Success(t *testing.T) {
 	// Create test client with mocks
 	client, localMock, _ := createTestClient()
@@ -944,6 +1190,8 @@ func TestGossipClient_GetTimeToNextLeaderSlotForPubkey_Success(t *testing.T) {
 
 	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
 	networkMock.On("GetLeaderSchedule", mock.Anything).Return(leaderSchedule, nil)
+	// no performance samples - falls back to the configured slot time
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).Return([]*rpc.GetRecentPerformanceSamplesResult{}, nil)
 
 	// Test the function
 	isOnSchedule, timeToNext, err := client.GetTimeToNextLeaderSlotForPubkey(pubkey)
@@ -988,6 +1236,8 @@ func TestGossipClient_GetTimeToNextLeaderSlotForPubkey_CustomSlotTime(t *testing
 
 	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
 	networkMock.On("GetLeaderSchedule", mock.Anything).Return(leaderSchedule, nil)
+	// no performance samples - falls back to the configured slot time
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).Return([]*rpc.GetRecentPerformanceSamplesResult{}, nil)
 
 	// Test the function
 	isOnSchedule, timeToNext, err := client.GetTimeToNextLeaderSlotForPubkey(pubkey)
@@ -1162,3 +1412,227 @@ func BenchmarkGossipClient_GetTimeToNextLeaderSlotForPubkey(b *testing.B) {
 		_, _, _ = gossipClient.GetTimeToNextLeaderSlotForPubkey(pubkey)
 	}
 }
+
+// leaderScheduleForEpoch matches GetLeaderScheduleWithOpts calls for the epoch containing slot
+func leaderScheduleForEpoch(slot uint64) any {
+	return mock.MatchedBy(func(opts *rpc.GetLeaderScheduleOpts) bool {
+		return opts != nil && opts.Epoch != nil && *opts.Epoch == slot
+	})
+}
+
+func TestGossipClient_GetLeaderFreeWindows_Success(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	// epoch 1 spans slots 900-1099, current slot 1000
+	epochInfo := &rpc.GetEpochInfoResult{
+		Epoch:        1,
+		SlotIndex:    100,
+		SlotsInEpoch: 200,
+		AbsoluteSlot: 1000,
+	}
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
+	// leader slots 950 (past), 1010-1013 and 1050 in this epoch
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, leaderScheduleForEpoch(900)).
+		Return(rpc.GetLeaderScheduleResult{pubkey: []uint64{50, 110, 111, 112, 113, 150}}, nil)
+	// leader slot 1150 in the next epoch
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, leaderScheduleForEpoch(1100)).
+		Return(rpc.GetLeaderScheduleResult{pubkey: []uint64{50}}, nil)
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).
+		Return([]*rpc.GetRecentPerformanceSamplesResult{}, nil)
+
+	windows, err := client.GetLeaderFreeWindows(pubkey)
+	require.NoError(t, err)
+
+	assert.True(t, windows.IsOnLeaderSchedule)
+	assert.Equal(t, uint64(1000), windows.CurrentSlot)
+	assert.Equal(t, uint64(1299), windows.ScanEndSlot)
+	assert.Equal(t, 400*time.Millisecond, windows.SlotDuration.Duration)
+
+	require.Len(t, windows.Windows, 4)
+	expected := [][2]uint64{{1000, 1009}, {1014, 1049}, {1051, 1149}, {1151, 1299}}
+	for i, w := range windows.Windows {
+		assert.Equal(t, expected[i][0], w.StartSlot, "window %d start", i)
+		assert.Equal(t, expected[i][1], w.EndSlot, "window %d end", i)
+	}
+	assert.Equal(t, 36*400*time.Millisecond, windows.Windows[1].Duration())
+
+	largest := windows.Largest(2)
+	require.Len(t, largest, 2)
+	assert.Equal(t, uint64(1151), largest[0].StartSlot)
+	assert.Equal(t, uint64(1051), largest[1].StartSlot)
+
+	networkMock.AssertExpectations(t)
+}
+
+func TestGossipClient_GetLeaderFreeWindows_NextEpochUnavailable(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	epochInfo := &rpc.GetEpochInfoResult{
+		Epoch:        1,
+		SlotIndex:    100,
+		SlotsInEpoch: 200,
+		AbsoluteSlot: 1000,
+	}
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, leaderScheduleForEpoch(900)).
+		Return(rpc.GetLeaderScheduleResult{pubkey: []uint64{150}}, nil)
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, leaderScheduleForEpoch(1100)).
+		Return(rpc.GetLeaderScheduleResult(nil), errors.New("leader schedule not yet available"))
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).
+		Return([]*rpc.GetRecentPerformanceSamplesResult{}, nil)
+
+	windows, err := client.GetLeaderFreeWindows(pubkey)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1099), windows.ScanEndSlot)
+	require.Len(t, windows.Windows, 2)
+	assert.Equal(t, uint64(1049), windows.Windows[0].EndSlot)
+	assert.Equal(t, uint64(1051), windows.Windows[1].StartSlot)
+	assert.Equal(t, uint64(1099), windows.Windows[1].EndSlot)
+}
+
+func TestGossipClient_GetLeaderFreeWindows_NotOnSchedule(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	epochInfo := &rpc.GetEpochInfoResult{
+		Epoch:        1,
+		SlotIndex:    100,
+		SlotsInEpoch: 200,
+		AbsoluteSlot: 1000,
+	}
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, mock.Anything).Return(rpc.GetLeaderScheduleResult{}, nil)
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).
+		Return([]*rpc.GetRecentPerformanceSamplesResult{}, nil)
+
+	windows, err := client.GetLeaderFreeWindows(pubkey)
+	require.NoError(t, err)
+
+	assert.False(t, windows.IsOnLeaderSchedule)
+	require.Len(t, windows.Windows, 1)
+	assert.Equal(t, uint64(1000), windows.Windows[0].StartSlot)
+	assert.Equal(t, uint64(1299), windows.Windows[0].EndSlot)
+}
+
+func TestGossipClient_GetLeaderFreeWindows_GetLeaderScheduleError(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(&rpc.GetEpochInfoResult{AbsoluteSlot: 1000}, nil)
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, mock.Anything).
+		Return(rpc.GetLeaderScheduleResult(nil), errors.New("rpc error"))
+
+	windows, err := client.GetLeaderFreeWindows(pubkey)
+	assert.Error(t, err)
+	assert.Nil(t, windows)
+	assert.Contains(t, err.Error(), "failed to get leader schedule")
+}
+
+func TestGossipClient_GetLeaderFreeWindows_MeasuredSlotDuration(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(&rpc.GetEpochInfoResult{
+		SlotsInEpoch: 200,
+		AbsoluteSlot: 0,
+	}, nil)
+	networkMock.On("GetLeaderScheduleWithOpts", mock.Anything, mock.Anything).Return(rpc.GetLeaderScheduleResult{}, nil)
+	// 400 slots over 180s of samples = 450ms per slot
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).Return([]*rpc.GetRecentPerformanceSamplesResult{
+		{NumSlots: 300, SamplePeriodSecs: 120},
+		{NumSlots: 100, SamplePeriodSecs: 60},
+	}, nil)
+
+	windows, err := client.GetLeaderFreeWindows(pubkey)
+	require.NoError(t, err)
+	assert.Equal(t, 450*time.Millisecond, windows.SlotDuration.Duration)
+}
+
+func TestLeaderFreeWindows_NextAtLeast(t *testing.T) {
+	now := time.Now()
+	windows := &LeaderFreeWindows{
+		Windows: []LeaderFreeWindow{
+			// in progress: 20m long but only 2m left
+			{StartSlot: 1, EndSlot: 10, StartTime: now.Add(-18 * time.Minute), EndTime: now.Add(2 * time.Minute)},
+			{StartSlot: 12, EndSlot: 20, StartTime: now.Add(3 * time.Minute), EndTime: now.Add(8 * time.Minute)},
+			{StartSlot: 22, EndSlot: 40, StartTime: now.Add(9 * time.Minute), EndTime: now.Add(30 * time.Minute)},
+		},
+	}
+
+	w, found := windows.NextAtLeast(time.Minute, now)
+	require.True(t, found)
+	assert.Equal(t, uint64(1), w.StartSlot)
+
+	w, found = windows.NextAtLeast(5*time.Minute, now)
+	require.True(t, found)
+	assert.Equal(t, uint64(12), w.StartSlot)
+
+	w, found = windows.NextAtLeast(10*time.Minute, now)
+	require.True(t, found)
+	assert.Equal(t, uint64(22), w.StartSlot)
+
+	_, found = windows.NextAtLeast(time.Hour, now)
+	assert.False(t, found)
+}
+
+func TestGossipClient_GetTimeToNextLeaderSlotForPubkey_MeasuredSlotTime(t *testing.T) {
+	client, _, networkMock := createTestClient()
+	pubkey := createTestPublicKey(1)
+
+	epochInfo := &rpc.GetEpochInfoResult{
+		Epoch:        1,
+		SlotIndex:    100,
+		AbsoluteSlot: 1000,
+	}
+
+	networkMock.On("GetEpochInfo", mock.Anything, rpc.CommitmentConfirmed).Return(epochInfo, nil)
+	networkMock.On("GetLeaderSchedule", mock.Anything).Return(rpc.GetLeaderScheduleResult{pubkey: []uint64{150}}, nil)
+	// cluster running slow: 100 slots per 60s sample = 600ms slots
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).Return([]*rpc.GetRecentPerformanceSamplesResult{
+		{NumSlots: 100, SamplePeriodSecs: 60},
+		{NumSlots: 100, SamplePeriodSecs: 60},
+	}, nil)
+
+	isOnSchedule, timeToNext, err := client.GetTimeToNextLeaderSlotForPubkey(pubkey)
+	require.NoError(t, err)
+	assert.True(t, isOnSchedule)
+	// 50 slots * 600ms rather than 50 slots * 400ms configured
+	assert.Equal(t, 30*time.Second, timeToNext)
+}
+
+func TestGossipClient_GetSlotDuration_CachesMeasurement(t *testing.T) {
+	client, _, networkMock := createTestClient()
+
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).Return([]*rpc.GetRecentPerformanceSamplesResult{
+		{NumSlots: 125, SamplePeriodSecs: 60},
+	}, nil).Once()
+
+	first := client.GetSlotDuration()
+	second := client.GetSlotDuration()
+
+	assert.Equal(t, SlotDuration{Duration: 480 * time.Millisecond, Source: SlotDurationSourceMeasured, Samples: 1}, first)
+	assert.Equal(t, first, second)
+	networkMock.AssertNumberOfCalls(t, "GetRecentPerformanceSamples", 1)
+}
+
+func TestGossipClient_GetSlotDuration_FallsBackToConfigured(t *testing.T) {
+	client, _, networkMock := createTestClient()
+
+	networkMock.On("GetRecentPerformanceSamples", mock.Anything, mock.Anything).
+		Return([]*rpc.GetRecentPerformanceSamplesResult(nil), errors.New("method not found"))
+
+	got := client.GetSlotDuration()
+	assert.Equal(t, 400*time.Millisecond, got.Duration)
+	assert.Equal(t, SlotDurationSourceConfigured, got.Source)
+	assert.Equal(t, "400ms (configured average_slot_duration)", got.String())
+
+	// failures are not cached so the next call measures again
+	client.GetSlotDuration()
+	networkMock.AssertNumberOfCalls(t, "GetRecentPerformanceSamples", 2)
+}
@@ -0,0 +1,145 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// LeaderFreeWindow is a run of consecutive slots in which a validator is not scheduled to lead
+type LeaderFreeWindow struct {
+	StartSlot uint64    // first leader-free slot
+	EndSlot   uint64    // last leader-free slot (inclusive)
+	StartTime time.Time // estimated wall-clock time StartSlot begins
+	EndTime   time.Time // estimated wall-clock time EndSlot ends
+}
+
+// Slots returns the number of slots in the window
+func (w LeaderFreeWindow) Slots() uint64 {
+	return w.EndSlot - w.StartSlot + 1
+}
+
+// Duration returns the estimated wall-clock length of the window
+func (w LeaderFreeWindow) Duration() time.Duration {
+	return w.EndTime.Sub(w.StartTime)
+}
+
+// LeaderFreeWindows are the leader-free windows for a pubkey from the current slot to the end of
+// the scanned leader schedule, in chronological order
+type LeaderFreeWindows struct {
+	Pubkey             string
+	CurrentSlot        uint64
+	ScanEndSlot        uint64 // last slot covered by the scanned leader schedules
+	SlotDuration       SlotDuration
+	IsOnLeaderSchedule bool
+	Windows            []LeaderFreeWindow
+}
+
+// Largest returns up to n windows sorted by length, longest first
+func (lw *LeaderFreeWindows) Largest(n int) []LeaderFreeWindow {
+	sorted := slices.Clone(lw.Windows)
+	slices.SortStableFunc(sorted, func(a, b LeaderFreeWindow) int {
+		return int(b.Slots()) - int(a.Slots())
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// NextAtLeast returns the first window, in chronological order, with at least minDuration of
+// leader-free time left in it. A window already in progress counts only its remaining time.
+func (lw *LeaderFreeWindows) NextAtLeast(minDuration time.Duration, now time.Time) (window LeaderFreeWindow, found bool) {
+	for _, w := range lw.Windows {
+		start := w.StartTime
+		if now.After(start) {
+			start = now
+		}
+		if w.EndTime.Sub(start) >= minDuration {
+			return w, true
+		}
+	}
+	return LeaderFreeWindow{}, false
+}
+
+// GetLeaderFreeWindows scans the current and next epoch's leader schedule for the given pubkey
+// and returns the leader-free windows from the current slot onwards. The next epoch's schedule
+// is best-effort - if the cluster RPC can't provide it only the current epoch is scanned.
+func (c *Client) GetLeaderFreeWindows(pubkey solanago.PublicKey) (*LeaderFreeWindows, error) {
+	epochInfo, err := c.networkRPCClient.GetEpochInfo(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	now := time.Now()
+
+	firstSlotOfEpoch := epochInfo.AbsoluteSlot - epochInfo.SlotIndex
+	firstSlotOfNextEpoch := firstSlotOfEpoch + epochInfo.SlotsInEpoch
+
+	currentSchedule, err := c.networkRPCClient.GetLeaderScheduleWithOpts(context.Background(), &rpc.GetLeaderScheduleOpts{
+		Epoch:    &firstSlotOfEpoch,
+		Identity: &pubkey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leader schedule for epoch %d: %w", epochInfo.Epoch, err)
+	}
+
+	var leaderSlots []uint64
+	for _, slotIndex := range currentSchedule[pubkey] {
+		leaderSlots = append(leaderSlots, firstSlotOfEpoch+slotIndex)
+	}
+
+	scanEndSlot := firstSlotOfNextEpoch - 1
+	nextSchedule, err := c.networkRPCClient.GetLeaderScheduleWithOpts(context.Background(), &rpc.GetLeaderScheduleOpts{
+		Epoch:    &firstSlotOfNextEpoch,
+		Identity: &pubkey,
+	})
+	if err != nil {
+		c.loggerNetwork.Debug("next epoch leader schedule not available - scanning current epoch only", "err", err)
+	} else {
+		scanEndSlot = firstSlotOfNextEpoch + epochInfo.SlotsInEpoch - 1
+		for _, slotIndex := range nextSchedule[pubkey] {
+			leaderSlots = append(leaderSlots, firstSlotOfNextEpoch+slotIndex)
+		}
+	}
+	slices.Sort(leaderSlots)
+
+	result := &LeaderFreeWindows{
+		Pubkey:             pubkey.String(),
+		CurrentSlot:        epochInfo.AbsoluteSlot,
+		ScanEndSlot:        scanEndSlot,
+		SlotDuration:       c.GetSlotDuration(),
+		IsOnLeaderSchedule: len(leaderSlots) > 0,
+	}
+
+	slotTime := func(slot uint64) time.Time {
+		return now.Add(time.Duration(int64(slot)-int64(result.CurrentSlot)) * result.SlotDuration.Duration)
+	}
+	addWindow := func(start, end uint64) {
+		result.Windows = append(result.Windows, LeaderFreeWindow{
+			StartSlot: start,
+			EndSlot:   end,
+			StartTime: slotTime(start),
+			EndTime:   slotTime(end + 1),
+		})
+	}
+
+	cursor := result.CurrentSlot
+	for _, leaderSlot := range leaderSlots {
+		if leaderSlot < cursor {
+			continue
+		}
+		if leaderSlot > cursor {
+			addWindow(cursor, leaderSlot-1)
+		}
+		cursor = leaderSlot + 1
+	}
+	if cursor <= scanEndSlot {
+		addWindow(cursor, scanEndSlot)
+	}
+
+	return result, nil
+}
@@ -1,9 +1,11 @@
 package solana
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 )
@@ -23,15 +25,28 @@ type MockClient struct {
 
 	// Vote account methods
 	getCreditRankedVoteAccountFromPubkey func(pubkey string) (*rpc.VoteAccountsResult, int, error)
+	getVoteAccountForIdentity            func(identity string) (*VoteAccount, error)
+	getVoteProgress                      func(identity string) (*VoteProgress, error)
 
 	// Slot methods
 	getCurrentSlot func() (uint64, error)
+	getEpochInfo   func() (*rpc.GetEpochInfoResult, error)
 
 	// Leader schedule methods
 	getTimeToNextLeaderSlotForPubkey func(pubkey solana.PublicKey) (bool, time.Duration, error)
+	getLeaderFreeWindows             func(pubkey solana.PublicKey) (*LeaderFreeWindows, error)
+	getSlotDuration                  func() SlotDuration
+	newSlotSource                    func(ctx context.Context) SlotSource
 
 	// Version methods
 	getLocalNodeVersion func() (string, error)
+
+	// Identity methods
+	getLocalNodeIdentity func() (string, error)
+
+	// Local slot methods
+	getLocalNodeSlot    func(commitment rpc.CommitmentType) (uint64, error)
+	getLocalNodeSlotLag func(commitment rpc.CommitmentType) (SlotLag, error)
 }
 
 // NewMockClient creates a new mock client with default behaviors
@@ -90,24 +105,66 @@ func (m *MockClient) WithGetCreditRankedVoteAccountFromPubkey(fn func(pubkey str
 	return m
 }
 
+// WithGetVoteAccountForIdentity sets a custom GetVoteAccountForIdentity function
+func (m *MockClient) WithGetVoteAccountForIdentity(fn func(identity string) (*VoteAccount, error)) *MockClient {
+	m.getVoteAccountForIdentity = fn
+	return m
+}
+
+// WithGetVoteProgress sets a custom GetVoteProgress function
+func (m *MockClient) WithGetVoteProgress(fn func(identity string) (*VoteProgress, error)) *MockClient {
+	m.getVoteProgress = fn
+	return m
+}
+
 // WithGetCurrentSlot sets a custom GetCurrentSlot function
 func (m *MockClient) WithGetCurrentSlot(fn func() (uint64, error)) *MockClient {
 	m.getCurrentSlot = fn
 	return m
 }
 
+// WithGetEpochInfo sets a custom GetEpochInfo function
+func (m *MockClient) WithGetEpochInfo(fn func() (*rpc.GetEpochInfoResult, error)) *MockClient {
+	m.getEpochInfo = fn
+	return m
+}
+
 // WithGetTimeToNextLeaderSlotForPubkey sets a custom GetTimeToNextLeaderSlotForPubkey function
 func (m *MockClient) WithGetTimeToNextLeaderSlotForPubkey(fn func(pubkey solana.PublicKey) (bool, time.Duration, error)) *MockClient {
 	m.getTimeToNextLeaderSlotForPubkey = fn
 	return m
 }
 
+// WithGetLeaderFreeWindows sets a custom GetLeaderFreeWindows function
+func (m *MockClient) WithGetLeaderFreeWindows(fn func(pubkey solana.PublicKey) (*LeaderFreeWindows, error)) *MockClient {
+	m.getLeaderFreeWindows = fn
+	return m
+}
+
+// WithGetSlotDuration sets a custom GetSlotDuration function
+func (m *MockClient) WithGetSlotDuration(fn func() SlotDuration) *MockClient {
+	m.getSlotDuration = fn
+	return m
+}
+
+// WithNewSlotSource sets a custom NewSlotSource function
+func (m *MockClient) WithNewSlotSource(fn func(ctx context.Context) SlotSource) *MockClient {
+	m.newSlotSource = fn
+	return m
+}
+
 // WithGetLocalNodeVersion sets a custom GetLocalNodeVersion function
 func (m *MockClient) WithGetLocalNodeVersion(fn func() (string, error)) *MockClient {
 	m.getLocalNodeVersion = fn
 	return m
 }
 
+// WithGetLocalNodeIdentity sets a custom GetLocalNodeIdentity function
+func (m *MockClient) WithGetLocalNodeIdentity(fn func() (string, error)) *MockClient {
+	m.getLocalNodeIdentity = fn
+	return m
+}
+
 // WithMockNode sets the mock node
 func (m *MockClient) WithMockNode(node *Node) *MockClient {
 	m.mockNode = node
@@ -154,6 +211,39 @@ func (m *MockClient) GetCurrentSlot() (uint64, error) {
 	return 0, nil
 }
 
+// GetVoteAccountForIdentity implements ClientInterface.GetVoteAccountForIdentity, a current vote
+// account with the identity as its authorized voter by default
+func (m *MockClient) GetVoteAccountForIdentity(identity string) (*VoteAccount, error) {
+	if m.getVoteAccountForIdentity != nil {
+		return m.getVoteAccountForIdentity(identity)
+	}
+	return &VoteAccount{
+		VotePubkey:      "Vote111111111111111111111111111111111111111",
+		NodePubkey:      identity,
+		AuthorizedVoter: identity,
+	}, nil
+}
+
+// GetVoteProgress implements ClientInterface.GetVoteProgress, voting in step with the current slot by default
+func (m *MockClient) GetVoteProgress(identity string) (*VoteProgress, error) {
+	if m.getVoteProgress != nil {
+		return m.getVoteProgress(identity)
+	}
+	slot, err := m.GetCurrentSlot()
+	if err != nil {
+		return nil, err
+	}
+	return &VoteProgress{Slot: slot, LastVote: slot, RootSlot: slot}, nil
+}
+
+// GetEpochInfo implements ClientInterface.GetEpochInfo
+func (m *MockClient) GetEpochInfo() (*rpc.GetEpochInfoResult, error) {
+	if m.getEpochInfo != nil {
+		return m.getEpochInfo()
+	}
+	return &rpc.GetEpochInfoResult{}, nil
+}
+
 // GetTimeToNextLeaderSlotForPubkey implements ClientInterface.GetTimeToNextLeaderSlotForPubkey
 func (m *MockClient) GetTimeToNextLeaderSlotForPubkey(pubkey solana.PublicKey) (bool, time.Duration, error) {
 	if m.getTimeToNextLeaderSlotForPubkey != nil {
@@ -162,6 +252,30 @@ func (m *MockClient) GetTimeToNextLeaderSlotForPubkey(pubkey solana.PublicKey) (
 	return false, 0, nil
 }
 
+// GetLeaderFreeWindows implements ClientInterface.GetLeaderFreeWindows
+func (m *MockClient) GetLeaderFreeWindows(pubkey solana.PublicKey) (*LeaderFreeWindows, error) {
+	if m.getLeaderFreeWindows != nil {
+		return m.getLeaderFreeWindows(pubkey)
+	}
+	return &LeaderFreeWindows{Pubkey: pubkey.String()}, nil
+}
+
+// GetSlotDuration implements ClientInterface.GetSlotDuration
+func (m *MockClient) GetSlotDuration() SlotDuration {
+	if m.getSlotDuration != nil {
+		return m.getSlotDuration()
+	}
+	return SlotDuration{Duration: 400 * time.Millisecond, Source: SlotDurationSourceConfigured}
+}
+
+// NewSlotSource implements ClientInterface.NewSlotSource, polling GetCurrentSlot by default
+func (m *MockClient) NewSlotSource(ctx context.Context) SlotSource {
+	if m.newSlotSource != nil {
+		return m.newSlotSource(ctx)
+	}
+	return NewPollingSlotSource(m.GetCurrentSlot, log.Default())
+}
+
 // GetLocalNodeHealth implements ClientInterface.GetLocalNodeHealth
 func (m *MockClient) GetLocalNodeHealth() (string, error) {
 	if m.getLocalNodeHealth != nil {
@@ -190,6 +304,43 @@ func (m *MockClient) GetLocalNodeVersion() (string, error) {
 	return m.mockNode.Version(), nil
 }
 
+// GetLocalNodeIdentity implements ClientInterface.GetLocalNodeIdentity
+func (m *MockClient) GetLocalNodeIdentity() (string, error) {
+	if m.getLocalNodeIdentity != nil {
+		return m.getLocalNodeIdentity()
+	}
+	return m.mockNode.PubKey(), nil
+}
+
+// WithGetLocalNodeSlot sets a custom GetLocalNodeSlot function
+func (m *MockClient) WithGetLocalNodeSlot(fn func(commitment rpc.CommitmentType) (uint64, error)) *MockClient {
+	m.getLocalNodeSlot = fn
+	return m
+}
+
+// WithGetLocalNodeSlotLag sets a custom GetLocalNodeSlotLag function
+func (m *MockClient) WithGetLocalNodeSlotLag(fn func(commitment rpc.CommitmentType) (SlotLag, error)) *MockClient {
+	m.getLocalNodeSlotLag = fn
+	return m
+}
+
+// GetLocalNodeSlot implements ClientInterface.GetLocalNodeSlot
+func (m *MockClient) GetLocalNodeSlot(commitment rpc.CommitmentType) (uint64, error) {
+	if m.getLocalNodeSlot != nil {
+		return m.getLocalNodeSlot(commitment)
+	}
+	return m.GetCurrentSlot()
+}
+
+// GetLocalNodeSlotLag implements ClientInterface.GetLocalNodeSlotLag, level with the cluster by default
+func (m *MockClient) GetLocalNodeSlotLag(commitment rpc.CommitmentType) (SlotLag, error) {
+	if m.getLocalNodeSlotLag != nil {
+		return m.getLocalNodeSlotLag(commitment)
+	}
+	slot, err := m.GetCurrentSlot()
+	return SlotLag{Commitment: commitment, LocalSlot: slot, ClusterSlot: slot}, err
+}
+
 // Helper function to create a string pointer
 func stringPtr(s string) *string {
 	return &s
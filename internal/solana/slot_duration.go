@@ -0,0 +1,85 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// SlotDurationSourceMeasured means the slot duration was measured from recent performance samples
+	SlotDurationSourceMeasured = "measured"
+	// SlotDurationSourceConfigured means the configured average_slot_duration was used
+	SlotDurationSourceConfigured = "configured"
+
+	// performanceSamplesLimit is the number of (60s) performance samples used to measure slot time
+	performanceSamplesLimit = 30
+	// slotDurationCacheTTL is how long a measured slot duration is reused before re-measuring -
+	// the cluster only records a new performance sample every 60s
+	slotDurationCacheTTL = time.Minute
+)
+
+// SlotDuration is an estimate of the cluster's current slot time and where it came from
+type SlotDuration struct {
+	Duration time.Duration
+	Source   string // SlotDurationSourceMeasured or SlotDurationSourceConfigured
+	Samples  int    // number of performance samples measured, 0 when configured
+}
+
+// String returns the estimate and its source e.g. 452ms (measured over 30 performance samples)
+func (d SlotDuration) String() string {
+	if d.Source == SlotDurationSourceMeasured {
+		return fmt.Sprintf("%s (measured over %d performance samples)", d.Duration, d.Samples)
+	}
+	return fmt.Sprintf("%s (configured average_slot_duration)", d.Duration)
+}
+
+// GetSlotDuration returns the cluster's slot time measured from getRecentPerformanceSamples, falling
+// back to the configured average slot duration when samples are unavailable. Measurements are
+// cached for a minute.
+func (c *Client) GetSlotDuration() SlotDuration {
+	c.slotDurationMu.Lock()
+	defer c.slotDurationMu.Unlock()
+
+	if !c.slotDurationMeasuredAt.IsZero() && time.Since(c.slotDurationMeasuredAt) < slotDurationCacheTTL {
+		return c.slotDuration
+	}
+
+	measured, err := c.measureSlotDuration()
+	if err != nil {
+		c.loggerNetwork.Debug("failed to measure slot duration - using configured average_slot_duration", "err", err, "average_slot_duration", c.averageSlotDuration)
+		return SlotDuration{
+			Duration: c.averageSlotDuration,
+			Source:   SlotDurationSourceConfigured,
+		}
+	}
+
+	c.loggerNetwork.Debug("measured slot duration", "slot_duration", measured)
+	c.slotDuration = measured
+	c.slotDurationMeasuredAt = time.Now()
+	return measured
+}
+
+// measureSlotDuration computes the average slot time over the most recent performance samples
+func (c *Client) measureSlotDuration() (SlotDuration, error) {
+	limit := uint(performanceSamplesLimit)
+	samples, err := c.networkRPCClient.GetRecentPerformanceSamples(context.Background(), &limit)
+	if err != nil {
+		return SlotDuration{}, fmt.Errorf("failed to get recent performance samples: %w", err)
+	}
+
+	var totalSlots, totalSecs uint64
+	for _, sample := range samples {
+		totalSlots += sample.NumSlots
+		totalSecs += uint64(sample.SamplePeriodSecs)
+	}
+	if totalSlots == 0 || totalSecs == 0 {
+		return SlotDuration{}, fmt.Errorf("no usable performance samples (%d returned)", len(samples))
+	}
+
+	return SlotDuration{
+		Duration: time.Duration(totalSecs) * time.Second / time.Duration(totalSlots),
+		Source:   SlotDurationSourceMeasured,
+		Samples:  len(samples),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// SlotLag is the local node's slot against the cluster's at the same commitment
+type SlotLag struct {
+	Commitment  rpc.CommitmentType
+	LocalSlot   uint64
+	ClusterSlot uint64
+}
+
+// Behind returns how many slots the local node is behind the cluster, 0 when level or ahead
+func (l SlotLag) Behind() uint64 {
+	if l.LocalSlot >= l.ClusterSlot {
+		return 0
+	}
+	return l.ClusterSlot - l.LocalSlot
+}
+
+// String returns the lag e.g. 12 slots behind (local 1000, cluster 1012, confirmed)
+func (l SlotLag) String() string {
+	return fmt.Sprintf("%d slots behind (local %d, cluster %d, %s)", l.Behind(), l.LocalSlot, l.ClusterSlot, l.Commitment)
+}
+
+// GetLocalNodeSlot returns the local validator's slot at the given commitment
+func (c *Client) GetLocalNodeSlot(commitment rpc.CommitmentType) (uint64, error) {
+	slot, err := c.localRPCClient.GetSlot(context.Background(), commitment)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get local node slot: %w", err)
+	}
+	return slot, nil
+}
+
+// GetLocalNodeSlotLag compares the local validator's slot against the cluster rpc's at the given
+// commitment. The cluster is queried second so that rpc latency can only overstate the lag.
+func (c *Client) GetLocalNodeSlotLag(commitment rpc.CommitmentType) (lag SlotLag, err error) {
+	lag.Commitment = commitment
+	lag.LocalSlot, err = c.GetLocalNodeSlot(commitment)
+	if err != nil {
+		return lag, err
+	}
+	lag.ClusterSlot, err = c.networkRPCClient.GetSlot(context.Background(), commitment)
+	if err != nil {
+		return lag, fmt.Errorf("failed to get cluster slot: %w", err)
+	}
+	return lag, nil
+}
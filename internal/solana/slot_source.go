@@ -0,0 +1,390 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+const (
+	// SlotSourceSlotsUpdates detects slots from the local validator's slotsUpdatesSubscribe stream
+	SlotSourceSlotsUpdates = "websocket slotsUpdatesSubscribe"
+	// SlotSourceSlot detects slots from the local validator's slotSubscribe stream
+	SlotSourceSlot = "websocket slotSubscribe"
+	// SlotSourcePolling detects slots by polling getSlot
+	SlotSourcePolling = "polling getSlot"
+
+	// slotPollInterval is how often the polling source calls getSlot. getSlot is lightweight so
+	// 10ms polling is cheap and gives ~5ms average detection lag.
+	slotPollInterval = 10 * time.Millisecond
+	// slotPollErrorRetryInterval backs off on RPC errors to avoid hammering a struggling node
+	slotPollErrorRetryInterval = 50 * time.Millisecond
+	// slotSubscriptionProbeTimeout is how long to wait for a subscription's first notification
+	// before deciding the local validator doesn't support it - several slots' worth
+	slotSubscriptionProbeTimeout = 2 * time.Second
+)
+
+// SlotSource detects the start of new slots
+type SlotSource interface {
+	// WaitForNextSlot blocks until a slot later than the current one is seen and returns it
+	WaitForNextSlot(ctx context.Context) (SlotTransition, error)
+	// Name returns the kind of source e.g. SlotSourceSlotsUpdates
+	Name() string
+	// Close releases any connections held by the source
+	Close()
+}
+
+// SlotTransition is a detected change to a new slot
+type SlotTransition struct {
+	Slot       uint64
+	Source     string
+	DetectedAt time.Time
+	// DetectionLag is how long after the slot started it was detected. For slotsUpdatesSubscribe
+	// it is measured from the validator's first-shred timestamp; for polling it is an upper bound
+	// (the gap since the previous poll); slotSubscribe carries no timing so it is unknown.
+	DetectionLag      time.Duration
+	DetectionLagKnown bool
+}
+
+// String describes how and how quickly the slot was detected
+func (t SlotTransition) String() string {
+	switch {
+	case t.Source == SlotSourceSlotsUpdates && t.DetectionLagKnown:
+		return fmt.Sprintf("%s after first shred (%s)", t.DetectionLag.Round(time.Millisecond), t.Source)
+	case t.Source == SlotSourcePolling && t.DetectionLagKnown:
+		return fmt.Sprintf("within %s (%s)", t.DetectionLag.Round(time.Millisecond), t.Source)
+	default:
+		return fmt.Sprintf("lag unknown (%s)", t.Source)
+	}
+}
+
+// NewSlotSource returns a websocket slot source against the local validator, preferring
+// slotsUpdatesSubscribe over slotSubscribe. If neither subscription produces notifications the
+// source polls GetCurrentSlot instead. The returned source must be closed.
+func (c *Client) NewSlotSource(ctx context.Context) SlotSource {
+	fallback := NewPollingSlotSource(c.GetCurrentSlot, c.loggerLocal)
+
+	if c.localWSURL == "" {
+		c.loggerLocal.Debug("no local websocket url - polling for slot changes")
+		return fallback
+	}
+
+	source, err := newWebsocketSlotSource(ctx, c.localWSURL, fallback, c.loggerLocal)
+	if err != nil {
+		c.loggerLocal.Warn("websocket slot subscriptions unavailable - polling for slot changes instead", "ws_url", c.localWSURL, "err", err)
+		return fallback
+	}
+
+	c.loggerLocal.Debug("slot source ready", "source", source.Name(), "ws_url", c.localWSURL)
+	return source
+}
+
+// pollingSlotSource detects slot changes by polling
+type pollingSlotSource struct {
+	getSlot func() (uint64, error)
+	logger  *log.Logger
+}
+
+// NewPollingSlotSource returns a SlotSource that polls getSlot, logging through logger
+func NewPollingSlotSource(getSlot func() (uint64, error), logger *log.Logger) SlotSource {
+	return &pollingSlotSource{getSlot: getSlot, logger: logger}
+}
+
+// Name implements SlotSource.Name
+func (p *pollingSlotSource) Name() string {
+	return SlotSourcePolling
+}
+
+// Close implements SlotSource.Close
+func (p *pollingSlotSource) Close() {}
+
+// WaitForNextSlot implements SlotSource.WaitForNextSlot
+func (p *pollingSlotSource) WaitForNextSlot(ctx context.Context) (SlotTransition, error) {
+	currentSlot, err := p.getSlot()
+	if err != nil {
+		return SlotTransition{}, fmt.Errorf("failed to get current slot: %w", err)
+	}
+	lastPollAt := time.Now()
+
+	for {
+		slot, err := p.getSlot()
+		polledAt := time.Now()
+		switch {
+		case err != nil:
+			p.logger.Debug("failed to get slot, retrying", "err", err)
+		case slot > currentSlot:
+			return SlotTransition{
+				Slot:              slot,
+				Source:            SlotSourcePolling,
+				DetectedAt:        polledAt,
+				DetectionLag:      polledAt.Sub(lastPollAt),
+				DetectionLagKnown: true,
+			}, nil
+		default:
+			lastPollAt = polledAt
+		}
+
+		interval := slotPollInterval
+		if err != nil {
+			interval = slotPollErrorRetryInterval
+		}
+		select {
+		case <-ctx.Done():
+			return SlotTransition{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// websocketSlotSource follows slot notifications from the local validator. A background goroutine
+// keeps the latest transition so waiting never has to drain a backlog of stale notifications.
+type websocketSlotSource struct {
+	name     string
+	wsClient *ws.Client
+	cancel   context.CancelFunc
+	fallback SlotSource
+	logger   *log.Logger
+
+	mu      sync.Mutex
+	latest  SlotTransition
+	err     error
+	changed chan struct{} // signalled (non-blocking) whenever latest or err changes
+}
+
+// newWebsocketSlotSource connects to wsURL and returns a source for the first subscription that
+// produces a notification within slotSubscriptionProbeTimeout
+func newWebsocketSlotSource(ctx context.Context, wsURL string, fallback SlotSource, logger *log.Logger) (*websocketSlotSource, error) {
+	connectCtx, cancelConnect := context.WithTimeout(ctx, slotSubscriptionProbeTimeout)
+	defer cancelConnect()
+
+	wsClient, err := ws.Connect(connectCtx, wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	for _, subscribe := range []func(s *websocketSlotSource) (func(), error){
+		(*websocketSlotSource).followSlotsUpdates,
+		(*websocketSlotSource).followSlots,
+	} {
+		pumpCtx, cancel := context.WithCancel(ctx)
+		s := &websocketSlotSource{
+			wsClient: wsClient,
+			cancel:   cancel,
+			fallback: fallback,
+			logger:   logger,
+			changed:  make(chan struct{}, 1),
+		}
+
+		unsubscribe, err := subscribe(s)
+		if err != nil {
+			cancel()
+			logger.Debug("slot subscription failed", "source", s.name, "err", err)
+			continue
+		}
+		go func() {
+			<-pumpCtx.Done()
+			unsubscribe()
+		}()
+
+		if err := s.waitForFirstNotification(ctx); err != nil {
+			cancel()
+			logger.Debug("slot subscription produced no notifications", "source", s.name, "err", err)
+			continue
+		}
+		return s, nil
+	}
+
+	wsClient.Close()
+	return nil, fmt.Errorf("no slot notifications received from slotsUpdatesSubscribe or slotSubscribe within %s", slotSubscriptionProbeTimeout)
+}
+
+// followSlotsUpdates subscribes to slotsUpdatesSubscribe, recording a transition on the first
+// shred received (or bank created, whichever comes first) for each new slot
+func (s *websocketSlotSource) followSlotsUpdates() (unsubscribe func(), err error) {
+	s.name = SlotSourceSlotsUpdates
+	sub, err := s.wsClient.SlotsUpdatesSubscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			update, err := sub.Recv()
+			if err != nil {
+				s.setError(err)
+				return
+			}
+			if update == nil {
+				return // unsubscribed
+			}
+			if update.Type != ws.SlotsUpdatesFirstShredReceived && update.Type != ws.SlotsUpdatesCreatedBank {
+				continue
+			}
+			detectedAt := time.Now()
+			transition := SlotTransition{
+				Slot:       update.Slot,
+				Source:     s.name,
+				DetectedAt: detectedAt,
+			}
+			if update.Timestamp != nil {
+				transition.DetectionLag = max(detectedAt.Sub(update.Timestamp.Time()), 0)
+				transition.DetectionLagKnown = true
+			}
+			s.setLatest(transition)
+		}
+	}()
+
+	return sub.Unsubscribe, nil
+}
+
+// followSlots subscribes to slotSubscribe, which has no timing information
+func (s *websocketSlotSource) followSlots() (unsubscribe func(), err error) {
+	s.name = SlotSourceSlot
+	sub, err := s.wsClient.SlotSubscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			result, err := sub.Recv()
+			if err != nil {
+				s.setError(err)
+				return
+			}
+			if result == nil {
+				return // unsubscribed
+			}
+			s.setLatest(SlotTransition{
+				Slot:       result.Slot,
+				Source:     s.name,
+				DetectedAt: time.Now(),
+			})
+		}
+	}()
+
+	return sub.Unsubscribe, nil
+}
+
+// setLatest records a transition if it is for a later slot than the latest seen
+func (s *websocketSlotSource) setLatest(transition SlotTransition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if transition.Slot <= s.latest.Slot {
+		return
+	}
+	s.latest = transition
+	s.signal()
+}
+
+// setError records a subscription failure
+func (s *websocketSlotSource) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	s.signal()
+}
+
+// signal wakes a waiter, must be called with mu held
+func (s *websocketSlotSource) signal() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// state returns the latest transition and subscription error
+func (s *websocketSlotSource) state() (SlotTransition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest, s.err
+}
+
+// waitForFirstNotification returns once the subscription has produced a slot
+func (s *websocketSlotSource) waitForFirstNotification(ctx context.Context) error {
+	timeout := time.After(slotSubscriptionProbeTimeout)
+	for {
+		latest, err := s.state()
+		if err != nil {
+			return err
+		}
+		if latest.Slot > 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("timed out after %s", slotSubscriptionProbeTimeout)
+		case <-s.changed:
+		}
+	}
+}
+
+// Name implements SlotSource.Name
+func (s *websocketSlotSource) Name() string {
+	return s.name
+}
+
+// Close implements SlotSource.Close
+func (s *websocketSlotSource) Close() {
+	s.cancel()
+	s.wsClient.Close()
+}
+
+// WaitForNextSlot implements SlotSource.WaitForNextSlot. If the subscription has failed it falls
+// back to polling rather than failing the wait.
+func (s *websocketSlotSource) WaitForNextSlot(ctx context.Context) (SlotTransition, error) {
+	current, err := s.state()
+	for {
+		if err != nil {
+			s.logger.Warn("slot subscription failed - falling back to polling", "source", s.name, "err", err)
+			return s.fallback.WaitForNextSlot(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return SlotTransition{}, ctx.Err()
+		case <-s.changed:
+		}
+
+		var latest SlotTransition
+		latest, err = s.state()
+		if err == nil && latest.Slot > current.Slot {
+			return latest, nil
+		}
+	}
+}
+
+// websocketURLFromRPCURL derives the validator's pubsub websocket url from its rpc url. Validators
+// serve websockets on the rpc port + 1 unless configured otherwise.
+func websocketURLFromRPCURL(rpcURL string) (string, error) {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rpc url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("unsupported rpc url scheme %q", u.Scheme)
+	}
+
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return "", fmt.Errorf("rpc url %q has no valid port", rpcURL)
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port+1))
+
+	return u.String(), nil
+}
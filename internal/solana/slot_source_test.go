@@ -0,0 +1,191 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlotNotificationServer starts a websocket stand-in for a validator's pubsub endpoint. Each
+// subscription method in supported is acknowledged and then sent a new slot every 20ms; any
+// other subscription gets a JSON-RPC error and no notifications.
+func newSlotNotificationServer(t *testing.T, supported ...string) (wsURL string) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var writeMu sync.Mutex
+		writeJSON := func(v any) error {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			return conn.WriteJSON(v)
+		}
+
+		var slot atomic.Uint64
+		slot.Store(1000)
+		done := make(chan struct{})
+		defer close(done)
+
+		for {
+			var req struct {
+				ID     uint64 `json:"id"`
+				Method string `json:"method"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			supportedMethod := false
+			for _, m := range supported {
+				supportedMethod = supportedMethod || m == req.Method
+			}
+			if !supportedMethod {
+				_ = writeJSON(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32601, "message": "Method not found"}})
+				continue
+			}
+
+			_ = writeJSON(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": 7})
+			go func(method string) {
+				for {
+					select {
+					case <-done:
+						return
+					case <-time.After(20 * time.Millisecond):
+					}
+					result := map[string]any{"slot": slot.Add(1), "parent": slot.Load() - 1, "root": slot.Load() - 32}
+					notification := "slotNotification"
+					if method == "slotsUpdatesSubscribe" {
+						notification = "slotsUpdatesNotification"
+						result["type"] = "firstShredReceived"
+						result["timestamp"] = time.Now().Add(-5 * time.Millisecond).UnixMilli()
+					}
+					err := writeJSON(map[string]any{
+						"jsonrpc": "2.0",
+						"method":  notification,
+						"params":  map[string]any{"subscription": 7, "result": result},
+					})
+					if err != nil {
+						return
+					}
+				}
+			}(req.Method)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestPollingSlotSource_WaitForNextSlot(t *testing.T) {
+	slots := []uint64{10, 10, 10, 11}
+	calls := 0
+	source := NewPollingSlotSource(func() (uint64, error) {
+		slot := slots[min(calls, len(slots)-1)]
+		calls++
+		return slot, nil
+	}, log.Default())
+
+	transition, err := source.WaitForNextSlot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), transition.Slot)
+	assert.Equal(t, SlotSourcePolling, transition.Source)
+	assert.True(t, transition.DetectionLagKnown)
+	assert.Less(t, transition.DetectionLag, time.Second)
+}
+
+func TestPollingSlotSource_InitialError(t *testing.T) {
+	boom := errors.New("boom")
+	source := NewPollingSlotSource(func() (uint64, error) { return 0, boom }, log.Default())
+
+	_, err := source.WaitForNextSlot(context.Background())
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestWebsocketSlotSource_SlotsUpdates(t *testing.T) {
+	wsURL := newSlotNotificationServer(t, "slotsUpdatesSubscribe", "slotSubscribe")
+
+	source, err := newWebsocketSlotSource(context.Background(), wsURL, NewPollingSlotSource(func() (uint64, error) {
+		return 0, errors.New("polling should not be used")
+	}, log.Default()), log.Default())
+	require.NoError(t, err)
+	defer source.Close()
+
+	assert.Equal(t, SlotSourceSlotsUpdates, source.Name())
+
+	first, err := source.WaitForNextSlot(context.Background())
+	require.NoError(t, err)
+	second, err := source.WaitForNextSlot(context.Background())
+	require.NoError(t, err)
+
+	assert.Greater(t, second.Slot, first.Slot)
+	assert.True(t, second.DetectionLagKnown)
+	assert.GreaterOrEqual(t, second.DetectionLag, 4*time.Millisecond)
+}
+
+func TestWebsocketSlotSource_FallsBackToSlotSubscribe(t *testing.T) {
+	wsURL := newSlotNotificationServer(t, "slotSubscribe")
+
+	source, err := newWebsocketSlotSource(context.Background(), wsURL, NewPollingSlotSource(func() (uint64, error) {
+		return 0, errors.New("polling should not be used")
+	}, log.Default()), log.Default())
+	require.NoError(t, err)
+	defer source.Close()
+
+	assert.Equal(t, SlotSourceSlot, source.Name())
+
+	transition, err := source.WaitForNextSlot(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, transition.Slot, uint64(1000))
+	assert.False(t, transition.DetectionLagKnown)
+	assert.Equal(t, "lag unknown (websocket slotSubscribe)", transition.String())
+}
+
+func TestClient_NewSlotSource_FallsBackToPolling(t *testing.T) {
+	client, _, _ := createTestClient()
+	client.localWSURL = "ws://127.0.0.1:1" // nothing listening
+
+	source := client.NewSlotSource(context.Background())
+	defer source.Close()
+
+	assert.Equal(t, SlotSourcePolling, source.Name())
+}
+
+func TestWebsocketURLFromRPCURL(t *testing.T) {
+	tests := []struct {
+		rpcURL  string
+		want    string
+		wantErr bool
+	}{
+		{rpcURL: "http://127.0.0.1:8899", want: "ws://127.0.0.1:8900"},
+		{rpcURL: "https://rpc.example.com:443/path", want: "wss://rpc.example.com:444/path"},
+		{rpcURL: "http://[::1]:8899", want: "ws://[::1]:8900"},
+		{rpcURL: "http://localhost", wantErr: true},
+		{rpcURL: "ftp://localhost:21", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rpcURL, func(t *testing.T) {
+			got, err := websocketURLFromRPCURL(tt.rpcURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrVoteAccountNotFound is returned when no vote account, current or delinquent, has the identity as its node pubkey
+var ErrVoteAccountNotFound = errors.New("vote account not found")
+
+// VoteAccount is the cluster's view of an identity's vote account
+type VoteAccount struct {
+	VotePubkey      string
+	NodePubkey      string
+	AuthorizedVoter string // authorized voter for the current epoch
+	Delinquent      bool
+	ActivatedStake  uint64
+	LastVote        uint64
+}
+
+// VoteProgress is a point-in-time view of an identity's voting from getVoteAccounts
+type VoteProgress struct {
+	Slot                 uint64 // cluster slot when sampled
+	LastVote             uint64
+	RootSlot             uint64
+	Delinquent           bool
+	EpochCredits         int64 // credits earned this epoch
+	ClusterMedianCredits int64 // median credits earned this epoch across current vote accounts
+}
+
+// parsedVoteAccount is the part of a jsonParsed vote account this reads
+type parsedVoteAccount struct {
+	Parsed struct {
+		Info struct {
+			AuthorizedVoters []struct {
+				AuthorizedVoter string `json:"authorizedVoter"`
+				Epoch           uint64 `json:"epoch"`
+			} `json:"authorizedVoters"`
+		} `json:"info"`
+		Type string `json:"type"`
+	} `json:"parsed"`
+	Program string `json:"program"`
+}
+
+// GetVoteAccountForIdentity returns the vote account whose node pubkey is identity, delinquent or
+// not, along with its authorized voter for the current epoch
+func (c *Client) GetVoteAccountForIdentity(identity string) (*VoteAccount, error) {
+	voteAccounts, err := c.networkRPCClient.GetVoteAccounts(context.Background(), &rpc.GetVoteAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote accounts: %w", err)
+	}
+
+	var voteAccount *VoteAccount
+	for _, accounts := range []struct {
+		list       []rpc.VoteAccountsResult
+		delinquent bool
+	}{{voteAccounts.Current, false}, {voteAccounts.Delinquent, true}} {
+		for _, account := range accounts.list {
+			if account.NodePubkey.String() == identity {
+				voteAccount = &VoteAccount{
+					VotePubkey:     account.VotePubkey.String(),
+					NodePubkey:     identity,
+					Delinquent:     accounts.delinquent,
+					ActivatedStake: account.ActivatedStake,
+					LastVote:       account.LastVote,
+				}
+				break
+			}
+		}
+		if voteAccount != nil {
+			break
+		}
+	}
+	if voteAccount == nil {
+		return nil, fmt.Errorf("%w for identity %s", ErrVoteAccountNotFound, identity)
+	}
+
+	epochInfo, err := c.GetEpochInfo()
+	if err != nil {
+		return nil, err
+	}
+	accountInfo, err := c.networkRPCClient.GetAccountInfoWithOpts(
+		context.Background(),
+		solanago.MustPublicKeyFromBase58(voteAccount.VotePubkey),
+		&rpc.GetAccountInfoOpts{
+			Encoding:   solanago.EncodingJSONParsed,
+			Commitment: rpc.CommitmentConfirmed,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote account %s: %w", voteAccount.VotePubkey, err)
+	}
+	if accountInfo == nil || accountInfo.Value == nil || accountInfo.Value.Data == nil {
+		return nil, fmt.Errorf("failed to get vote account %s: no account data", voteAccount.VotePubkey)
+	}
+	voteAccount.AuthorizedVoter, err = parseAuthorizedVoter(accountInfo.Value.Data.GetRawJSON(), epochInfo.Epoch)
+	if err != nil {
+		return nil, fmt.Errorf("vote account %s: %w", voteAccount.VotePubkey, err)
+	}
+
+	return voteAccount, nil
+}
+
+// GetVoteProgress returns how the vote account whose node pubkey is identity is voting, against the
+// cluster slot and the median credits of current vote accounts
+func (c *Client) GetVoteProgress(identity string) (*VoteProgress, error) {
+	slot, err := c.GetCurrentSlot()
+	if err != nil {
+		return nil, err
+	}
+	voteAccounts, err := c.networkRPCClient.GetVoteAccounts(context.Background(), &rpc.GetVoteAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote accounts: %w", err)
+	}
+
+	progress := &VoteProgress{Slot: slot}
+	found := false
+	credits := make([]int64, 0, len(voteAccounts.Current))
+	for _, account := range voteAccounts.Current {
+		credits = append(credits, epochCreditsEarned(account))
+		if account.NodePubkey.String() == identity {
+			progress.LastVote, progress.RootSlot, progress.EpochCredits = account.LastVote, account.RootSlot, epochCreditsEarned(account)
+			found = true
+		}
+	}
+	for _, account := range voteAccounts.Delinquent {
+		if !found && account.NodePubkey.String() == identity {
+			progress.LastVote, progress.RootSlot, progress.EpochCredits = account.LastVote, account.RootSlot, epochCreditsEarned(account)
+			progress.Delinquent = true
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w for identity %s", ErrVoteAccountNotFound, identity)
+	}
+
+	if len(credits) > 0 {
+		slices.Sort(credits)
+		progress.ClusterMedianCredits = credits[len(credits)/2]
+	}
+	return progress, nil
+}
+
+// epochCreditsEarned returns the credits a vote account has earned in its latest epoch
+func epochCreditsEarned(account rpc.VoteAccountsResult) int64 {
+	if len(account.EpochCredits) == 0 {
+		return 0
+	}
+	// each entry is [epoch, credits, previous credits]
+	latest := account.EpochCredits[len(account.EpochCredits)-1]
+	return latest[1] - latest[2]
+}
+
+// parseAuthorizedVoter returns the authorized voter for epoch from a jsonParsed vote account - the
+// entry with the latest epoch not after it, since voters authorized for a later epoch aren't yet
+// in effect
+func parseAuthorizedVoter(data json.RawMessage, epoch uint64) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("account data is not jsonParsed - is it a vote account?")
+	}
+	var account parsedVoteAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return "", fmt.Errorf("failed to parse vote account: %w", err)
+	}
+	if account.Program != "vote" || account.Parsed.Type != "vote" {
+		return "", fmt.Errorf("not a vote account (program %q, type %q)", account.Program, account.Parsed.Type)
+	}
+
+	authorizedVoter := ""
+	var authorizedVoterEpoch uint64
+	for _, voter := range account.Parsed.Info.AuthorizedVoters {
+		if voter.Epoch <= epoch && (authorizedVoter == "" || voter.Epoch >= authorizedVoterEpoch) {
+			authorizedVoter = voter.AuthorizedVoter
+			authorizedVoterEpoch = voter.Epoch
+		}
+	}
+	if authorizedVoter == "" {
+		return "", fmt.Errorf("no authorized voter for epoch %d", epoch)
+	}
+	return authorizedVoter, nil
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// commandWaitDelay is how long to wait for a killed command's output pipes to close
+const commandWaitDelay = 2 * time.Second
+
+// commandErrorOutputTail is how much of a failed command's output its error message includes
+const commandErrorOutputTail = 512
+
+// ErrCommandTimedOut matches (with errors.Is) a *CommandError for a command killed at its timeout
+var ErrCommandTimedOut = errors.New("command timed out")
+
+// CommandError is returned by RunCommand when a command fails, fails to start or times out
+type CommandError struct {
+	Command  string        // the command, shell-quoted
+	Output   string        // combined stdout and stderr, partial if the command was killed
+	TimedOut bool          // killed, along with its process group, at Timeout
+	Timeout  time.Duration // the timeout the command ran with
+	Err      error
+}
+
+// Error implements error, including the tail of the command's output
+func (e *CommandError) Error() string {
+	msg := e.Err.Error()
+	if e.TimedOut {
+		msg = fmt.Sprintf("timed out after %s and was killed", e.Timeout)
+	}
+	output := strings.TrimSpace(e.Output)
+	if output == "" {
+		return msg
+	}
+	if len(output) > commandErrorOutputTail {
+		output = "..." + output[len(output)-commandErrorOutputTail:]
+	}
+	return fmt.Sprintf("%s: %s", msg, output)
+}
+
+// Unwrap returns the underlying exec error
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrCommandTimedOut and the command timed out
+func (e *CommandError) Is(target error) bool {
+	return target == ErrCommandTimedOut && e.TimedOut
+}
+
+// Command is a command to run as an argv list - never through a shell - with optional extra
+// environment variables and a timeout
+type Command struct {
+	Argv    []string
+	Env     map[string]string // added to this process's environment
+	Timeout time.Duration     // 0 for none
+}
+
+// IsZero returns true if no command is set
+func (c Command) IsZero() bool {
+	return len(c.Argv) == 0
+}
+
+// String renders the command as a shell-quoted line, env assignments first, so it shows the exact
+// argv and can be pasted into a shell
+func (c Command) String() string {
+	words := make([]string, 0, len(c.Env)+len(c.Argv))
+	for _, k := range slices.Sorted(maps.Keys(c.Env)) {
+		words = append(words, k+"="+ShellQuote(c.Env[k]))
+	}
+	for _, arg := range c.Argv {
+		words = append(words, ShellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// Run runs the command, or only logs it in a dry run
+func (c Command) Run(dryRun, logDebug bool) error {
+	return RunCommand(RunCommandParams{
+		CommandSlice: c.Argv,
+		Env:          c.Env,
+		Timeout:      c.Timeout,
+		DryRun:       dryRun,
+		LogDebug:     logDebug,
+	})
+}
+
+// ShellQuote quotes s for a POSIX shell, leaving it as is when it has no special characters
+func ShellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r))
+	}) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// SplitShellWords splits s into words the way a POSIX shell would, honouring single quotes, double
+// quotes and backslash escapes, without variable expansion, globbing or any other shell features.
+// Go template actions ({{ ... }}) are kept whole, so a template can be split before it is rendered
+// and a value containing spaces stays a single word.
+func SplitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		inQuote rune // ' or " while inside quotes
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		// template actions are copied verbatim, quotes and spaces included
+		if strings.HasPrefix(s[i:], "{{") {
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated template action in %q", s)
+			}
+			word.WriteString(s[i : i+end+2])
+			inWord = true
+			i += end + 1
+			continue
+		}
+
+		switch {
+		case inQuote == '\'':
+			if c == '\'' {
+				inQuote = 0
+			} else {
+				word.WriteByte(c)
+			}
+		case inQuote == '"':
+			switch {
+			case c == '"':
+				inQuote = 0
+			case c == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0:
+				i++
+				word.WriteByte(s[i])
+			default:
+				word.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			inQuote = rune(c)
+			inWord = true
+		case c == '\\':
+			if i+1 >= len(s) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			i++
+			word.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inQuote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", inQuote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"agave-validator --ledger /mnt/ledger set-identity id.json", []string{"agave-validator", "--ledger", "/mnt/ledger", "set-identity", "id.json"}},
+		{"  extra   spaces\tand\ttabs ", []string{"extra", "spaces", "and", "tabs"}},
+		{`set-identity '/keys/my key.json'`, []string{"set-identity", "/keys/my key.json"}},
+		{`set-identity "/keys/my \"key\".json"`, []string{"set-identity", `/keys/my "key".json`}},
+		{`set-identity /keys/my\ key.json`, []string{"set-identity", "/keys/my key.json"}},
+		{`echo '' "$HOME"`, []string{"echo", "", "$HOME"}},
+		{"sudo -u sol {{ .Bin }} --ledger {{ .LedgerDir }}", []string{"sudo", "-u", "sol", "{{ .Bin }}", "--ledger", "{{ .LedgerDir }}"}},
+		{`{{ printf "%s x" .Bin }} --flag={{ .A }}`, []string{`{{ printf "%s x" .Bin }}`, "--flag={{ .A }}"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitShellWords(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestSplitShellWords_Errors(t *testing.T) {
+	for _, in := range []string{`echo 'unterminated`, `echo "unterminated`, `echo trailing\`, `{{ .Bin `} {
+		_, err := SplitShellWords(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestCommand_String(t *testing.T) {
+	cmd := Command{
+		Argv: []string{"sudo", "-u", "sol", "agave-validator", "--ledger", "/mnt/my ledger", "set-identity", "it's.json"},
+		Env:  map[string]string{"RUST_LOG": "warn", "A": "b c"},
+	}
+	assert.Equal(t, `A='b c' RUST_LOG=warn sudo -u sol agave-validator --ledger '/mnt/my ledger' set-identity 'it'\''s.json'`, cmd.String())
+
+	// the rendered line splits back into the same argv
+	words, err := SplitShellWords(Command{Argv: cmd.Argv}.String())
+	require.NoError(t, err)
+	assert.Equal(t, cmd.Argv, words)
+}
+
+func TestCommand_RunWithEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	cmd := Command{
+		Argv: []string{"sh", "-c", `printf '%s' "$GREETING" > "$1"`, "sh", out},
+		Env:  map[string]string{"GREETING": "hello world"},
+	}
+
+	require.NoError(t, cmd.Run(false, false))
+
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(got))
+}
+
+func TestCommand_RunTimeout(t *testing.T) {
+	// the shell's background child shares its output pipe - only killing the whole process
+	// group gets Run to return before it finishes
+	cmd := Command{
+		Argv:    []string{"sh", "-c", "echo switching; sleep 30 & wait"},
+		Timeout: 100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	err := cmd.Run(false, false)
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), commandWaitDelay)
+	assert.True(t, errors.Is(err, ErrCommandTimedOut), err)
+	var cmdErr *CommandError
+	require.True(t, errors.As(err, &cmdErr))
+	assert.True(t, cmdErr.TimedOut)
+	assert.Equal(t, "switching\n", cmdErr.Output, "partial output is kept")
+	assert.Contains(t, err.Error(), "timed out after 100ms and was killed: switching")
+}
+
+func TestCommand_RunFailureOutput(t *testing.T) {
+	err := Command{Argv: []string{"sh", "-c", "echo admin socket wedged >&2; exit 3"}}.Run(false, false)
+
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrCommandTimedOut))
+	assert.Contains(t, err.Error(), "exit status 3: admin socket wedged")
+}
+
+func TestCommand_RunDryRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+
+	require.NoError(t, Command{Argv: []string{"touch", out}}.Run(true, false))
+
+	assert.NoFileExists(t, out)
+}
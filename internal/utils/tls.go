@@ -50,6 +50,31 @@ func BuildMTLSClientConfig(caCertPath, nodeCertPath, nodeKeyPath string) (*tls.C
 	}, nil
 }
 
+// BuildTLSClientConfig builds a *tls.Config for an outbound client connection. caCertPath, when
+// set, replaces the system roots used to verify the server; certPath/keyPath, when set, are
+// presented as a client certificate. Empty paths are skipped.
+func BuildTLSClientConfig(caCertPath, certPath, keyPath string) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if certPath != "" || keyPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate/key: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caCertPath != "" {
+		caPool, err := loadCACertPool(caCertPath)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = caPool
+	}
+
+	return config, nil
+}
+
 func loadCACertPool(caCertPath string) (*x509.CertPool, error) {
 	pem, err := os.ReadFile(caCertPath)
 	if err != nil {
@@ -209,6 +209,26 @@ func TestBuildMTLSClientConfig_InvalidCAPath(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to read CA certificate")
 }
 
+func TestBuildTLSClientConfig_Empty(t *testing.T) {
+	cfg, err := BuildTLSClientConfig("", "", "")
+
+	require.NoError(t, err)
+	assert.Nil(t, cfg.RootCAs)
+	assert.Empty(t, cfg.Certificates)
+}
+
+func TestBuildTLSClientConfig_CAAndClientCert(t *testing.T) {
+	ca := generateTestCA(t)
+	node := generateTestCert(t, ca, nil)
+	caCertPath, certPath, keyPath := writeTempFiles(t, ca, node)
+
+	cfg, err := BuildTLSClientConfig(caCertPath, certPath, keyPath)
+
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.RootCAs)
+	assert.Len(t, cfg.Certificates, 1)
+}
+
 // TestMTLSHandshake_Success verifies that a server built with BuildMTLSServerConfig and a
 // client built with BuildMTLSClientConfig can complete a mutual TLS handshake over TCP.
 // (We use crypto/tls directly here to keep the test simple and independent of QUIC.)
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -18,6 +20,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -161,35 +164,76 @@ func RemoveFile(path string) error {
 // RunCommandParams represents the parameters for running a command
 type RunCommandParams struct {
 	CommandSlice []string
+	Env          map[string]string // added to this process's environment
+	Timeout      time.Duration     // 0 for none
 	DryRun       bool
 	LogDebug     bool
 }
 
-// RunCommand runs a command and returns the output
+// RunCommand runs a command in its own process group, killing the whole group if it runs past
+// its timeout. A failed command returns a *CommandError carrying whatever output it produced.
 func RunCommand(params RunCommandParams) error {
+	if len(params.CommandSlice) == 0 {
+		return fmt.Errorf("no command to run")
+	}
+	command := Command{Argv: params.CommandSlice, Env: params.Env}
+
 	if params.DryRun {
-		log.Debugf("dry run: %s", strings.Join(params.CommandSlice, " "))
+		log.Debugf("dry run: %s", command)
 		return nil
 	}
 
 	// don't use up cycles unless we need to so that commands run faster
 	if params.LogDebug {
-		log.Debug("running command", "command", strings.Join(params.CommandSlice, " "))
+		log.Debug("running command", "command", command, "timeout", params.Timeout)
 	}
 
-	cmd := exec.Command(params.CommandSlice[0], params.CommandSlice[1:]...)
+	ctx := context.Background()
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
 
-	output, err := cmd.CombinedOutput()
+	cmd := exec.CommandContext(ctx, params.CommandSlice[0], params.CommandSlice[1:]...)
+	if len(params.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range params.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	// own process group, so a timeout also kills anything the command spawned - e.g. sudo's child
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	// don't wait forever on output pipes held open by an orphan that escaped the group
+	cmd.WaitDelay = commandWaitDelay
+
+	// a single writer for both streams is only written from one goroutine at a time
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
 	if err != nil {
+		cmdErr := &CommandError{
+			Command:  command.String(),
+			Output:   output.String(),
+			TimedOut: ctx.Err() != nil,
+			Timeout:  params.Timeout,
+			Err:      err,
+		}
 		log.Error("command failed",
-			"command", strings.Join(params.CommandSlice, " "),
-			"output", string(output),
+			"command", cmdErr.Command,
+			"output", cmdErr.Output,
+			"timed_out", cmdErr.TimedOut,
 			"err", err,
 		)
-		return err
+		return cmdErr
 	}
 
-	log.Debugf("output: %s", string(output))
+	log.Debugf("output: %s", output.String())
 	return nil
 }
 
@@ -0,0 +1,200 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+)
+
+// binVersionTimeout bounds running the validator binary with --version
+const binVersionTimeout = 5 * time.Second
+
+// ClientProfile holds the defaults for a validator client, used where the config leaves them unset
+type ClientProfile struct {
+	Name                          string
+	SetIdentityActiveCmdTemplate  string
+	SetIdentityPassiveCmdTemplate string
+	TowerFileNameTemplate         string
+	RequiresClientConfig          bool // default set-identity templates need validator.client_config
+	SupportsAdminRPC              bool // exposes the agave admin.rpc socket in the ledger dir
+}
+
+const (
+	agaveSetIdentityActiveCmdTemplate  = "{{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Active.KeyFile }} --require-tower"
+	agaveSetIdentityPassiveCmdTemplate = "{{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Passive.KeyFile }}"
+	towerFileNameTemplate              = "tower-1_9-{{ .Identities.Active.PubKey }}.bin"
+)
+
+// ClientProfiles are the supported validator clients keyed by validator.client name
+var ClientProfiles = map[string]ClientProfile{
+	constants.ClientTypeAgave: {
+		Name:                          constants.ClientTypeAgave,
+		SetIdentityActiveCmdTemplate:  agaveSetIdentityActiveCmdTemplate,
+		SetIdentityPassiveCmdTemplate: agaveSetIdentityPassiveCmdTemplate,
+		TowerFileNameTemplate:         towerFileNameTemplate,
+		SupportsAdminRPC:              true,
+	},
+	constants.ClientTypeJito: {
+		Name:                          constants.ClientTypeJito,
+		SetIdentityActiveCmdTemplate:  agaveSetIdentityActiveCmdTemplate,
+		SetIdentityPassiveCmdTemplate: agaveSetIdentityPassiveCmdTemplate,
+		TowerFileNameTemplate:         towerFileNameTemplate,
+		SupportsAdminRPC:              true,
+	},
+	// frankendancer and firedancer both switch identity with fdctl-style set-identity against
+	// their config.toml and keep agave's tower file naming
+	constants.ClientTypeFiredancer: {
+		Name:                          constants.ClientTypeFiredancer,
+		SetIdentityActiveCmdTemplate:  "{{ .Bin }} set-identity --config {{ .ClientConfig }} {{ .Identities.Active.KeyFile }} --require-tower",
+		SetIdentityPassiveCmdTemplate: "{{ .Bin }} set-identity --config {{ .ClientConfig }} {{ .Identities.Passive.KeyFile }}",
+		TowerFileNameTemplate:         towerFileNameTemplate,
+		RequiresClientConfig:          true,
+	},
+}
+
+// clientAliases map other accepted validator.client values to a profile name
+var clientAliases = map[string]string{
+	"jito-solana":   constants.ClientTypeJito,
+	"frankendancer": constants.ClientTypeFiredancer,
+}
+
+// versionClientTags map the client:<tag> reported by agave-family --version output to a profile name
+var versionClientTags = map[string]string{
+	"agave":         constants.ClientTypeAgave,
+	"jitolabs":      constants.ClientTypeJito,
+	"firedancer":    constants.ClientTypeFiredancer,
+	"frankendancer": constants.ClientTypeFiredancer,
+}
+
+var (
+	versionClientTagRegexp = regexp.MustCompile(`client:\s*([A-Za-z]+)`)
+	versionRegexp          = regexp.MustCompile(`\b(\d+)\.\d+\.\d+\S*`)
+)
+
+// clientProfileNames returns the supported validator.client values, sorted
+func clientProfileNames() []string {
+	names := make([]string, 0, len(ClientProfiles)+len(clientAliases))
+	for name := range ClientProfiles {
+		names = append(names, name)
+	}
+	for alias := range clientAliases {
+		names = append(names, alias)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookupClientProfile returns the profile for a validator.client value or alias
+func lookupClientProfile(name string) (ClientProfile, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := clientAliases[name]; ok {
+		name = alias
+	}
+	profile, ok := ClientProfiles[name]
+	return profile, ok
+}
+
+// parseBinVersion extracts the client profile name (empty when not reported) and version from a
+// validator binary's --version output, e.g.
+//
+//	agave-validator 2.1.14 (src:7ac65892; feat:798020478, client:JitoLabs)
+//	0.505.20216 (44f9f393d167138abe1c819f7424990a56e1913e)
+func parseBinVersion(output string) (client, version string) {
+	if m := versionRegexp.FindStringSubmatch(output); m != nil {
+		version = m[0]
+		// firedancer versions are 0.x - agave and jito have long been past 1.x
+		if m[1] == "0" {
+			client = constants.ClientTypeFiredancer
+		}
+	}
+	if m := versionClientTagRegexp.FindStringSubmatch(output); m != nil {
+		if tagClient, ok := versionClientTags[strings.ToLower(m[1])]; ok {
+			client = tagClient
+		}
+	}
+	return client, version
+}
+
+// clientFromBinName returns the profile name implied by the binary name, empty if it doesn't
+func clientFromBinName(bin string) string {
+	base := strings.ToLower(filepath.Base(bin))
+	for _, prefix := range []string{"fdctl", "fddev", "firedancer", "frankendancer"} {
+		if strings.HasPrefix(base, prefix) {
+			return constants.ClientTypeFiredancer
+		}
+	}
+	return ""
+}
+
+// binVersionOutput runs the validator binary with --version and returns its output
+func binVersionOutput(bin string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), binVersionTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, bin, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", bin, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// configureClient sets the client profile from validator.client, or detects it from the binary
+// name, its --version output and finally the local validator's getVersion
+func (v *Validator) configureClient(client, clientConfig string) (err error) {
+	if clientConfig != "" {
+		v.ClientConfig, err = filepath.Abs(clientConfig)
+		if err != nil {
+			return fmt.Errorf("failed to resolve validator.client_config %s: %w", clientConfig, err)
+		}
+	}
+
+	binOutput, binErr := binVersionOutput(v.Bin)
+	if binErr != nil {
+		v.logger.Warn("could not detect validator binary version", "error", binErr)
+	}
+	binClient, binVersion := parseBinVersion(binOutput)
+	v.BinMetadata.Version = binVersion
+
+	if client != "" {
+		profile, ok := lookupClientProfile(client)
+		if !ok {
+			return fmt.Errorf("invalid validator.client %q, must be one of: %s", client, strings.Join(clientProfileNames(), ", "))
+		}
+		v.setClientProfile(profile, "config")
+		return nil
+	}
+
+	if detected := clientFromBinName(v.Bin); detected != "" {
+		v.setClientProfile(ClientProfiles[detected], "bin name")
+		return nil
+	}
+
+	if binClient != "" {
+		v.setClientProfile(ClientProfiles[binClient], "bin --version")
+		return nil
+	}
+
+	if rpcVersion, err := v.solanaRPCClient.GetLocalNodeVersion(); err == nil {
+		if rpcClient, _ := parseBinVersion(rpcVersion); rpcClient != "" {
+			v.setClientProfile(ClientProfiles[rpcClient], "getVersion")
+			return nil
+		}
+	}
+
+	v.logger.Warn("could not detect validator client - assuming agave, set validator.client to override")
+	v.setClientProfile(ClientProfiles[constants.ClientTypeAgave], "default")
+	return nil
+}
+
+// setClientProfile sets the client profile and bin metadata client
+func (v *Validator) setClientProfile(profile ClientProfile, source string) {
+	v.ClientProfile = profile
+	v.BinMetadata.Client = profile.Name
+	v.logger.Debug("client profile set", "client", profile.Name, "source", source, "bin_version", v.BinMetadata.Version)
+}
@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+	solanapkg "github.com/sol-strategies/solana-validator-failover/internal/solana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createVersionBin writes an executable named name that prints output for --version
+func createVersionBin(t *testing.T, name, output string) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), name)
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0o755))
+	return bin
+}
+
+func TestParseBinVersion(t *testing.T) {
+	tests := []struct {
+		output  string
+		client  string
+		version string
+	}{
+		{"agave-validator 2.1.14 (src:7ac65892; feat:798020478, client:Agave)", constants.ClientTypeAgave, "2.1.14"},
+		{"agave-validator 2.1.14 (src:7ac65892; feat:798020478, client:JitoLabs)", constants.ClientTypeJito, "2.1.14"},
+		{"0.505.20216 (44f9f393d167138abe1c819f7424990a56e1913e)", constants.ClientTypeFiredancer, "0.505.20216"},
+		{"agave-validator 1.0.0 (src:7ac65892; feat:798020478, client:Mock)", "", "1.0.0"},
+		{"dummy agave-validator", "", ""},
+	}
+	for _, tt := range tests {
+		client, version := parseBinVersion(tt.output)
+		assert.Equal(t, tt.client, client, tt.output)
+		assert.Equal(t, tt.version, version, tt.output)
+	}
+}
+
+func TestLookupClientProfile_Aliases(t *testing.T) {
+	profile, ok := lookupClientProfile("Frankendancer")
+	assert.True(t, ok)
+	assert.Equal(t, constants.ClientTypeFiredancer, profile.Name)
+
+	profile, ok = lookupClientProfile("jito-solana")
+	assert.True(t, ok)
+	assert.Equal(t, constants.ClientTypeJito, profile.Name)
+
+	_, ok = lookupClientProfile("sig")
+	assert.False(t, ok)
+}
+
+func TestConfigureClient_FromConfig(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "agave-validator", "agave-validator 2.1.14 (src:7ac65892; feat:798020478, client:Agave)")
+
+	err := validator.configureClient("jito", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ClientTypeJito, validator.ClientProfile.Name)
+	assert.Equal(t, BinMetadata{Client: constants.ClientTypeJito, Version: "2.1.14"}, validator.BinMetadata)
+}
+
+func TestConfigureClient_InvalidClient(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "agave-validator", "")
+
+	err := validator.configureClient("sig", "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid validator.client")
+}
+
+func TestConfigureClient_DetectsFromBinName(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "fdctl", "")
+
+	err := validator.configureClient("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ClientTypeFiredancer, validator.ClientProfile.Name)
+}
+
+func TestConfigureClient_DetectsFromBinVersion(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "agave-validator", "agave-validator 2.1.14 (src:7ac65892; feat:798020478, client:JitoLabs)")
+
+	err := validator.configureClient("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ClientTypeJito, validator.ClientProfile.Name)
+}
+
+func TestConfigureClient_DetectsFromGetVersion(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "validator", "unknown")
+	validator.solanaRPCClient = solanapkg.NewMockClient().WithGetLocalNodeVersion(func() (string, error) {
+		return "0.505.20216", nil
+	})
+
+	err := validator.configureClient("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ClientTypeFiredancer, validator.ClientProfile.Name)
+}
+
+func TestConfigureClient_DefaultsToAgave(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.Bin = createVersionBin(t, "validator", "unknown")
+	validator.solanaRPCClient = solanapkg.NewMockClient().WithGetLocalNodeVersion(func() (string, error) {
+		return "", errors.New("connection refused")
+	})
+
+	err := validator.configureClient("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ClientTypeAgave, validator.ClientProfile.Name)
+}
+
+func TestConfigureSetIdentityCommands_FiredancerDefaults(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.Bin = "/usr/local/bin/fdctl"
+	validator.ClientProfile = ClientProfiles[constants.ClientTypeFiredancer]
+	validator.ClientConfig = "/home/solana/config.toml"
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{})
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"/usr/local/bin/fdctl set-identity --config /home/solana/config.toml "+validator.Identities.Active.KeyFile+" --require-tower",
+		validator.SetIdentityActiveCommand)
+	assert.Equal(t,
+		"/usr/local/bin/fdctl set-identity --config /home/solana/config.toml "+validator.Identities.Passive.KeyFile,
+		validator.SetIdentityPassiveCommand)
+}
+
+func TestConfigureSetIdentityCommands_FiredancerRequiresClientConfig(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.ClientProfile = ClientProfiles[constants.ClientTypeFiredancer]
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "validator.client_config")
+
+	// explicit templates don't need it
+	err = validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActiveCmdTemplate:  "{{ .Bin }} set-identity {{ .Identities.Active.KeyFile }} --require-tower",
+		SetIdentityPassiveCmdTemplate: "{{ .Bin }} set-identity {{ .Identities.Passive.KeyFile }}",
+	})
+	assert.NoError(t, err)
+}
+
+func TestConfigureSetIdentityMode_AdminRPCUnsupportedByFiredancer(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.ClientProfile = ClientProfiles[constants.ClientTypeFiredancer]
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: constants.SetIdentityModeAdminRPC})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported by the firedancer client")
+}
@@ -5,17 +5,21 @@ import (
 
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/notifications"
 )
 
 // Config is the configuration for the validator
 type Config struct {
 	Bin                 string            `mapstructure:"bin"`
+	Client              string            `mapstructure:"client"`        // optional, agave|jito|firedancer|frankendancer - detected when unset
+	ClientConfig        string            `mapstructure:"client_config"` // optional, the client's own config file, e.g. firedancer's config.toml
 	Cluster             string            `mapstructure:"cluster"`
 	ClusterRPCURL       string            `mapstructure:"cluster_rpc_url"`
 	AverageSlotDuration string            `mapstructure:"average_slot_duration"`
 	Failover            FailoverConfig    `mapstructure:"failover"`
 	Identities          identities.Config `mapstructure:"identities"`
 	RPCAddress          string            `mapstructure:"rpc_address"`
+	RPCWSAddress        string            `mapstructure:"rpc_ws_address"` // optional, defaults to rpc_address with port + 1
 	LedgerDir           string            `mapstructure:"ledger_dir"`
 	Tower               TowerConfig       `mapstructure:"tower"`
 	Name                string            `mapstructure:"name"`      // optional display name used in plans/logs; defaults to OS hostname
@@ -31,15 +35,21 @@ type TowerConfig struct {
 
 // FailoverConfig is the configuration for a failover
 type FailoverConfig struct {
-	SetIdentityPassiveCmdTemplate string               `mapstructure:"set_identity_passive_cmd_template"`
-	SetIdentityActiveCmdTemplate  string               `mapstructure:"set_identity_active_cmd_template"`
-	Hooks                         hooks.FailoverHooks  `mapstructure:"hooks"`
-	Rollback                      hooks.RollbackConfig `mapstructure:"rollback"`
-	MinimumTimeToLeaderSlot       string               `mapstructure:"min_time_to_leader_slot"`
-	Monitor                       MonitorConfig        `mapstructure:"monitor"`
-	Peers                         PeersConfig          `mapstructure:"peers"`
-	Server                        ServerConfig         `mapstructure:"server"`
-	TLS                           TLSConfig            `mapstructure:"tls"`
+	SetIdentityPassiveCmdTemplate string                 `mapstructure:"set_identity_passive_cmd_template"`
+	SetIdentityActiveCmdTemplate  string                 `mapstructure:"set_identity_active_cmd_template"`
+	SetIdentityPassive            hooks.CommandConfig    `mapstructure:"set_identity_passive"` // argv form, takes precedence over the template
+	SetIdentityActive             hooks.CommandConfig    `mapstructure:"set_identity_active"`  // argv form, takes precedence over the template
+	SetIdentityMode               string                 `mapstructure:"set_identity_mode"`    // command (default) or admin_rpc
+	SetIdentityTimeout            string                 `mapstructure:"set_identity_timeout"` // default for commands without their own timeout, 0 for none
+	Hooks                         hooks.FailoverHooks    `mapstructure:"hooks"`
+	Rollback                      hooks.RollbackConfig   `mapstructure:"rollback"`
+	MinimumTimeToLeaderSlot       string                 `mapstructure:"min_time_to_leader_slot"`
+	CatchUp                       CatchUpConfig          `mapstructure:"catch_up"`
+	Monitor                       MonitorConfig          `mapstructure:"monitor"`
+	Notifications                 []notifications.Config `mapstructure:"notifications"`
+	Peers                         PeersConfig            `mapstructure:"peers"`
+	Server                        ServerConfig           `mapstructure:"server"`
+	TLS                           TLSConfig              `mapstructure:"tls"`
 	IsDryRun                      bool
 }
 
@@ -64,6 +74,7 @@ type PeersConfig map[string]struct {
 // MonitorConfig holds the configuration for a failover monitor
 type MonitorConfig struct {
 	CreditSamples CreditSamplesConfig `mapstructure:"credit_samples"`
+	Votes         VotesMonitorConfig  `mapstructure:"votes"`
 }
 
 // CreditSamplesConfig holds the configuration for a failover monitor credit samples
@@ -73,6 +84,21 @@ type CreditSamplesConfig struct {
 	IntervalDuration time.Duration // parsed duration, set during configuration
 }
 
+// VotesMonitorConfig holds the configuration for monitoring that votes land after a failover
+type VotesMonitorConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	WithinSlots   int           `mapstructure:"within_slots"` // votes must land within this many slots of the failover, and keep landing
+	Duration      string        `mapstructure:"duration"`     // how long to monitor for
+	DurationValue time.Duration // parsed duration, set during configuration
+}
+
+// CatchUpConfig holds how far behind the cluster (and the active node) a passive node may be and
+// still become active
+type CatchUpConfig struct {
+	MaxSlotLag int    `mapstructure:"max_slot_lag"`
+	Commitment string `mapstructure:"commitment"` // processed or confirmed
+}
+
 // ServerConfig holds the configuration for a failover server
 type ServerConfig struct {
 	Port              int    `mapstructure:"port"`
@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/sol-strategies/solana-validator-failover/internal/failover"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+// passivePreflight runs on the passive node just before the active node is told to proceed, and
+// again once the active node has finished waiting for the schedule, its leader checks and pre hooks
+func (v *Validator) passivePreflight(activeNodeInfo *failover.NodeInfo) error {
+	if err := v.checkActiveKeypair(); err != nil {
+		return err
+	}
+	if _, err := v.checkCaughtUp(activeNodeInfo.Slot); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkActiveKeypair verifies, on the passive node, that the keypair it will set identity to is
+// usable before the active node is allowed to go passive - a problem found at the final
+// set-identity step leaves both nodes passive. The keypair file must exist, parse, match the
+// configured active identity, be readable only by its owner, be owned by the user this runs as
+// (or root) and be referenced by the set identity active command.
+func (v *Validator) checkActiveKeypair() error {
+	keyFile := v.Identities.Active.KeyFile
+	if keyFile == "" {
+		return fmt.Errorf("no active keypair file - identities.active is not set (pubkey-only mode), so this node has no key to set identity to active with")
+	}
+
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		return fmt.Errorf("active keypair file %s: %w", keyFile, err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("active keypair file %s has permissions %04o - it must not be accessible by group or others (e.g. chmod 600)", keyFile, perm)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		uid := uint32(os.Getuid())
+		if stat.Uid != uid && stat.Uid != 0 {
+			return fmt.Errorf("active keypair file %s is owned by uid %d, not the user this runs as (uid %d)", keyFile, stat.Uid, uid)
+		}
+	}
+
+	// re-read it - the file may have changed since startup, e.g. swapped in by a pre hook
+	identity, err := identities.NewIdentityFromFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("active keypair file %s: %w", keyFile, err)
+	}
+	expectedPubkey := v.Identities.Active.PubKey()
+	if v.activePubkey != "" {
+		expectedPubkey = v.activePubkey
+	}
+	if identity.PubKey() != expectedPubkey {
+		return fmt.Errorf("active keypair file %s is for %s, expected %s", keyFile, identity.PubKey(), expectedPubkey)
+	}
+
+	// the command must actually use it, e.g. not a template that rendered an empty key file
+	referenced := false
+	for _, arg := range v.SetIdentityActiveCmd.Argv {
+		referenced = referenced || strings.Contains(arg, keyFile)
+	}
+	for _, value := range v.SetIdentityActiveCmd.Env {
+		referenced = referenced || strings.Contains(value, keyFile)
+	}
+	if !referenced {
+		return fmt.Errorf("set identity active command does not reference the active keypair file %s: %s", keyFile, v.SetIdentityActiveCommand)
+	}
+
+	v.logger.Debug("active keypair check passed", "key_file", keyFile, "pubkey", identity.PubKey())
+	return nil
+}
+
+// checkCaughtUp returns an error when this node is more than CatchUpMaxSlotLag slots behind the
+// cluster rpc, or behind activeSlot - the active node's slot, 0 when unknown. getHealth tolerates
+// a lag that costs votes as soon as a lagging node becomes active.
+func (v *Validator) checkCaughtUp(activeSlot uint64) (lag solana.SlotLag, err error) {
+	lag, err = v.solanaRPCClient.GetLocalNodeSlotLag(v.CatchUpCommitment)
+	if err != nil {
+		return lag, fmt.Errorf("failed to check this node has caught up: %w", err)
+	}
+	if lag.Behind() > v.CatchUpMaxSlotLag {
+		return lag, fmt.Errorf("this node is %s - more than failover.catch_up.max_slot_lag %d", lag, v.CatchUpMaxSlotLag)
+	}
+	if activeSlot > lag.LocalSlot && activeSlot-lag.LocalSlot > v.CatchUpMaxSlotLag {
+		return lag, fmt.Errorf(
+			"this node is %d slots behind the active node (local %d, active %d, %s) - more than failover.catch_up.max_slot_lag %d",
+			activeSlot-lag.LocalSlot, lag.LocalSlot, activeSlot, lag.Commitment, v.CatchUpMaxSlotLag,
+		)
+	}
+	v.logger.Debug("catch-up check passed", "slot_lag", lag.String(), "active_slot", activeSlot)
+	return lag, nil
+}
+
+// checkVoteAccount looks up the active identity's vote account and returns an error when there is
+// none, or when its authorized voter is neither the active identity nor identities.authorized_voter
+// - either way a failover would succeed without the validator voting. Delinquency is left to
+// checkNotDelinquent so status can still show a delinquent vote account.
+func (v *Validator) checkVoteAccount() (voteAccount *solana.VoteAccount, authorizedVoterSource string, err error) {
+	voteAccount, err = v.solanaRPCClient.GetVoteAccountForIdentity(v.Identities.Active.PubKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check the active identity's vote account: %w", err)
+	}
+
+	switch {
+	case voteAccount.AuthorizedVoter == v.Identities.Active.PubKey():
+		authorizedVoterSource = "active identity"
+	case v.Identities.AuthorizedVoter != nil && voteAccount.AuthorizedVoter == v.Identities.AuthorizedVoter.PubKey():
+		authorizedVoterSource = "authorized_voter " + v.Identities.AuthorizedVoter.KeyFile
+	case v.Identities.AuthorizedVoter != nil:
+		return voteAccount, "", fmt.Errorf(
+			"vote account %s authorized voter is %s, not the active identity %s or identities.authorized_voter %s",
+			voteAccount.VotePubkey, voteAccount.AuthorizedVoter, v.Identities.Active.PubKey(), v.Identities.AuthorizedVoter.PubKey(),
+		)
+	default:
+		return voteAccount, "", fmt.Errorf(
+			"vote account %s authorized voter is %s, not the active identity %s - set identities.authorized_voter to its keypair",
+			voteAccount.VotePubkey, voteAccount.AuthorizedVoter, v.Identities.Active.PubKey(),
+		)
+	}
+
+	v.logger.Debug("vote account check passed",
+		"vote_account", voteAccount.VotePubkey,
+		"authorized_voter", voteAccount.AuthorizedVoter,
+		"authorized_voter_source", authorizedVoterSource,
+		"delinquent", voteAccount.Delinquent,
+	)
+	return voteAccount, authorizedVoterSource, nil
+}
+
+// checkNotDelinquent returns an error when the vote account is delinquent, unless allowDelinquent
+// is set - failing over away from a delinquent node is often the point, but it should be a decision.
+func (v *Validator) checkNotDelinquent(voteAccount *solana.VoteAccount, allowDelinquent bool) error {
+	if !voteAccount.Delinquent {
+		return nil
+	}
+	if !allowDelinquent {
+		return fmt.Errorf(
+			"vote account %s is delinquent (last vote %d) - set --allow-delinquent to fail over anyway",
+			voteAccount.VotePubkey, voteAccount.LastVote,
+		)
+	}
+	v.logger.Warn("--allow-delinquent flag is set, failing over with a delinquent vote account",
+		"vote_account", voteAccount.VotePubkey,
+		"last_vote", voteAccount.LastVote,
+	)
+	return nil
+}
@@ -0,0 +1,240 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	solanapkg "github.com/sol-strategies/solana-validator-failover/internal/solana"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPreflightValidator returns a test validator with keypair identities and a set identity
+// active command that references the active keypair file
+func createPreflightValidator(t *testing.T) *TestValidator {
+	t.Helper()
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.SetIdentityActiveCmd = utils.Command{Argv: []string{"agave-validator", "set-identity", validator.Identities.Active.KeyFile, "--require-tower"}}
+	validator.SetIdentityActiveCommand = validator.SetIdentityActiveCmd.String()
+	return validator
+}
+
+func TestCheckActiveKeypair_Success(t *testing.T) {
+	validator := createPreflightValidator(t)
+
+	assert.NoError(t, validator.checkActiveKeypair())
+}
+
+func TestCheckActiveKeypair_PubkeyOnly(t *testing.T) {
+	validator := createTestValidator(t)
+	require.NoError(t, validator.configureIdentities(identities.Config{
+		ActivePubkey:  solana.NewWallet().PublicKey().String(),
+		PassivePubkey: solana.NewWallet().PublicKey().String(),
+	}))
+
+	err := validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "pubkey-only mode")
+}
+
+func TestCheckActiveKeypair_FileRemoved(t *testing.T) {
+	validator := createPreflightValidator(t)
+	require.NoError(t, os.Remove(validator.Identities.Active.KeyFile))
+
+	err := validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no such file or directory")
+}
+
+func TestCheckActiveKeypair_UnsafePermissions(t *testing.T) {
+	validator := createPreflightValidator(t)
+	require.NoError(t, os.Chmod(validator.Identities.Active.KeyFile, 0o644))
+
+	err := validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "permissions 0644")
+}
+
+func TestCheckActiveKeypair_Replaced(t *testing.T) {
+	validator := createPreflightValidator(t)
+	// a different keypair written over the file after startup
+	other := createTestKeyFile(t, t.TempDir(), "other.json")
+	data, err := os.ReadFile(other)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(validator.Identities.Active.KeyFile, data, 0o600))
+
+	err = validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected "+validator.Identities.Active.PubKey())
+}
+
+func TestCheckActiveKeypair_ActivePubkeyMismatch(t *testing.T) {
+	validator := createPreflightValidator(t)
+	validator.activePubkey = solana.NewWallet().PublicKey().String()
+
+	err := validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected "+validator.activePubkey)
+}
+
+func TestCheckActiveKeypair_CommandDoesNotReferenceKeyFile(t *testing.T) {
+	validator := createPreflightValidator(t)
+	validator.SetIdentityActiveCmd = utils.Command{Argv: []string{"agave-validator", "set-identity", "--require-tower"}}
+
+	err := validator.checkActiveKeypair()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not reference the active keypair file")
+}
+
+// createCatchUpValidator returns a test validator whose local node is at localSlot and the cluster at clusterSlot
+func createCatchUpValidator(t *testing.T, localSlot, clusterSlot uint64) *TestValidator {
+	t.Helper()
+	validator := createTestValidator(t)
+	require.NoError(t, validator.configureCatchUp(CatchUpConfig{MaxSlotLag: 16, Commitment: "processed"}))
+	validator.solanaRPCClient = solanapkg.NewMockClient().WithGetLocalNodeSlotLag(func(commitment rpc.CommitmentType) (solanapkg.SlotLag, error) {
+		return solanapkg.SlotLag{Commitment: commitment, LocalSlot: localSlot, ClusterSlot: clusterSlot}, nil
+	})
+	return validator
+}
+
+func TestCheckCaughtUp_WithinMaxLag(t *testing.T) {
+	validator := createCatchUpValidator(t, 1000, 1016)
+
+	lag, err := validator.checkCaughtUp(1010)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), lag.Behind())
+	assert.Equal(t, rpc.CommitmentProcessed, lag.Commitment)
+}
+
+func TestCheckCaughtUp_BehindCluster(t *testing.T) {
+	validator := createCatchUpValidator(t, 1000, 1017)
+
+	_, err := validator.checkCaughtUp(0)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "17 slots behind (local 1000, cluster 1017, processed)")
+}
+
+func TestCheckCaughtUp_BehindActiveNode(t *testing.T) {
+	// the cluster rpc is itself lagging, the active node is not
+	validator := createCatchUpValidator(t, 1000, 1000)
+
+	_, err := validator.checkCaughtUp(1050)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "50 slots behind the active node")
+}
+
+func TestCheckCaughtUp_AheadOfActiveNode(t *testing.T) {
+	validator := createCatchUpValidator(t, 1100, 1100)
+
+	_, err := validator.checkCaughtUp(1000)
+
+	assert.NoError(t, err)
+}
+
+func TestCheckCaughtUp_RPCError(t *testing.T) {
+	validator := createTestValidator(t)
+	validator.solanaRPCClient = solanapkg.NewMockClient().WithGetLocalNodeSlotLag(func(commitment rpc.CommitmentType) (solanapkg.SlotLag, error) {
+		return solanapkg.SlotLag{}, errors.New("connection refused")
+	})
+
+	_, err := validator.checkCaughtUp(0)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+// voteAccountClient returns a mock client whose active identity's vote account has authorizedVoter
+func voteAccountClient(authorizedVoter string, err error) *solanapkg.MockClient {
+	return solanapkg.NewMockClient().WithGetVoteAccountForIdentity(func(identity string) (*solanapkg.VoteAccount, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &solanapkg.VoteAccount{VotePubkey: "Vote111111111111111111111111111111111111111", NodePubkey: identity, AuthorizedVoter: authorizedVoter}, nil
+	})
+}
+
+func TestCheckVoteAccount_ActiveIdentityIsVoter(t *testing.T) {
+	validator := createPreflightValidator(t)
+	validator.solanaRPCClient = voteAccountClient(validator.Identities.Active.PubKey(), nil)
+
+	voteAccount, source, err := validator.checkVoteAccount()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Vote111111111111111111111111111111111111111", voteAccount.VotePubkey)
+	assert.Equal(t, "active identity", source)
+}
+
+func TestCheckVoteAccount_AuthorizedVoterKeypair(t *testing.T) {
+	validator := createPreflightValidator(t)
+	voter, err := identities.NewIdentityFromFile(createTestKeyFile(t, t.TempDir(), "authorized-voter.json"))
+	require.NoError(t, err)
+	validator.Identities.AuthorizedVoter = voter
+	validator.solanaRPCClient = voteAccountClient(voter.PubKey(), nil)
+
+	_, source, err := validator.checkVoteAccount()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "authorized_voter "+voter.KeyFile, source)
+}
+
+func TestCheckVoteAccount_VoterMismatch(t *testing.T) {
+	validator := createPreflightValidator(t)
+	otherVoter := solana.NewWallet().PublicKey().String()
+	validator.solanaRPCClient = voteAccountClient(otherVoter, nil)
+
+	voteAccount, _, err := validator.checkVoteAccount()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "authorized voter is "+otherVoter)
+	assert.Contains(t, err.Error(), "set identities.authorized_voter")
+	assert.NotNil(t, voteAccount, "the vote account is still returned for display")
+
+	// a configured authorized voter that isn't it doesn't help
+	voter, err := identities.NewIdentityFromFile(createTestKeyFile(t, t.TempDir(), "authorized-voter.json"))
+	require.NoError(t, err)
+	validator.Identities.AuthorizedVoter = voter
+
+	_, _, err = validator.checkVoteAccount()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "identities.authorized_voter "+voter.PubKey())
+}
+
+func TestCheckVoteAccount_NotFound(t *testing.T) {
+	validator := createPreflightValidator(t)
+	validator.solanaRPCClient = voteAccountClient("", solanapkg.ErrVoteAccountNotFound)
+
+	_, _, err := validator.checkVoteAccount()
+
+	assert.ErrorIs(t, err, solanapkg.ErrVoteAccountNotFound)
+}
+
+func TestCheckNotDelinquent(t *testing.T) {
+	validator := createPreflightValidator(t)
+	voteAccount := &solanapkg.VoteAccount{VotePubkey: "Vote111111111111111111111111111111111111111", LastVote: 100}
+
+	assert.NoError(t, validator.checkNotDelinquent(voteAccount, false), "a voting account passes")
+
+	voteAccount.Delinquent = true
+	err := validator.checkNotDelinquent(voteAccount, false)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is delinquent")
+	assert.Contains(t, err.Error(), "--allow-delinquent")
+
+	assert.NoError(t, validator.checkNotDelinquent(voteAccount, true), "--allow-delinquent overrides it")
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
+	"github.com/sol-strategies/solana-validator-failover/internal/solana"
+)
+
+// Status is a point-in-time view of this validator as the failover tool sees it
+type Status struct {
+	Hostname                string
+	PublicIP                string
+	Role                    string
+	GossipPubkey            string
+	ActivePubkey            string
+	PassivePubkey           string
+	ClientVersion           string
+	ClientVersionRPC        string
+	Health                  string
+	CurrentSlot             uint64
+	Epoch                   uint64
+	IsOnLeaderSchedule      bool
+	TimeToNextLeaderSlot    time.Duration
+	LeaderScheduleError     error
+	MinimumTimeToLeaderSlot time.Duration
+	SlotDuration            solana.SlotDuration
+	VoteAccount             *solana.VoteAccount // nil when it could not be looked up
+	AuthorizedVoterSource   string              // which configured key is the authorized voter, empty when none is
+	VoteAccountError        error
+	Peers                   Peers
+}
+
+// Status queries the local node and cluster for this validator's current status. Only a
+// failure to read the current epoch is fatal - other lookups are reported in the result.
+func (v *Validator) Status() (status *Status, err error) {
+	status = &Status{
+		Hostname:                v.Hostname,
+		PublicIP:                v.PublicIP,
+		Role:                    "unknown",
+		GossipPubkey:            v.GossipNode.PubKey(),
+		ActivePubkey:            v.Identities.Active.PubKey(),
+		PassivePubkey:           v.Identities.Passive.PubKey(),
+		ClientVersion:           v.GossipNode.Version(),
+		ClientVersionRPC:        v.getLocalNodeVersion(),
+		MinimumTimeToLeaderSlot: v.MinimumTimeToLeaderSlot,
+		SlotDuration:            v.solanaRPCClient.GetSlotDuration(),
+		Peers:                   v.Peers,
+	}
+
+	switch {
+	case v.IsActive():
+		status.Role = constants.NodeRoleActive
+	case v.IsPassive():
+		status.Role = constants.NodeRolePassive
+	}
+
+	status.Health, err = v.solanaRPCClient.GetLocalNodeHealth()
+	if err != nil {
+		status.Health = fmt.Sprintf("unhealthy: %v", err)
+	}
+
+	epochInfo, err := v.solanaRPCClient.GetEpochInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	status.CurrentSlot = epochInfo.AbsoluteSlot
+	status.Epoch = epochInfo.Epoch
+
+	pubkey, err := solanago.PublicKeyFromBase58(status.ActivePubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse active identity pubkey: %w", err)
+	}
+	status.IsOnLeaderSchedule, status.TimeToNextLeaderSlot, status.LeaderScheduleError = v.solanaRPCClient.GetTimeToNextLeaderSlotForPubkey(pubkey)
+	status.VoteAccount, status.AuthorizedVoterSource, status.VoteAccountError = v.checkVoteAccount()
+
+	return status, nil
+}
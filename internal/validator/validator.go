@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	gotls "crypto/tls"
+	"errors"
 	"fmt"
 	"html/template"
 	"net"
@@ -14,10 +15,14 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/charmbracelet/log"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sol-strategies/solana-validator-failover/internal/agave"
 	"github.com/sol-strategies/solana-validator-failover/internal/constants"
 	"github.com/sol-strategies/solana-validator-failover/internal/failover"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
+	"github.com/sol-strategies/solana-validator-failover/internal/notifications"
 	"github.com/sol-strategies/solana-validator-failover/internal/solana"
 	"github.com/sol-strategies/solana-validator-failover/internal/style"
 	"github.com/sol-strategies/solana-validator-failover/internal/utils"
@@ -31,9 +36,13 @@ type FailoverParams struct {
 	NoMinTimeToLeaderSlot bool
 	MinTimeToLeaderSlot   time.Duration
 	SkipTowerSync         bool
-	AutoConfirm           bool   // -y/--yes: skip all interactive confirmations
-	ToPeer                string // --to-peer: auto-select peer by name or IP (active node only)
-	RollbackEnabled       bool   // --rollback-enabled/-r: force-enable rollback regardless of config
+	AutoConfirm           bool              // -y/--yes: skip all interactive confirmations
+	ToPeer                string            // --to-peer: auto-select peer by name or IP
+	RollbackEnabled       bool              // --rollback-enabled/-r: force-enable rollback regardless of config
+	Pull                  bool              // --pull: passive node initiates the failover by connecting to the active node
+	Schedule              failover.Schedule // --at-slot/--at-time/--at-epoch-boundary: when to execute the failover
+	WaitForWindow         time.Duration     // --wait-for-window: hold the failover until a leader-free window this long
+	AllowDelinquent       bool              // --allow-delinquent: fail over even if the vote account is delinquent
 }
 
 // Peers is a map of peers
@@ -55,6 +64,8 @@ type BinMetadata struct {
 type Validator struct {
 	Bin                            string
 	BinMetadata                    BinMetadata
+	ClientProfile                  ClientProfile
+	ClientConfig                   string
 	FailoverServerConfig           ServerConfig
 	MonitorConfig                  MonitorConfig
 	GossipNode                     *solana.Node
@@ -63,19 +74,29 @@ type Validator struct {
 	Identities                     *identities.Identities
 	LedgerDir                      string
 	MinimumTimeToLeaderSlot        time.Duration
+	CatchUpMaxSlotLag              uint64             // slots a passive node may be behind the cluster or active node and still become active
+	CatchUpCommitment              rpc.CommitmentType // commitment the catch-up check compares slots at
 	Peers                          Peers
 	PublicIP                       string
 	RPCAddress                     string
-	SetIdentityActiveCommand       string
-	SetIdentityPassiveCommand      string
+	Cluster                        string
+	SetIdentityActiveCommand       string // SetIdentityActiveCmd shell-quoted, for display
+	SetIdentityPassiveCommand      string // SetIdentityPassiveCmd shell-quoted, for display
+	SetIdentityActiveCmd           utils.Command
+	SetIdentityPassiveCmd          utils.Command
+	SetIdentityMode                string
+	SetIdentityTimeout             time.Duration // default timeout for set identity and rollback commands, 0 for none
 	TowerFile                      string
 	TowerFileAutoDeleteWhenPassive bool
 	Rollback                       hooks.RollbackConfig
+	Notifier                       *notifications.Dispatcher
 
 	logger          *log.Logger
 	solanaRPCClient solana.ClientInterface
-	serverTLSConfig *gotls.Config // non-nil when mTLS is enabled; used by the passive QUIC server
-	clientTLSConfig *gotls.Config // non-nil when mTLS is enabled; used by the active QUIC client
+	adminRPC        *agave.AdminRPCClient // non-nil when set-identity goes over the admin rpc socket
+	activePubkey    string                // identities.active_pubkey, which the active keypair file must match when both are set
+	serverTLSConfig *gotls.Config         // non-nil when mTLS is enabled; used by the passive QUIC server
+	clientTLSConfig *gotls.Config         // non-nil when mTLS is enabled; used by the active QUIC client
 }
 
 // NewSolanaRPCClient creates a new Solana RPC client
@@ -104,7 +125,7 @@ func (v *Validator) NewFromConfig(cfg *Config) error {
 	defer v.logger.Debug("configuration done")
 
 	// configure solana rpc clients all in one
-	err := v.configureRPCClient(cfg.RPCAddress, cfg.Cluster, cfg.ClusterRPCURL, cfg.AverageSlotDuration)
+	err := v.configureRPCClient(cfg.RPCAddress, cfg.RPCWSAddress, cfg.Cluster, cfg.ClusterRPCURL, cfg.AverageSlotDuration)
 	if err != nil {
 		return err
 	}
@@ -121,6 +142,12 @@ func (v *Validator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
+	// client profile - supplies defaults for the tower file and set identity commands
+	err = v.configureClient(cfg.Client, cfg.ClientConfig)
+	if err != nil {
+		return err
+	}
+
 	// configure identities
 	err = v.configureIdentities(cfg.Identities)
 	if err != nil {
@@ -139,14 +166,20 @@ func (v *Validator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
-	// configure hooks
-	err = v.configureHooks(cfg.Failover)
+	// set identity mode configure - after the commands, which admin_rpc falls back to
+	err = v.configureSetIdentityMode(cfg.Failover)
 	if err != nil {
 		return err
 	}
 
-	// configure rollback
-	err = v.configureRollback(cfg.Failover)
+	// configure hooks and rollback
+	err = v.configureHooksAndRollback(cfg.Failover)
+	if err != nil {
+		return err
+	}
+
+	// configure notifications
+	err = v.configureNotifications(cfg.Failover)
 	if err != nil {
 		return err
 	}
@@ -169,6 +202,12 @@ func (v *Validator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
+	// how far behind a passive node may be and still become active
+	err = v.configureCatchUp(cfg.Failover.CatchUp)
+	if err != nil {
+		return err
+	}
+
 	// get hostname
 	err = v.configureHostname(cfg.Name)
 	if err != nil {
@@ -242,14 +281,24 @@ func (v *Validator) Failover(params FailoverParams) (err error) {
 	}
 
 	// passive node path
-	if params.ToPeer != "" {
-		log.Warn("--to-peer flag is only applicable when run on an active node - ignoring", "to_peer", params.ToPeer)
+	if params.ToPeer != "" && !params.Pull {
+		log.Warn("--to-peer flag is only applicable on a passive node when --pull is set - ignoring", "to_peer", params.ToPeer)
 	}
 	return v.makeActive(params)
 }
 
+// LeaderFreeWindows returns the leader-free windows for the active identity over the current
+// and next epoch
+func (v *Validator) LeaderFreeWindows() (*solana.LeaderFreeWindows, error) {
+	pubkey, err := solanago.PublicKeyFromBase58(v.Identities.Active.PubKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse active identity pubkey: %w", err)
+	}
+	return v.solanaRPCClient.GetLeaderFreeWindows(pubkey)
+}
+
 // configureRPCClient configures the solana rpc client
-func (v *Validator) configureRPCClient(localRPCURL, solanaClusterName, clusterRPCURL, averageSlotDuration string) error {
+func (v *Validator) configureRPCClient(localRPCURL, localWSURL, solanaClusterName, clusterRPCURL, averageSlotDuration string) error {
 	if solanaClusterName == "" {
 		return fmt.Errorf("cluster is required")
 	}
@@ -261,6 +310,14 @@ func (v *Validator) configureRPCClient(localRPCURL, solanaClusterName, clusterRP
 		)
 	}
 
+	// optional - derived from the rpc address when not set
+	if localWSURL != "" && !utils.IsValidURLWithPort(localWSURL) {
+		return fmt.Errorf(
+			"invalid rpc ws address: %s, must be a valid url with a port",
+			localWSURL,
+		)
+	}
+
 	// determine the cluster RPC URL: use built-in URL for known clusters,
 	// otherwise require cluster_rpc_url from config
 	var solanaClusterRPCURL string
@@ -285,12 +342,15 @@ func (v *Validator) configureRPCClient(localRPCURL, solanaClusterName, clusterRP
 	v.logger.Debug("rpc client configured",
 		"cluster", solanaClusterName,
 		"local_rpc_url", localRPCURL,
+		"local_ws_url", localWSURL,
 		"cluster_rpc_url", solanaClusterRPCURL,
 	)
 
 	v.RPCAddress = localRPCURL
+	v.Cluster = solanaClusterName
 	v.solanaRPCClient = v.NewSolanaRPCClient(solana.NewClientParams{
 		LocalRPCURL:         localRPCURL,
+		LocalWSURL:          localWSURL,
 		ClusterRPCURL:       solanaClusterRPCURL,
 		AverageSlotDuration: avgSlotDuration,
 	})
@@ -326,6 +386,7 @@ func (v *Validator) configureIdentities(identitiesConfig identities.Config) (err
 	if err != nil {
 		return err
 	}
+	v.activePubkey = identitiesConfig.ActivePubkey
 
 	v.logger.Debug("identities set",
 		"active_pubkey", v.Identities.Active.PubKey(),
@@ -350,6 +411,11 @@ func (v *Validator) configureTowerFile(cfg TowerConfig) error {
 		return err
 	}
 
+	// tower file name template defaults to the client profile's
+	if cfg.FileNameTemplate == "" {
+		cfg.FileNameTemplate = v.ClientProfile.TowerFileNameTemplate
+	}
+
 	// tower file name template must be valid
 	towerFileNameTemplate, err := template.New("tower").Parse(cfg.FileNameTemplate)
 	if err != nil {
@@ -377,60 +443,46 @@ func (v *Validator) configureTowerFile(cfg TowerConfig) error {
 	return nil
 }
 
-// configureSetIdenttiyCommands ensures the set identity commands are valid and sets them
+// configureSetIdenttiyCommands ensures the set identity commands are valid and sets them. Each
+// command comes from its argv config, else its template string, else the client profile's template.
 func (v *Validator) configureSetIdenttiyCommands(cfg FailoverConfig) (err error) {
-	var (
-		setIdentityActiveCmdBuf  strings.Builder
-		setIdentityPassiveCmdBuf strings.Builder
-	)
-
-	// parse active command template
-	setIdentityActiveCmdTemplate, err := template.New("set_identity_active_cmd").
-		Parse(cfg.SetIdentityActiveCmdTemplate)
-	if err != nil {
-		return fmt.Errorf(
-			"failed to parse set identity active cmd template %s: %w",
-			cfg.SetIdentityActiveCmdTemplate,
-			err,
-		)
+	// templates default to the client profile's
+	usingProfileTemplate := false
+	if cfg.SetIdentityActive.IsZero() && cfg.SetIdentityActiveCmdTemplate == "" {
+		cfg.SetIdentityActiveCmdTemplate = v.ClientProfile.SetIdentityActiveCmdTemplate
+		usingProfileTemplate = true
+	}
+	if cfg.SetIdentityPassive.IsZero() && cfg.SetIdentityPassiveCmdTemplate == "" {
+		cfg.SetIdentityPassiveCmdTemplate = v.ClientProfile.SetIdentityPassiveCmdTemplate
+		usingProfileTemplate = true
+	}
+	if usingProfileTemplate && v.ClientProfile.RequiresClientConfig && v.ClientConfig == "" {
+		return fmt.Errorf("validator.client_config must be set for the default %s set identity commands, or set failover.set_identity_*_cmd_template", v.ClientProfile.Name)
 	}
-	v.logger.Debug("set identity active command template set", "template", cfg.SetIdentityActiveCmdTemplate)
 
-	// set identity active command must compile
-	if err := setIdentityActiveCmdTemplate.Execute(&setIdentityActiveCmdBuf, v); err != nil {
-		return fmt.Errorf(
-			"failed to execute set identity active cmd template %s: %w",
-			cfg.SetIdentityActiveCmdTemplate,
-			err,
-		)
+	// commands without their own timeout get the set identity timeout
+	if cfg.SetIdentityTimeout != "" {
+		v.SetIdentityTimeout, err = time.ParseDuration(cfg.SetIdentityTimeout)
+		if err != nil || v.SetIdentityTimeout < 0 {
+			return fmt.Errorf("invalid failover.set_identity_timeout %q: must be a duration like 30s, or 0 for none", cfg.SetIdentityTimeout)
+		}
 	}
 
 	// set identity active command
-	v.SetIdentityActiveCommand = setIdentityActiveCmdBuf.String()
-	v.logger.Debug("set identity active command set", "command", v.SetIdentityActiveCommand)
-
-	// parse passive command template
-	setIdentityPassiveCmdTemplate, err := template.New("set_identity_passive_cmd").
-		Parse(cfg.SetIdentityPassiveCmdTemplate)
+	v.SetIdentityActiveCmd, err = v.resolveCommand("set_identity_active", cfg.SetIdentityActive, cfg.SetIdentityActiveCmdTemplate)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to parse set identity passive cmd template %s: %w",
-			cfg.SetIdentityPassiveCmdTemplate,
-			err,
-		)
+		return err
 	}
-	v.logger.Debug("set identity passive command template set", "template", cfg.SetIdentityPassiveCmdTemplate)
+	v.SetIdentityActiveCommand = v.SetIdentityActiveCmd.String()
+	v.logger.Debug("set identity active command set", "command", v.SetIdentityActiveCommand, "timeout", v.SetIdentityActiveCmd.Timeout)
 
-	// set identity passive command must compile
-	if err := setIdentityPassiveCmdTemplate.Execute(&setIdentityPassiveCmdBuf, v); err != nil {
-		return fmt.Errorf(
-			"failed to execute set identity passive cmd template %s: %w",
-			cfg.SetIdentityPassiveCmdTemplate,
-			err,
-		)
+	// set identity passive command
+	v.SetIdentityPassiveCmd, err = v.resolveCommand("set_identity_passive", cfg.SetIdentityPassive, cfg.SetIdentityPassiveCmdTemplate)
+	if err != nil {
+		return err
 	}
-	v.SetIdentityPassiveCommand = setIdentityPassiveCmdBuf.String()
-	v.logger.Debug("set identity passive command set", "command", v.SetIdentityPassiveCommand)
+	v.SetIdentityPassiveCommand = v.SetIdentityPassiveCmd.String()
+	v.logger.Debug("set identity passive command set", "command", v.SetIdentityPassiveCommand, "timeout", v.SetIdentityPassiveCmd.Timeout)
 
 	// if the commands are the same, warn - could be intentional or a mistake
 	if v.SetIdentityActiveCommand == v.SetIdentityPassiveCommand {
@@ -440,9 +492,185 @@ func (v *Validator) configureSetIdenttiyCommands(cfg FailoverConfig) (err error)
 	return nil
 }
 
+// renderCmdTemplateArgv renders a <name>_cmd_template string into an argv. The template is split
+// into words first and each word rendered on its own. A template whose actions span words, e.g.
+// {{ if .X }} --flag{{ end }}, is rendered whole and then split, so values with spaces must be
+// quoted in it.
+func (v *Validator) renderCmdTemplateArgv(name, cmdTemplate string, render func(field, text string) (string, error)) ([]string, error) {
+	words, err := utils.SplitShellWords(cmdTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split %s_cmd_template: %w", name, err)
+	}
+
+	argv := make([]string, 0, len(words))
+	for _, word := range words {
+		if _, err := template.New(name).Parse(word); err != nil {
+			argv = nil
+			break
+		}
+		arg, err := render("cmd_template", word)
+		if err != nil {
+			return nil, err
+		}
+		// a word that renders empty, e.g. {{ if .X }}--flag{{ end }}, is left out
+		if arg != "" {
+			argv = append(argv, arg)
+		}
+	}
+	if argv != nil {
+		return argv, nil
+	}
+
+	rendered, err := render("cmd_template", cmdTemplate)
+	if err != nil {
+		return nil, err
+	}
+	argv, err = utils.SplitShellWords(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split rendered %s_cmd_template: %w", name, err)
+	}
+	return argv, nil
+}
+
+// resolveCommand renders a command configured as an argv list or, when that is unset, the
+// <name>_cmd_template string split into arguments like a shell would. Every argument is rendered
+// on its own against the validator, so a rendered path containing spaces stays one argument.
+func (v *Validator) resolveCommand(name string, cmdCfg hooks.CommandConfig, cmdTemplate string) (cmd utils.Command, err error) {
+	render := func(field, text string) (string, error) {
+		tpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s %s %q: %w", name, field, text, err)
+		}
+		var buf strings.Builder
+		if err := tpl.Execute(&buf, v); err != nil {
+			return "", fmt.Errorf("failed to execute %s %s %q: %w", name, field, text, err)
+		}
+		return buf.String(), nil
+	}
+
+	cmd.Timeout = v.SetIdentityTimeout
+	if cmdCfg.IsZero() {
+		cmd.Argv, err = v.renderCmdTemplateArgv(name, cmdTemplate, render)
+		if err != nil {
+			return cmd, err
+		}
+		if cmd.IsZero() {
+			return cmd, fmt.Errorf("%s_cmd_template %q renders an empty command", name, cmdTemplate)
+		}
+		return cmd, nil
+	}
+
+	bin, err := render("cmd", cmdCfg.Cmd)
+	if err != nil {
+		return cmd, err
+	}
+	if bin == "" {
+		return cmd, fmt.Errorf("%s.cmd %q renders an empty command", name, cmdCfg.Cmd)
+	}
+	cmd.Argv = append(cmd.Argv, bin)
+	for i, a := range cmdCfg.Args {
+		arg, err := render(fmt.Sprintf("args[%d]", i), a)
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Argv = append(cmd.Argv, arg)
+	}
+	if len(cmdCfg.Env) > 0 {
+		cmd.Env = make(map[string]string, len(cmdCfg.Env))
+		for k, e := range cmdCfg.Env {
+			cmd.Env[k], err = render("env."+k, e)
+			if err != nil {
+				return cmd, err
+			}
+		}
+	}
+	if cmdCfg.Timeout != "" {
+		cmd.Timeout, err = time.ParseDuration(cmdCfg.Timeout)
+		if err != nil {
+			return cmd, fmt.Errorf("invalid %s.timeout %q: %w", name, cmdCfg.Timeout, err)
+		}
+		if cmd.Timeout <= 0 {
+			return cmd, fmt.Errorf("invalid %s.timeout %q: must be positive", name, cmdCfg.Timeout)
+		}
+	}
+	return cmd, nil
+}
+
+// configureSetIdentityMode sets how set-identity is done. In admin_rpc mode the identity is switched
+// over the validator's admin rpc socket, which must answer now - otherwise the set-identity
+// commands are used.
+func (v *Validator) configureSetIdentityMode(cfg FailoverConfig) error {
+	v.adminRPC = nil
+	switch cfg.SetIdentityMode {
+	case "", constants.SetIdentityModeCommand:
+		v.SetIdentityMode = constants.SetIdentityModeCommand
+		return nil
+	case constants.SetIdentityModeAdminRPC:
+	default:
+		return fmt.Errorf("invalid failover.set_identity_mode %q, must be one of: %s, %s",
+			cfg.SetIdentityMode, constants.SetIdentityModeCommand, constants.SetIdentityModeAdminRPC)
+	}
+
+	if !v.ClientProfile.SupportsAdminRPC {
+		return fmt.Errorf("failover.set_identity_mode %s is not supported by the %s client", constants.SetIdentityModeAdminRPC, v.ClientProfile.Name)
+	}
+
+	// the keypairs are sent to the validator, so they must have been loaded from files
+	if v.Identities.Active.Key == nil || v.Identities.Passive.Key == nil {
+		return fmt.Errorf("failover.set_identity_mode %s requires identities.active and identities.passive keypair files", constants.SetIdentityModeAdminRPC)
+	}
+
+	client := agave.NewAdminRPCClient(v.LedgerDir)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	contactInfo, err := client.ContactInfo(ctx)
+	if err != nil {
+		v.SetIdentityMode = constants.SetIdentityModeCommand
+		v.logger.Warn("admin rpc not answering - falling back to set-identity commands", "socket", client.SocketPath(), "err", err)
+		return nil
+	}
+	if contactInfo.ID != v.Identities.Active.PubKey() && contactInfo.ID != v.Identities.Passive.PubKey() {
+		return fmt.Errorf("admin rpc at %s reports identity %s, which is neither the active (%s) nor passive (%s) identity",
+			client.SocketPath(), contactInfo.ID, v.Identities.Active.PubKey(), v.Identities.Passive.PubKey())
+	}
+
+	v.SetIdentityMode = constants.SetIdentityModeAdminRPC
+	v.adminRPC = client
+	v.logger.Debug("set identity mode set", "mode", v.SetIdentityMode, "socket", client.SocketPath(), "identity", contactInfo.ID)
+	return nil
+}
+
+// failoverAdminRPC returns the admin rpc client for failover configs - a nil *agave.AdminRPCClient
+// must not become a non-nil interface
+func (v *Validator) failoverAdminRPC() failover.AdminRPC {
+	if v.adminRPC == nil {
+		return nil
+	}
+	return v.adminRPC
+}
+
+// adminRPCSocket returns the admin rpc socket set-identity goes over, empty when using commands
+func (v *Validator) adminRPCSocket() string {
+	if v.adminRPC == nil {
+		return ""
+	}
+	return v.adminRPC.SocketPath()
+}
+
+// configureHooksAndRollback configures the failover hooks and rollback, reporting every problem
+// in either at once
+func (v *Validator) configureHooksAndRollback(cfg FailoverConfig) error {
+	return errors.Join(v.configureHooks(cfg), v.configureRollback(cfg))
+}
+
 // configureHooks ensures the hooks are valid and sets them
 func (v *Validator) configureHooks(cfg FailoverConfig) (err error) {
 	v.Hooks = cfg.Hooks
+	v.Hooks.Outputs = hooks.NewOutputs()
+	// render every template and resolve every command now - a typo found mid-failover is too late
+	if err := errors.Join(v.Hooks.Configure(), v.Hooks.Validate(hooks.SampleTemplateData())); err != nil {
+		return fmt.Errorf("invalid failover.hooks: %w", err)
+	}
 	v.logger.Debug("hooks set",
 		"pre_when_active", len(v.Hooks.Pre.WhenActive),
 		"pre_when_passive", len(v.Hooks.Pre.WhenPassive),
@@ -450,16 +678,28 @@ func (v *Validator) configureHooks(cfg FailoverConfig) (err error) {
 		"post_when_passive", len(v.Hooks.Post.WhenPassive),
 	)
 	for _, h := range v.Hooks.Pre.WhenActive {
-		v.logger.Debug("pre hook (when active)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed)
+		v.logger.Debug("pre hook (when active)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed, "timeout", h.TimeoutDuration, "retries", h.Retries)
 	}
 	for _, h := range v.Hooks.Pre.WhenPassive {
-		v.logger.Debug("pre hook (when passive)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed)
+		v.logger.Debug("pre hook (when passive)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed, "timeout", h.TimeoutDuration, "retries", h.Retries)
 	}
 	for _, h := range v.Hooks.Post.WhenActive {
-		v.logger.Debug("post hook (when active)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed)
+		v.logger.Debug("post hook (when active)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed, "timeout", h.TimeoutDuration, "retries", h.Retries)
 	}
 	for _, h := range v.Hooks.Post.WhenPassive {
-		v.logger.Debug("post hook (when passive)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed)
+		v.logger.Debug("post hook (when passive)", "name", h.Name, "command", h.Command, "args", h.Args, "must_succeed", h.MustSucceed, "timeout", h.TimeoutDuration, "retries", h.Retries)
+	}
+	return nil
+}
+
+// configureNotifications validates the notification targets and sets up their dispatcher
+func (v *Validator) configureNotifications(cfg FailoverConfig) (err error) {
+	v.Notifier, err = notifications.New(cfg.Notifications)
+	if err != nil {
+		return fmt.Errorf("invalid failover.notifications: %w", err)
+	}
+	for _, n := range cfg.Notifications {
+		v.logger.Debug("notification", "name", n.Name, "type", n.Type, "events", n.Events)
 	}
 	return nil
 }
@@ -468,42 +708,60 @@ func (v *Validator) configureHooks(cfg FailoverConfig) (err error) {
 // rollback commands alongside any configured rollback hooks.
 // If a cmd_template is empty, it falls back to the corresponding set-identity command.
 func (v *Validator) configureRollback(cfg FailoverConfig) error {
+	var errs []error
 	v.Rollback.Enabled = cfg.Rollback.Enabled
-	v.Rollback.ToActive.Hooks = cfg.Rollback.ToActive.Hooks
-	v.Rollback.ToPassive.Hooks = cfg.Rollback.ToPassive.Hooks
-
-	// resolve to-active rollback command
-	if cfg.Rollback.ToActive.CmdTemplate == "" {
-		v.Rollback.ToActive.ResolvedCmd = v.SetIdentityActiveCommand
-	} else {
-		tpl, err := template.New("rollback_to_active_cmd").Parse(cfg.Rollback.ToActive.CmdTemplate)
-		if err != nil {
-			return fmt.Errorf("failed to parse rollback.to_active.cmd_template: %w", err)
+	v.Rollback.VerifyHandover = cfg.Rollback.VerifyHandover
+	if v.Rollback.VerifyHandover.Enabled {
+		if v.Rollback.VerifyHandover.WithinSlots < 1 {
+			errs = append(errs, fmt.Errorf("rollback.verify_handover.within_slots must be >= 1, got %d", v.Rollback.VerifyHandover.WithinSlots))
 		}
-		var buf strings.Builder
-		if err := tpl.Execute(&buf, v); err != nil {
-			return fmt.Errorf("failed to execute rollback.to_active.cmd_template: %w", err)
+		timeout, err := time.ParseDuration(v.Rollback.VerifyHandover.Timeout)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("invalid rollback.verify_handover.timeout %q: %v", v.Rollback.VerifyHandover.Timeout, err))
+		case timeout <= 0:
+			errs = append(errs, fmt.Errorf("rollback.verify_handover.timeout must be > 0, got %s", v.Rollback.VerifyHandover.Timeout))
+		default:
+			v.Rollback.VerifyHandover.TimeoutDuration = timeout
+		}
+		if !v.Rollback.Enabled {
+			v.logger.Warn("rollback.verify_handover is enabled but rollback is not - the handover will not be verified unless rollback is enabled (rollback.enabled or --rollback-enabled)")
 		}
-		v.Rollback.ToActive.ResolvedCmd = buf.String()
 	}
 
-	// resolve to-passive rollback command
-	if cfg.Rollback.ToPassive.CmdTemplate == "" {
-		v.Rollback.ToPassive.ResolvedCmd = v.SetIdentityPassiveCommand
-	} else {
-		tpl, err := template.New("rollback_to_passive_cmd").Parse(cfg.Rollback.ToPassive.CmdTemplate)
-		if err != nil {
-			return fmt.Errorf("failed to parse rollback.to_passive.cmd_template: %w", err)
-		}
-		var buf strings.Builder
-		if err := tpl.Execute(&buf, v); err != nil {
-			return fmt.Errorf("failed to execute rollback.to_passive.cmd_template: %w", err)
-		}
-		v.Rollback.ToPassive.ResolvedCmd = buf.String()
+	// configure rollback hooks, then render their templates and resolve their commands now rather
+	// than mid-rollback
+	v.Rollback.Outputs = v.Hooks.Outputs // rollback hooks can use failover hook outputs
+	v.Rollback.ToActive.Hooks = cfg.Rollback.ToActive.Hooks
+	v.Rollback.ToPassive.Hooks = cfg.Rollback.ToPassive.Hooks
+	preSample, postSample := hooks.SampleTemplateData(), hooks.SampleTemplateData()
+	preSample.Phase, postSample.Phase = hooks.PhaseRollbackPre, hooks.PhaseRollbackPost
+	errs = append(errs,
+		configureHookSet("rollback.to_active.hooks.pre", v.Rollback.ToActive.Hooks.Pre, preSample),
+		configureHookSet("rollback.to_passive.hooks.pre", v.Rollback.ToPassive.Hooks.Pre, preSample),
+		configureHookSet("rollback.to_active.hooks.post", v.Rollback.ToActive.Hooks.Post, postSample),
+		configureHookSet("rollback.to_passive.hooks.post", v.Rollback.ToPassive.Hooks.Post, postSample),
+	)
+
+	// resolve rollback commands, defaulting to the set-identity commands
+	var err error
+	v.Rollback.ToActive.ResolvedCmd = v.SetIdentityActiveCmd
+	if !cfg.Rollback.ToActive.Command.IsZero() || cfg.Rollback.ToActive.CmdTemplate != "" {
+		v.Rollback.ToActive.ResolvedCmd, err = v.resolveCommand("rollback.to_active", cfg.Rollback.ToActive.Command, cfg.Rollback.ToActive.CmdTemplate)
+		errs = append(errs, err)
+	}
+	v.Rollback.ToPassive.ResolvedCmd = v.SetIdentityPassiveCmd
+	if !cfg.Rollback.ToPassive.Command.IsZero() || cfg.Rollback.ToPassive.CmdTemplate != "" {
+		v.Rollback.ToPassive.ResolvedCmd, err = v.resolveCommand("rollback.to_passive", cfg.Rollback.ToPassive.Command, cfg.Rollback.ToPassive.CmdTemplate)
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
 
 	v.logger.Debug("rollback configured",
 		"enabled", v.Rollback.Enabled,
+		"verify_handover", v.Rollback.VerifyHandover.Enabled,
 		"to_active_cmd", v.Rollback.ToActive.ResolvedCmd,
 		"to_passive_cmd", v.Rollback.ToPassive.ResolvedCmd,
 	)
@@ -511,6 +769,15 @@ func (v *Validator) configureRollback(cfg FailoverConfig) error {
 	return nil
 }
 
+// configureHookSet configures a set of hooks in place and validates them against sample,
+// returning every problem found
+func configureHookSet(name string, hs hooks.Hooks, sample hooks.HookTemplateData) error {
+	if err := errors.Join(hs.Configure(), hs.Validate(sample)); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
+
 // configurePeers ensures the peers are valid and sets them
 func (v *Validator) configurePeers(cfg PeersConfig) (err error) {
 	if len(cfg) == 0 {
@@ -577,6 +844,24 @@ func (v *Validator) configureMinimumTimeToLeaderSlot(timeToLeaderSlotDurationStr
 	return nil
 }
 
+// configureCatchUp validates and sets the catch-up check's max slot lag and commitment
+func (v *Validator) configureCatchUp(cfg CatchUpConfig) error {
+	if cfg.MaxSlotLag < 0 {
+		return fmt.Errorf("invalid failover.catch_up.max_slot_lag %d: must be 0 or more", cfg.MaxSlotLag)
+	}
+	switch commitment := rpc.CommitmentType(cfg.Commitment); commitment {
+	case "":
+		v.CatchUpCommitment = rpc.CommitmentConfirmed
+	case rpc.CommitmentProcessed, rpc.CommitmentConfirmed:
+		v.CatchUpCommitment = commitment
+	default:
+		return fmt.Errorf("invalid failover.catch_up.commitment %q: must be one of %s or %s", cfg.Commitment, rpc.CommitmentProcessed, rpc.CommitmentConfirmed)
+	}
+	v.CatchUpMaxSlotLag = uint64(cfg.MaxSlotLag)
+	v.logger.Debug("catch-up check set", "max_slot_lag", v.CatchUpMaxSlotLag, "commitment", v.CatchUpCommitment)
+	return nil
+}
+
 // GetHostname returns the hostname - can be overridden in tests
 func (v *Validator) GetHostname() (string, error) {
 	return os.Hostname()
@@ -620,12 +905,28 @@ func (v *Validator) configureServer(cfg ServerConfig, monitorCfg MonitorConfig)
 
 	// store the monitor config with parsed duration
 	monitorCfg.CreditSamples.IntervalDuration = duration
+
+	if monitorCfg.Votes.Enabled {
+		if monitorCfg.Votes.WithinSlots < 1 {
+			return fmt.Errorf("votes within_slots must be >= 1, got %d", monitorCfg.Votes.WithinSlots)
+		}
+		monitorCfg.Votes.DurationValue, err = time.ParseDuration(monitorCfg.Votes.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid votes duration %q: %v", monitorCfg.Votes.Duration, err)
+		}
+		if monitorCfg.Votes.DurationValue <= 0 {
+			return fmt.Errorf("votes duration must be > 0, got %s", monitorCfg.Votes.Duration)
+		}
+	}
 	v.MonitorConfig = monitorCfg
 
 	v.logger.Debug("server and monitor config set",
 		"port", v.FailoverServerConfig.Port,
 		"credit_samples_count", v.MonitorConfig.CreditSamples.Count,
 		"credit_samples_interval", v.MonitorConfig.CreditSamples.Interval,
+		"votes_enabled", v.MonitorConfig.Votes.Enabled,
+		"votes_within_slots", v.MonitorConfig.Votes.WithinSlots,
+		"votes_duration", v.MonitorConfig.Votes.Duration,
 	)
 	return nil
 }
@@ -732,6 +1033,28 @@ func (v *Validator) makeActive(params FailoverParams) (err error) {
 		)
 	}
 
+	// this node must have caught up with the cluster - checked again, also against the active
+	// node's slot, just before the active node is told to proceed
+	if params.NoWaitForHealthy {
+		log.Debug("--no-wait-for-healthy flag is set, skipping wait for catch-up")
+	} else if err = v.waitUntilCaughtUp(); err != nil {
+		return fmt.Errorf("failed to wait until caught up: %w", err)
+	}
+
+	// the keypair this node will become active with must be usable - checked again then too
+	if err = v.checkActiveKeypair(); err != nil {
+		return err
+	}
+
+	// the identity being handed over must control a vote account this node can vote with
+	voteAccount, authorizedVoterSource, err := v.checkVoteAccount()
+	if err != nil {
+		return err
+	}
+	if err = v.checkNotDelinquent(voteAccount, params.AllowDelinquent); err != nil {
+		return err
+	}
+
 	// delete the tower file if it exists and auto empty when passive is true
 	if v.TowerFileAutoDeleteWhenPassive && utils.FileExists(v.TowerFile) {
 		log.Debug("deleting tower file because validator.tower.auto_empty_when_passive is true",
@@ -763,7 +1086,16 @@ func (v *Validator) makeActive(params FailoverParams) (err error) {
 		}
 	}
 
-	// create a QUIC server that listens for the active node to connect and decide what to do
+	// in pull mode this node dials the active peer instead of waiting for it to connect
+	var activePeer Peer
+	if params.Pull {
+		activePeer, err = v.selectPeer(params, "Select the active peer to pull from:")
+		if err != nil {
+			return err
+		}
+	}
+
+	// create a QUIC server that listens for the active node to connect (or dials it in pull mode) and decide what to do
 	failoverServer, err := failover.NewServerFromConfig(failover.ServerConfig{
 		Port:              v.FailoverServerConfig.Port,
 		HeartbeatInterval: v.FailoverServerConfig.HeartbeatInterval,
@@ -774,25 +1106,45 @@ func (v *Validator) makeActive(params FailoverParams) (err error) {
 			Identities:                     v.Identities,
 			TowerFile:                      v.TowerFile,
 			SetIdentityCommand:             v.SetIdentityActiveCommand,
+			SetIdentityCmd:                 v.SetIdentityActiveCmd,
+			AdminRPCSocket:                 v.adminRPCSocket(),
+			Client:                         v.ClientProfile.Name,
 			ClientVersion:                  v.GossipNode.Version(),
 			ClientVersionRPC:               v.getLocalNodeVersion(),
 			SolanaValidatorFailoverVersion: pkgconstants.AppVersion,
 			RPCAddress:                     v.RPCAddress,
+			Cluster:                        v.Cluster,
+			SlotDuration:                   v.solanaRPCClient.GetSlotDuration(),
+			VoteAccount:                    *voteAccount,
+			AuthorizedVoterSource:          authorizedVoterSource,
 		},
 		SolanaRPCClient:  v.solanaRPCClient,
 		RPCURL:           v.RPCAddress,
 		IsDryRunFailover: !params.NotADrill,
 		Hooks:            v.Hooks,
 		Rollback:         v.Rollback,
+		Notifier:         v.Notifier,
+		AdminRPC:         v.failoverAdminRPC(),
+		Preflight:        v.passivePreflight,
 		SkipTowerSync:    params.SkipTowerSync,
 		AutoConfirm:      params.AutoConfirm,
 		TLSConfig:        v.serverTLSConfig,
+		Schedule:         params.Schedule,
+		Pull:             params.Pull,
+		PullPeerName:     activePeer.Name,
+		PullPeerAddress:  activePeer.Address,
+		PullTLSConfig:    v.clientTLSConfig,
 		MonitorConfig: failover.MonitorConfig{
 			CreditSamples: failover.CreditSamplesConfig{
 				Count:            v.MonitorConfig.CreditSamples.Count,
 				Interval:         v.MonitorConfig.CreditSamples.Interval,
 				IntervalDuration: v.MonitorConfig.CreditSamples.IntervalDuration,
 			},
+			Votes: failover.VoteMonitorConfig{
+				Enabled:     v.MonitorConfig.Votes.Enabled,
+				WithinSlots: uint64(v.MonitorConfig.Votes.WithinSlots),
+				Duration:    v.MonitorConfig.Votes.DurationValue,
+			},
 		},
 	})
 	if err != nil {
@@ -827,10 +1179,25 @@ func (v *Validator) makePassive(params FailoverParams) (err error) {
 		return fmt.Errorf("tower file is empty: %s", v.TowerFile)
 	}
 
-	// select passive peer to connect to from declared peers
-	selectedPassivePeer, err := v.selectPassivePeer(params)
-	if err != nil {
-		return err
+	// select passive peer to connect to from declared peers - in pull mode the passive
+	// peer connects to us, so any configured peer (or only --to-peer if set) may do so
+	var selectedPassivePeer Peer
+	pullPeers := map[string]string{}
+	if params.Pull {
+		for name, peer := range v.Peers {
+			host, _, _ := net.SplitHostPort(peer.Address)
+			if params.ToPeer == "" || params.ToPeer == name || params.ToPeer == host {
+				pullPeers[name] = peer.Address
+			}
+		}
+		if len(pullPeers) == 0 {
+			return fmt.Errorf("--to-peer: no peer found matching %q (checked names and IP addresses)", params.ToPeer)
+		}
+	} else {
+		selectedPassivePeer, err = v.selectPeer(params, "Select a passive peer to failover to:")
+		if err != nil {
+			return err
+		}
 	}
 
 	// connect to the passive peer and follow its lead to handover as active
@@ -849,16 +1216,35 @@ func (v *Validator) makePassive(params FailoverParams) (err error) {
 			TowerFile:                      v.TowerFile,
 			TowerFileSizeBytes:             utils.FileSize(v.TowerFile),
 			SetIdentityCommand:             v.SetIdentityPassiveCommand,
+			SetIdentityCmd:                 v.SetIdentityPassiveCmd,
+			AdminRPCSocket:                 v.adminRPCSocket(),
+			Client:                         v.ClientProfile.Name,
 			ClientVersion:                  v.GossipNode.Version(),
 			ClientVersionRPC:               v.getLocalNodeVersion(),
 			SolanaValidatorFailoverVersion: pkgconstants.AppVersion,
 			RPCAddress:                     v.RPCAddress,
+			Cluster:                        v.Cluster,
+			SlotDuration:                   v.solanaRPCClient.GetSlotDuration(),
 		},
-		Hooks:     v.Hooks,
-		Rollback:  v.Rollback,
-		TLSConfig: v.clientTLSConfig,
+		SlotCommitment:    v.CatchUpCommitment,
+		Hooks:             v.Hooks,
+		Rollback:          v.Rollback,
+		Notifier:          v.Notifier,
+		AdminRPC:          v.failoverAdminRPC(),
+		TLSConfig:         v.clientTLSConfig,
+		Schedule:          params.Schedule,
+		WaitForWindow:     params.WaitForWindow,
+		Pull:              params.Pull,
+		PullPeers:         pullPeers,
+		ListenPort:        v.FailoverServerConfig.Port,
+		HeartbeatInterval: v.FailoverServerConfig.HeartbeatInterval,
+		StreamTimeout:     v.FailoverServerConfig.StreamTimeout,
+		ListenTLSConfig:   v.serverTLSConfig,
 	})
 	if err != nil {
+		if params.Pull {
+			return fmt.Errorf("failed waiting for passive peer: %w", err)
+		}
 		return fmt.Errorf("failed to connect to peer %s: %w", selectedPassivePeer.Name, err)
 	}
 
@@ -894,9 +1280,33 @@ func (v *Validator) waitUntilHealthy() (err error) {
 	return sp.Run()
 }
 
-// selectPassivePeer allows selection of a peer from the list of peers.
+// waitUntilCaughtUp waits until this node is within CatchUpMaxSlotLag slots of the cluster
+func (v *Validator) waitUntilCaughtUp() (err error) {
+	startTime := time.Now()
+	sp := spinner.New().
+		TitleStyle(style.SpinnerTitleStyle).
+		Title("waiting for validator to catch up with the cluster...")
+
+	sp.ActionWithErr(func(ctx context.Context) error {
+		for {
+			lag, err := v.checkCaughtUp(0)
+			if err != nil {
+				sp.Title(style.RenderWarningString(fmt.Sprintf("waiting for validator to catch up: %s", err)))
+				time.Sleep(2 * time.Second)
+				continue
+			}
+
+			sp.Title(style.RenderPinkString(fmt.Sprintf("validator has caught up (%s) - elapsed time %s", lag, time.Since(startTime).String())))
+			return nil
+		}
+	})
+
+	return sp.Run()
+}
+
+// selectPeer allows selection of a peer from the list of peers.
 // When params.ToPeer is set, it auto-selects by name or IP without an interactive prompt.
-func (v *Validator) selectPassivePeer(params FailoverParams) (selectedPeer Peer, err error) {
+func (v *Validator) selectPeer(params FailoverParams, title string) (selectedPeer Peer, err error) {
 	if params.ToPeer != "" {
 		// match by name first
 		if peer, ok := v.Peers[params.ToPeer]; ok {
@@ -934,7 +1344,7 @@ func (v *Validator) selectPassivePeer(params FailoverParams) (selectedPeer Peer,
 	var selectedPeerName string
 
 	err = huh.NewSelect[string]().
-		Title("Select a passive peer to failover to:").
+		Title(title).
 		Options(huhPeerOptions...).
 		Value(&selectedPeerName).
 		Run()
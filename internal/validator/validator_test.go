@@ -18,9 +18,13 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sol-strategies/solana-validator-failover/internal/agave"
+	"github.com/sol-strategies/solana-validator-failover/internal/constants"
 	"github.com/sol-strategies/solana-validator-failover/internal/hooks"
 	"github.com/sol-strategies/solana-validator-failover/internal/identities"
 	solanapkg "github.com/sol-strategies/solana-validator-failover/internal/solana"
+	"github.com/sol-strategies/solana-validator-failover/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -78,7 +82,7 @@ func (tv *TestValidator) NewFromConfig(cfg *Config) error {
 	defer tv.logger.Debug("configuration done")
 
 	// configure solana rpc clients all in one
-	err := tv.configureRPCClient(cfg.RPCAddress, cfg.Cluster, cfg.ClusterRPCURL, cfg.AverageSlotDuration)
+	err := tv.configureRPCClient(cfg.RPCAddress, cfg.RPCWSAddress, cfg.Cluster, cfg.ClusterRPCURL, cfg.AverageSlotDuration)
 	if err != nil {
 		return err
 	}
@@ -95,6 +99,12 @@ func (tv *TestValidator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
+	// client profile
+	err = tv.configureClient(cfg.Client, cfg.ClientConfig)
+	if err != nil {
+		return err
+	}
+
 	// configure identities
 	err = tv.configureIdentities(cfg.Identities)
 	if err != nil {
@@ -113,6 +123,12 @@ func (tv *TestValidator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
+	// set identity mode configure
+	err = tv.configureSetIdentityMode(cfg.Failover)
+	if err != nil {
+		return err
+	}
+
 	// configure hooks
 	err = tv.configureHooks(cfg.Failover)
 	if err != nil {
@@ -137,6 +153,12 @@ func (tv *TestValidator) NewFromConfig(cfg *Config) error {
 		return err
 	}
 
+	// how far behind a passive node may be and still become active
+	err = tv.configureCatchUp(cfg.Failover.CatchUp)
+	if err != nil {
+		return err
+	}
+
 	// get hostname - use overridden method
 	err = tv.configureHostname()
 	if err != nil {
@@ -203,7 +225,8 @@ func createDummyAgaveValidator(t *testing.T) string {
 func createTestValidator(t *testing.T) *TestValidator {
 	return &TestValidator{
 		Validator: &Validator{
-			logger: log.WithPrefix("validator"),
+			logger:        log.WithPrefix("validator"),
+			ClientProfile: ClientProfiles[constants.ClientTypeAgave],
 		},
 		mockPublicIP:     "192.168.1.100",
 		mockHostname:     "test-validator",
@@ -218,7 +241,7 @@ func createTestValidator(t *testing.T) *TestValidator {
 func TestConfigureRPCClient_Success(t *testing.T) {
 	validator := createTestValidator(t)
 
-	err := validator.configureRPCClient("http://localhost:8899", "testnet", "", "400ms")
+	err := validator.configureRPCClient("http://localhost:8899", "", "testnet", "", "400ms")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, validator.solanaRPCClient)
@@ -227,7 +250,7 @@ func TestConfigureRPCClient_Success(t *testing.T) {
 func TestConfigureRPCClient_EmptyCluster(t *testing.T) {
 	validator := createTestValidator(t)
 
-	err := validator.configureRPCClient("http://localhost:8899", "", "", "400ms")
+	err := validator.configureRPCClient("http://localhost:8899", "", "", "", "400ms")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cluster is required")
@@ -236,16 +259,25 @@ func TestConfigureRPCClient_EmptyCluster(t *testing.T) {
 func TestConfigureRPCClient_InvalidRPCAddress(t *testing.T) {
 	validator := createTestValidator(t)
 
-	err := validator.configureRPCClient("invalid-address", "testnet", "", "400ms")
+	err := validator.configureRPCClient("invalid-address", "", "testnet", "", "400ms")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid rpc address")
 }
 
+func TestConfigureRPCClient_InvalidRPCWSAddress(t *testing.T) {
+	validator := createTestValidator(t)
+
+	err := validator.configureRPCClient("http://localhost:8899", "ws://localhost", "testnet", "", "400ms")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rpc ws address")
+}
+
 func TestConfigureRPCClient_CustomCluster_Success(t *testing.T) {
 	validator := createTestValidator(t)
 
-	err := validator.configureRPCClient("http://localhost:8899", "custom-mainnet", "https://mainnet.custom.io", "400ms")
+	err := validator.configureRPCClient("http://localhost:8899", "", "custom-mainnet", "https://mainnet.custom.io", "400ms")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, validator.solanaRPCClient)
@@ -254,7 +286,7 @@ func TestConfigureRPCClient_CustomCluster_Success(t *testing.T) {
 func TestConfigureRPCClient_CustomCluster_MissingClusterRPCURL(t *testing.T) {
 	validator := createTestValidator(t)
 
-	err := validator.configureRPCClient("http://localhost:8899", "custom-mainnet", "", "400ms")
+	err := validator.configureRPCClient("http://localhost:8899", "", "custom-mainnet", "", "400ms")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cluster_rpc_url is required")
@@ -458,6 +490,85 @@ func TestConfigureMinimumTimeToLeaderSlot_InvalidDuration(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to parse minimum time to leader slot")
 }
 
+// ============================================================================
+// Tests for configureCatchUp
+// ============================================================================
+
+func TestConfigureCatchUp_Defaults(t *testing.T) {
+	validator := createTestValidator(t)
+
+	err := validator.configureCatchUp(CatchUpConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), validator.CatchUpMaxSlotLag)
+	assert.Equal(t, rpc.CommitmentConfirmed, validator.CatchUpCommitment)
+}
+
+func TestConfigureCatchUp_Success(t *testing.T) {
+	validator := createTestValidator(t)
+
+	err := validator.configureCatchUp(CatchUpConfig{MaxSlotLag: 32, Commitment: "processed"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(32), validator.CatchUpMaxSlotLag)
+	assert.Equal(t, rpc.CommitmentProcessed, validator.CatchUpCommitment)
+}
+
+func TestConfigureCatchUp_Invalid(t *testing.T) {
+	validator := createTestValidator(t)
+
+	err := validator.configureCatchUp(CatchUpConfig{MaxSlotLag: -1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max_slot_lag")
+
+	err = validator.configureCatchUp(CatchUpConfig{Commitment: "finalized"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failover.catch_up.commitment")
+}
+
+// ============================================================================
+// Tests for configureServer
+// ============================================================================
+
+func TestConfigureServer_VotesMonitor(t *testing.T) {
+	validator := createTestValidator(t)
+
+	err := validator.configureServer(ServerConfig{Port: 9898}, MonitorConfig{
+		CreditSamples: CreditSamplesConfig{Count: 5, Interval: "5s"},
+		Votes:         VotesMonitorConfig{Enabled: true, WithinSlots: 64, Duration: "1m"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, validator.MonitorConfig.CreditSamples.IntervalDuration)
+	assert.Equal(t, time.Minute, validator.MonitorConfig.Votes.DurationValue)
+}
+
+func TestConfigureServer_VotesMonitorInvalid(t *testing.T) {
+	validator := createTestValidator(t)
+	creditSamples := CreditSamplesConfig{Count: 5, Interval: "5s"}
+
+	err := validator.configureServer(ServerConfig{}, MonitorConfig{
+		CreditSamples: creditSamples,
+		Votes:         VotesMonitorConfig{Enabled: true, WithinSlots: 0, Duration: "1m"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "within_slots")
+
+	err = validator.configureServer(ServerConfig{}, MonitorConfig{
+		CreditSamples: creditSamples,
+		Votes:         VotesMonitorConfig{Enabled: true, WithinSlots: 64, Duration: "soon"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid votes duration")
+
+	// disabled monitoring isn't validated
+	err = validator.configureServer(ServerConfig{}, MonitorConfig{
+		CreditSamples: creditSamples,
+		Votes:         VotesMonitorConfig{Enabled: false},
+	})
+	assert.NoError(t, err)
+}
+
 // ============================================================================
 // Tests for configurePublicIP
 // ============================================================================
@@ -523,6 +634,117 @@ func TestConfigureGossipNode_NodeNotFound(t *testing.T) {
 // Tests for configureHooks
 // ============================================================================
 
+// serveAdminRPCContactInfo serves an admin.rpc socket in a new ledger dir answering contactInfo with
+// the given identity, returning the ledger dir
+func serveAdminRPCContactInfo(t *testing.T, identity string) string {
+	t.Helper()
+	// unix socket paths are limited to ~100 bytes, so avoid t.TempDir's long paths
+	ledgerDir, err := os.MkdirTemp("", "ledger")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(ledgerDir) })
+
+	listener, err := net.Listen("unix", filepath.Join(ledgerDir, agave.AdminRPCSocketName))
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			var req struct {
+				ID uint64 `json:"id"`
+			}
+			if json.NewDecoder(conn).Decode(&req) == nil {
+				_ = json.NewEncoder(conn).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": map[string]any{"id": identity}})
+			}
+			conn.Close()
+		}
+	}()
+	return ledgerDir
+}
+
+func configureTestIdentities(t *testing.T, validator *TestValidator) {
+	t.Helper()
+	tempDir := t.TempDir()
+	require.NoError(t, validator.configureIdentities(identities.Config{
+		Active:  createTestKeyFile(t, tempDir, "active-key.json"),
+		Passive: createTestKeyFile(t, tempDir, "passive-key.json"),
+	}))
+}
+
+func TestConfigureSetIdentityMode_DefaultsToCommand(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdentityMode(FailoverConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.SetIdentityModeCommand, validator.SetIdentityMode)
+	assert.Nil(t, validator.failoverAdminRPC())
+}
+
+func TestConfigureSetIdentityMode_InvalidMode(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: "ssh"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid failover.set_identity_mode")
+}
+
+func TestConfigureSetIdentityMode_AdminRPCRequiresKeypairs(t *testing.T) {
+	validator := createTestValidator(t)
+	require.NoError(t, validator.configureIdentities(identities.Config{
+		ActivePubkey:  solana.NewWallet().PublicKey().String(),
+		PassivePubkey: solana.NewWallet().PublicKey().String(),
+	}))
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: constants.SetIdentityModeAdminRPC})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "keypair files")
+}
+
+func TestConfigureSetIdentityMode_AdminRPC(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.LedgerDir = serveAdminRPCContactInfo(t, validator.Identities.Passive.PubKey())
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: constants.SetIdentityModeAdminRPC})
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.SetIdentityModeAdminRPC, validator.SetIdentityMode)
+	assert.NotNil(t, validator.failoverAdminRPC())
+	assert.Equal(t, filepath.Join(validator.LedgerDir, agave.AdminRPCSocketName), validator.adminRPCSocket())
+}
+
+func TestConfigureSetIdentityMode_AdminRPCUnavailableFallsBack(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.LedgerDir = t.TempDir()
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: constants.SetIdentityModeAdminRPC})
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.SetIdentityModeCommand, validator.SetIdentityMode)
+	assert.Nil(t, validator.failoverAdminRPC())
+	assert.Empty(t, validator.adminRPCSocket())
+}
+
+func TestConfigureSetIdentityMode_AdminRPCUnknownIdentity(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.LedgerDir = serveAdminRPCContactInfo(t, solana.NewWallet().PublicKey().String())
+
+	err := validator.configureSetIdentityMode(FailoverConfig{SetIdentityMode: constants.SetIdentityModeAdminRPC})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "neither the active")
+}
+
 func TestConfigureHooks_Success(t *testing.T) {
 	validator := createTestValidator(t)
 
@@ -541,6 +763,272 @@ func TestConfigureHooks_Success(t *testing.T) {
 	assert.Equal(t, "test-hook", validator.Hooks.Pre.WhenActive[0].Name)
 }
 
+func TestConfigureHooks_ParsesTimeoutAndBackoff(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{
+		Hooks: hooks.FailoverHooks{
+			Pre: hooks.PreHooks{WhenPassive: []hooks.Hook{{Name: "lb", Command: "echo", Timeout: "30s", Retries: 2, RetryBackoff: "2s"}}},
+		},
+	}
+
+	err := validator.configureHooks(failoverConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, validator.Hooks.Pre.WhenPassive[0].TimeoutDuration)
+	assert.Equal(t, 2*time.Second, validator.Hooks.Pre.WhenPassive[0].RetryBackoffDuration)
+}
+
+func TestConfigureHooks_InvalidTimeout(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{
+		Hooks: hooks.FailoverHooks{
+			Post: hooks.PostHooks{WhenActive: []hooks.Hook{{Name: "lb", Command: "echo", Timeout: "soon"}}},
+		},
+	}
+
+	err := validator.configureHooks(failoverConfig)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "post.when_active")
+	assert.Contains(t, err.Error(), "invalid timeout")
+}
+
+func TestConfigureHooks_UnknownUser(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{
+		Hooks: hooks.FailoverHooks{
+			Post: hooks.PostHooks{WhenPassive: []hooks.Hook{{Name: "notify", Command: "echo", User: "no-such-user-here"}}},
+		},
+	}
+
+	err := validator.configureHooks(failoverConfig)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "post.when_passive")
+	assert.Contains(t, err.Error(), `user "no-such-user-here" does not exist`)
+}
+
+func TestConfigureHooks_ReportsTemplateAndCommandProblems(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{
+		Hooks: hooks.FailoverHooks{
+			Pre:  hooks.PreHooks{WhenActive: []hooks.Hook{{Name: "drain", Command: "no-such-command-here", MustSucceed: true}}},
+			Post: hooks.PostHooks{WhenPassive: []hooks.Hook{{Name: "notify", Command: "echo", Args: []string{"{{ .ThisNodeNmae }}"}}}},
+		},
+	}
+
+	err := validator.configureHooks(failoverConfig)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "pre.when_active: hook drain: command no-such-command-here is not runnable")
+	assert.Contains(t, err.Error(), "post.when_passive: hook notify: failed to execute arg[0] template")
+}
+
+func TestConfigureRollback_ReportsHookProblems(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{}
+	failoverConfig.Rollback.ToActive.Hooks.Pre = hooks.Hooks{{Name: "page", Command: "no-such-command-here"}}
+	failoverConfig.Rollback.ToPassive.Hooks.Post = hooks.Hooks{{Name: "notify", Command: "echo", When: "{{ .Nope }}"}}
+
+	err := validator.configureRollback(failoverConfig)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rollback.to_active.hooks.pre: hook page: command no-such-command-here is not runnable")
+	assert.Contains(t, err.Error(), "invalid rollback.to_passive.hooks.post: hook notify: invalid when")
+}
+
+func TestConfigureSetIdentityCommands_TemplateKeepsPathsWhole(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.Bin = "agave-validator"
+	validator.LedgerDir = "/mnt/my ledger"
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActiveCmdTemplate:  "sudo -u sol {{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Active.KeyFile }} --require-tower",
+		SetIdentityPassiveCmdTemplate: `{{ .Bin }} --ledger "{{ .LedgerDir }}" set-identity {{ .Identities.Passive.KeyFile }}{{ if false }}--never{{ end }}`,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"sudo", "-u", "sol", "agave-validator", "--ledger", "/mnt/my ledger", "set-identity", validator.Identities.Active.KeyFile, "--require-tower"},
+		validator.SetIdentityActiveCmd.Argv)
+	assert.Equal(t,
+		[]string{"agave-validator", "--ledger", "/mnt/my ledger", "set-identity", validator.Identities.Passive.KeyFile},
+		validator.SetIdentityPassiveCmd.Argv)
+	assert.Contains(t, validator.SetIdentityActiveCommand, "--ledger '/mnt/my ledger'")
+}
+
+func TestConfigureSetIdentityCommands_TemplateBlockSpanningWords(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.Bin = "agave-validator"
+	validator.LedgerDir = "/mnt/my ledger"
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActiveCmdTemplate:  `{{ .Bin }} --ledger '{{ .LedgerDir }}' set-identity {{ .Identities.Active.KeyFile }}{{ if true }} --require-tower{{ end }}`,
+		SetIdentityPassiveCmdTemplate: "{{ .Bin }} set-identity {{ .Identities.Passive.KeyFile }}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"agave-validator", "--ledger", "/mnt/my ledger", "set-identity", validator.Identities.Active.KeyFile, "--require-tower"},
+		validator.SetIdentityActiveCmd.Argv)
+}
+
+func TestConfigureSetIdentityCommands_Argv(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.Bin = "agave-validator"
+	validator.LedgerDir = "/mnt/ledger"
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActive: hooks.CommandConfig{
+			Cmd:     "{{ .Bin }}",
+			Args:    []string{"--ledger", "{{ .LedgerDir }}", "set-identity", "{{ .Identities.Active.KeyFile }}", "--require-tower"},
+			Env:     map[string]string{"RUST_LOG": "warn"},
+			Timeout: "30s",
+		},
+		// the template still applies to a side without argv config
+		SetIdentityPassiveCmdTemplate: "{{ .Bin }} --ledger {{ .LedgerDir }} set-identity {{ .Identities.Passive.KeyFile }}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"agave-validator", "--ledger", "/mnt/ledger", "set-identity", validator.Identities.Active.KeyFile, "--require-tower"},
+		validator.SetIdentityActiveCmd.Argv)
+	assert.Equal(t, map[string]string{"RUST_LOG": "warn"}, validator.SetIdentityActiveCmd.Env)
+	assert.Equal(t, 30*time.Second, validator.SetIdentityActiveCmd.Timeout)
+	assert.Equal(t, "agave-validator", validator.SetIdentityPassiveCmd.Argv[0])
+}
+
+func TestConfigureSetIdentityCommands_DefaultTimeout(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityTimeout:            "20s",
+		SetIdentityActive:             hooks.CommandConfig{Cmd: "agave-validator", Timeout: "5s"},
+		SetIdentityPassiveCmdTemplate: "agave-validator set-identity {{ .Identities.Passive.KeyFile }}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, validator.SetIdentityActiveCmd.Timeout)
+	assert.Equal(t, 20*time.Second, validator.SetIdentityPassiveCmd.Timeout)
+}
+
+func TestConfigureSetIdentityCommands_InvalidDefaultTimeout(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{SetIdentityTimeout: "-1s"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid failover.set_identity_timeout")
+}
+
+func TestConfigureSetIdentityCommands_InvalidTimeout(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActive: hooks.CommandConfig{Cmd: "agave-validator", Timeout: "soon"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid set_identity_active.timeout")
+}
+
+func TestConfigureSetIdentityCommands_UnterminatedQuote(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+
+	err := validator.configureSetIdenttiyCommands(FailoverConfig{
+		SetIdentityActiveCmdTemplate: "agave-validator set-identity '{{ .Identities.Active.KeyFile }}",
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to split set_identity_active_cmd_template")
+}
+
+func TestConfigureHooksAndRollback_ReportsEveryProblem(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{
+		Hooks: hooks.FailoverHooks{
+			Pre: hooks.PreHooks{WhenActive: []hooks.Hook{
+				{Name: "drain", Command: "echo", Timeout: "soon"},
+				{Name: "notify", Command: "echo", Args: []string{"{{ .ThisNodeNmae }}"}},
+			}},
+		},
+	}
+	failoverConfig.Rollback.ToPassive.Hooks.Post = hooks.Hooks{{Name: "page", Command: "no-such-command-here"}}
+
+	err := validator.configureHooksAndRollback(failoverConfig)
+
+	assert.Error(t, err)
+	// a configure error in one hook doesn't hide template problems in another, or other sections
+	assert.Contains(t, err.Error(), "pre.when_active: hook drain: invalid timeout")
+	assert.Contains(t, err.Error(), "pre.when_active: hook notify: failed to execute arg[0] template")
+	assert.Contains(t, err.Error(), "invalid rollback.to_passive.hooks.post: hook page: command no-such-command-here is not runnable")
+}
+
+func TestConfigureRollback_Commands(t *testing.T) {
+	validator := createTestValidator(t)
+	configureTestIdentities(t, validator)
+	validator.Bin = "agave-validator"
+	validator.LedgerDir = "/mnt/ledger"
+	validator.SetIdentityPassiveCmd = utils.Command{Argv: []string{"agave-validator", "set-identity", "passive.json"}}
+
+	failoverConfig := FailoverConfig{}
+	failoverConfig.Rollback.ToActive.Command = hooks.CommandConfig{
+		Cmd:  "{{ .Bin }}",
+		Args: []string{"--ledger", "{{ .LedgerDir }}", "set-identity", "{{ .Identities.Active.KeyFile }}"},
+	}
+
+	err := validator.configureRollback(failoverConfig)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"agave-validator", "--ledger", "/mnt/ledger", "set-identity", validator.Identities.Active.KeyFile},
+		validator.Rollback.ToActive.ResolvedCmd.Argv)
+	assert.Equal(t, validator.SetIdentityPassiveCmd, validator.Rollback.ToPassive.ResolvedCmd)
+}
+
+func TestConfigureRollback_VerifyHandover(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{}
+	failoverConfig.Rollback.Enabled = true
+	failoverConfig.Rollback.VerifyHandover = hooks.VerifyHandoverConfig{Enabled: true, WithinSlots: 64, Timeout: "2m"}
+
+	err := validator.configureRollback(failoverConfig)
+
+	require.NoError(t, err)
+	assert.True(t, validator.Rollback.VerifyHandover.Enabled)
+	assert.Equal(t, 64, validator.Rollback.VerifyHandover.WithinSlots)
+	assert.Equal(t, 2*time.Minute, validator.Rollback.VerifyHandover.TimeoutDuration)
+}
+
+func TestConfigureRollback_VerifyHandoverInvalid(t *testing.T) {
+	validator := createTestValidator(t)
+
+	failoverConfig := FailoverConfig{}
+	failoverConfig.Rollback.VerifyHandover = hooks.VerifyHandoverConfig{Enabled: true, WithinSlots: 0, Timeout: "2m"}
+	err := validator.configureRollback(failoverConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rollback.verify_handover.within_slots")
+
+	failoverConfig.Rollback.VerifyHandover = hooks.VerifyHandoverConfig{Enabled: true, WithinSlots: 64, Timeout: "later"}
+	err = validator.configureRollback(failoverConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rollback.verify_handover.timeout")
+}
+
 // ============================================================================
 // Legacy tests for backward compatibility
 // ============================================================================
@@ -1027,3 +1515,64 @@ func TestConfigureTLS_Success(t *testing.T) {
 	assert.NotNil(t, v.serverTLSConfig)
 	assert.NotNil(t, v.clientTLSConfig)
 }
+
+func TestValidator_Status(t *testing.T) {
+	activeKey := solana.NewWallet().PrivateKey
+	passiveKey := solana.NewWallet().PrivateKey
+
+	slotDuration := solanapkg.SlotDuration{Duration: 480 * time.Millisecond, Source: solanapkg.SlotDurationSourceMeasured, Samples: 30}
+	mockClient := solanapkg.NewMockClient().
+		WithGetEpochInfo(func() (*rpc.GetEpochInfoResult, error) {
+			return &rpc.GetEpochInfoResult{Epoch: 7, AbsoluteSlot: 1234}, nil
+		}).
+		WithGetTimeToNextLeaderSlotForPubkey(func(pubkey solana.PublicKey) (bool, time.Duration, error) {
+			assert.Equal(t, activeKey.PublicKey(), pubkey)
+			return true, 2 * time.Minute, nil
+		}).
+		WithGetSlotDuration(func() solanapkg.SlotDuration { return slotDuration })
+
+	v := &Validator{
+		Hostname: "london",
+		Identities: &identities.Identities{
+			Active:  &identities.Identity{Key: activeKey},
+			Passive: &identities.Identity{Key: passiveKey},
+		},
+		GossipNode:              solanapkg.NewMockNode(passiveKey.PublicKey(), "2.1.14"),
+		MinimumTimeToLeaderSlot: 5 * time.Minute,
+		solanaRPCClient:         mockClient,
+		logger:                  log.WithPrefix("validator"),
+	}
+
+	status, err := v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, "passive", status.Role)
+	assert.Equal(t, "ok", status.Health)
+	assert.Equal(t, uint64(1234), status.CurrentSlot)
+	assert.Equal(t, uint64(7), status.Epoch)
+	assert.True(t, status.IsOnLeaderSchedule)
+	assert.Equal(t, 2*time.Minute, status.TimeToNextLeaderSlot)
+	assert.NoError(t, status.LeaderScheduleError)
+	assert.Equal(t, slotDuration, status.SlotDuration)
+	require.NotNil(t, status.VoteAccount)
+	assert.Equal(t, activeKey.PublicKey().String(), status.VoteAccount.AuthorizedVoter)
+	assert.Equal(t, "active identity", status.AuthorizedVoterSource)
+	assert.NoError(t, status.VoteAccountError)
+}
+
+func TestValidator_Status_EpochInfoError(t *testing.T) {
+	activeKey := solana.NewWallet().PrivateKey
+	v := &Validator{
+		Identities: &identities.Identities{
+			Active:  &identities.Identity{Key: activeKey},
+			Passive: &identities.Identity{Key: solana.NewWallet().PrivateKey},
+		},
+		GossipNode: solanapkg.NewMockNode(activeKey.PublicKey(), "2.1.14"),
+		solanaRPCClient: solanapkg.NewMockClient().WithGetEpochInfo(func() (*rpc.GetEpochInfoResult, error) {
+			return nil, errors.New("rpc down")
+		}),
+		logger: log.WithPrefix("validator"),
+	}
+
+	_, err := v.Status()
+	assert.ErrorContains(t, err, "failed to get epoch info")
+}
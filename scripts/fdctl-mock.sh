@@ -21,9 +21,9 @@ while [[ $# -gt 0 ]]; do
         set-identity)
             shift
             # Notify mock-solana of the identity change if MOCK_SOLANA_URL and VALIDATOR_NAME are set.
-            # --force means setting to active; absence means passive.
+            # --require-tower means setting to active; absence means passive.
             if [ -n "${MOCK_SOLANA_URL:-}" ] && [ -n "${VALIDATOR_NAME:-}" ]; then
-                if echo "$@" | grep -q -- "--force"; then
+                if echo "$@" | grep -q -- "--require-tower"; then
                     # Check if this set-identity-to-active call should be simulated as failing.
                     FAIL_CHECK=$(curl -sf "${MOCK_SOLANA_URL}/fail-check?validator=${VALIDATOR_NAME}&action=set_active" 2>/dev/null || echo '{"fail":false}')
                     if echo "$FAIL_CHECK" | grep -q '"fail":true'; then